@@ -20,10 +20,16 @@ var (
 	flagAddPR       string
 	flagSyncAll     bool
 	flagSyncIgnored bool
+	flagAddPath     string
+	flagAddRemote   string
+	flagAddTrack    bool
+	flagAddDetach   bool
+	flagAddCarry    bool
 	// Negation flags (--no-*)
 	flagNoOpen        bool
 	flagNoSync        bool
 	flagNoSyncIgnored bool
+	flagNoTrack       bool
 )
 
 var addCmd = &cobra.Command{
@@ -35,13 +41,28 @@ var addCmd = &cobra.Command{
 The worktree will be created in a sibling directory with the naming convention:
   <repo-name>-<branch-suffix>
 
+The branch suffix replaces characters that are unsafe in directory names with
+'-' and is shortened with a hash when it is longer than 64 characters. Use
+worktree.path in the config file to change the layout, or --path to choose
+the directory for a single worktree.
+
+Branches that don't exist locally are fetched from the default remote
+('git config gw.remote', remote in the config file, or origin). Use
+--remote or a remote-qualified name such as upstream/feature/x to fetch from
+another remote. Branches fetched from a remote track their remote branch.
+
+With --detach, the worktree is checked out at a tag, commit or other ref with a
+detached HEAD instead of a branch. The directory is named from the tag, or from
+the short commit hash for any other ref, and the worktree can be found by that
+name, the tag or the commit hash.
+
 Hooks:
   You can configure project-specific hooks in gw.yaml at the repository root.
   Available hooks: pre_add, post_add
   
   Hooks receive these environment variables:
     - GW_WORKTREE_PATH: Path to the worktree
-    - GW_BRANCH: Branch name
+    - GW_BRANCH: Branch name (empty for detached worktrees)
     - GW_REPO_ROOT: Repository root path
   
   Example gw.yaml:
@@ -64,9 +85,28 @@ Examples:
     Creates a new branch from origin/develop and worktree
     Note: The 'from' argument only applies when creating a new branch (-b flag)
 
+  gw add upstream/feature/x
+    Fetches feature/x from the upstream remote and creates a worktree for it
+
+  gw add --remote upstream feature/x
+    Same as above
+
+  gw add -b --track feature/new
+    Creates a new branch whose upstream is origin/feature/new, or shows the
+    'git push -u' command that publishes it when it isn't on origin yet
+
   gw add --pr 123
     Creates a worktree for PR #123
 
+  gw add --detach v1.2.0
+    Creates ../ex-repo-v1.2.0/ with a detached HEAD at the tag v1.2.0
+
+  gw add --detach 1a2b3c4d
+    Creates ../ex-repo-1a2b3c4/ with a detached HEAD at the commit
+
+  gw add --path ../hoge feature/hoge
+    Creates the worktree in ../hoge instead
+
   gw add
     Interactive branch selection with fzf`,
 	Args: cobra.MaximumNArgs(2),
@@ -81,12 +121,18 @@ func init() {
 	addCmd.Flags().StringVarP(&flagAddPR, "pr", "p", "", "PR number or URL to create worktree for")
 	addCmd.Flags().BoolVar(&flagAddOpen, "open", false, "Open worktree in editor after creation")
 	addCmd.Flags().StringVarP(&flagEditor, "editor", "e", "", "Editor command to use (e.g., code, vim)")
-	addCmd.Flags().BoolVarP(&flagSyncAll, "sync", "s", false, "Sync all changed files from main worktree")
+	addCmd.Flags().BoolVarP(&flagSyncAll, "sync", "s", false, "Replicate the staged, unstaged and untracked changes of the main worktree")
 	addCmd.Flags().BoolVarP(&flagSyncIgnored, "sync-ignored", "i", false, "Sync gitignored files from main worktree")
+	addCmd.Flags().StringVar(&flagAddPath, "path", "", "Create the worktree at this path instead of the configured location")
+	addCmd.Flags().StringVar(&flagAddRemote, "remote", "", "Remote to fetch the branch from (overrides gw.remote and remote in the config file)")
+	addCmd.Flags().BoolVar(&flagAddTrack, "track", false, "Set the upstream of a new branch (-b) to <remote>/<branch>")
+	addCmd.Flags().BoolVarP(&flagAddDetach, "detach", "d", false, "Check out a tag, commit or ref with a detached HEAD instead of a branch")
+	addCmd.Flags().BoolVar(&flagAddCarry, "carry", false, "Move the uncommitted changes of the current worktree into the new worktree")
 	// Negation flags
 	addCmd.Flags().BoolVar(&flagNoOpen, "no-open", false, "Force disable opening worktree in editor (overrides config and --open)")
 	addCmd.Flags().BoolVar(&flagNoSync, "no-sync", false, "Force disable syncing changed files (overrides config and --sync)")
 	addCmd.Flags().BoolVar(&flagNoSyncIgnored, "no-sync-ignored", false, "Force disable syncing gitignored files (overrides config and --sync-ignored)")
+	addCmd.Flags().BoolVar(&flagNoTrack, "no-track", false, "Force disable setting the upstream of new branches (overrides config and --track)")
 	rootCmd.AddCommand(addCmd)
 }
 
@@ -103,6 +149,9 @@ func runAddWithSelector(cmd *cobra.Command, args []string, selector fzf.Selector
 	if flagSyncAll && flagSyncIgnored {
 		return fmt.Errorf("cannot use --sync and --sync-ignored together")
 	}
+	if flagAddCarry && flagSyncAll {
+		return fmt.Errorf("cannot use --carry and --sync together")
+	}
 
 	// Validate --no-* flag conflicts
 	if flagAddOpen && flagNoOpen {
@@ -114,6 +163,23 @@ func runAddWithSelector(cmd *cobra.Command, args []string, selector fzf.Selector
 	if flagSyncIgnored && flagNoSyncIgnored {
 		return fmt.Errorf("cannot use --sync-ignored and --no-sync-ignored together")
 	}
+	if flagAddTrack && flagNoTrack {
+		return fmt.Errorf("cannot use --track and --no-track together")
+	}
+	if flagAddTrack && !flagAddBranch {
+		return fmt.Errorf("--track can only be used with --branch")
+	}
+	if flagAddDetach {
+		if flagAddBranch || flagAddPR != "" {
+			return fmt.Errorf("cannot use --detach with --branch or --pr")
+		}
+		if flagAddRemote != "" {
+			return fmt.Errorf("cannot use --detach and --remote together")
+		}
+		if len(args) != 1 {
+			return fmt.Errorf("--detach requires a tag, commit or ref")
+		}
+	}
 
 	// Merge config with flags (flags take precedence)
 	var openFlagPtr *bool
@@ -134,6 +200,10 @@ func runAddWithSelector(cmd *cobra.Command, args []string, selector fzf.Selector
 	if cmd.Flags().Changed("sync-ignored") {
 		syncIgnoredFlagPtr = &flagSyncIgnored
 	}
+	var trackFlagPtr *bool
+	if cmd.Flags().Changed("track") {
+		trackFlagPtr = &flagAddTrack
+	}
 
 	// Extract from argument (second argument) - this takes highest priority
 	var from string
@@ -152,12 +222,14 @@ func runAddWithSelector(cmd *cobra.Command, args []string, selector fzf.Selector
 		syncFlagPtr,
 		syncIgnoredFlagPtr,
 		fromFlagPtr,
+		trackFlagPtr,
 		flagNoOpen,
 		flagNoSync,
 		flagNoSyncIgnored,
 		false,
 		false,
 		false,
+		flagNoTrack,
 	)
 
 	// Validate config
@@ -170,10 +242,54 @@ func runAddWithSelector(cmd *cobra.Command, args []string, selector fzf.Selector
 		return fmt.Errorf("failed to get repository name: %w", err)
 	}
 
+	// Get editor command from merged config
+	editorCmd := mergedConfig.GetEditor()
+
+	// Determine sync mode
+	syncMode := determineSyncMode(mergedConfig.Add.Sync, mergedConfig.Add.SyncIgnored, flagSyncAll, flagSyncIgnored)
+
+	// Find the worktree whose changes are carried before creating anything
+	var carryFrom string
+	if flagAddCarry {
+		source, err := findCarrySource()
+		if err != nil {
+			return err
+		}
+		carryFrom = source.Path
+		// The carried changes take the place of the main worktree's changes
+		if syncMode == syncAll {
+			syncMode = syncNone
+		}
+	}
+
+	if flagAddDetach {
+		name, commit, err := resolveDetachedRef(args[0])
+		if err != nil {
+			return err
+		}
+		existing, err := checkExistingDetachedWorktree(name, commit)
+		if err != nil {
+			return err
+		}
+		if existing != nil {
+			fmt.Printf("Worktree already exists: %s\n", existing.Path)
+			return carryIntoExisting(carryFrom, existing)
+		}
+		return createWorktree(repoName, name, &createWorktreeOptions{
+			detach:     true,
+			from:       commit,
+			openEditor: editorCmd,
+			syncMode:   syncMode,
+			customPath: flagAddPath,
+			carryFrom:  carryFrom,
+		})
+	}
+
 	// Create options
 	opts := &addOptions{
 		createBranch: flagAddBranch,
 		prIdentifier: flagAddPR,
+		remote:       flagAddRemote,
 		selector:     selector,
 	}
 
@@ -191,6 +307,13 @@ func runAddWithSelector(cmd *cobra.Command, args []string, selector fzf.Selector
 		return nil // User cancelled
 	}
 
+	// Split remote-qualified input such as upstream/feature/x
+	fromPR := opts.prIdentifier != ""
+	remote, branch, err := resolveRemoteBranch(branch, flagAddRemote, flagAddBranch || fromPR)
+	if err != nil {
+		return err
+	}
+
 	// Check if worktree already exists
 	existing, err := checkExistingWorktree(branch)
 	if err != nil {
@@ -198,21 +321,31 @@ func runAddWithSelector(cmd *cobra.Command, args []string, selector fzf.Selector
 	}
 	if existing != nil {
 		fmt.Printf("Worktree already exists: %s\n", existing.Path)
-		return nil
+		return carryIntoExisting(carryFrom, existing)
 	}
 
 	// Ensure branch exists or can be created
-	fromPR := opts.prIdentifier != ""
-	if err := ensureBranchExists(branch, flagAddBranch, fromPR); err != nil {
+	if err := ensureBranchExists(remote, branch, flagAddBranch, fromPR); err != nil {
 		return err
 	}
 
-	// Get editor command from merged config
-	editorCmd := mergedConfig.GetEditor()
-
-	// Determine sync mode
-	syncMode := determineSyncMode(mergedConfig.Add.Sync, mergedConfig.Add.SyncIgnored, flagSyncAll, flagSyncIgnored)
-
 	// Create the worktree
-	return createWorktree(repoName, branch, flagAddBranch, from, editorCmd, syncMode)
+	createOpts := &createWorktreeOptions{
+		createBranch: flagAddBranch,
+		from:         from,
+		pr:           opts.prIdentifier,
+		openEditor:   editorCmd,
+		syncMode:     syncMode,
+		customPath:   flagAddPath,
+		carryFrom:    carryFrom,
+	}
+	if err := createWorktree(repoName, branch, createOpts); err != nil {
+		return err
+	}
+
+	// Branches fetched from a remote already track it; new branches only on request
+	if flagAddBranch && !fromPR && mergedConfig.Add.PushUpstream {
+		setPushUpstream(branch, remote)
+	}
+	return nil
 }
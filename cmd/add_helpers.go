@@ -2,16 +2,19 @@ package cmd
 
 import (
 	"fmt"
-	"io"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"slices"
+	"time"
 
 	"github.com/t98o84/gw/internal/config"
 	"github.com/t98o84/gw/internal/errors"
+	"github.com/t98o84/gw/internal/filesync"
 	"github.com/t98o84/gw/internal/fzf"
 	"github.com/t98o84/gw/internal/git"
 	"github.com/t98o84/gw/internal/github"
+	"github.com/t98o84/gw/internal/metadata"
 )
 
 // syncMode represents the synchronization mode for worktree creation
@@ -29,25 +32,37 @@ var (
 	mockListBranches       func() ([]string, error)
 	mockFindWorktree       func(branch string) (*git.Worktree, error)
 	mockBranchExists       func(branch string) (bool, error)
-	mockRemoteBranchExists func(branch string) (bool, error)
-	mockFetchBranch        func(branch string) error
+	mockRemoteBranchExists func(remote, branch string) (bool, error)
+	mockFetchBranch        func(remote, branch string) error
+	mockListRemotes        func() ([]string, error)
+	mockSetPushUpstream    func(branch, remote string) (bool, error)
 	mockWorktreePath       func(repoName, branch string) (string, error)
+	mockListWorktrees      func() ([]git.Worktree, error)
 	mockAdd                func(path string, branch string, createBranch bool, from string) error
+	mockAddDetached        func(path string, ref string) error
+	mockDetachedName       func(ref string) (string, string, error)
 	mockOpenInEditor       func(editor, path string) error
+	mockMetadataStore      func() (*metadata.Store, error)
 )
 
 // addOptions contains options for worktree creation
 type addOptions struct {
 	createBranch bool
 	prIdentifier string
-	selector     fzf.Selector
+	// remote is the remote given with --remote (empty means not specified)
+	remote   string
+	selector fzf.Selector
 }
 
 // determineBranch determines which branch to use based on args and options
 func determineBranch(args []string, opts *addOptions, repoName string) (string, error) {
 	// Handle PR flag
 	if opts.prIdentifier != "" {
-		branch, err := getBranchFromPR(opts.prIdentifier, repoName)
+		remote := opts.remote
+		if remote == "" {
+			remote = git.DefaultRemote()
+		}
+		branch, err := getBranchFromPR(opts.prIdentifier, repoName, remote)
 		if err != nil {
 			return "", err
 		}
@@ -68,11 +83,11 @@ func determineBranch(args []string, opts *addOptions, repoName string) (string,
 }
 
 // getBranchFromPR retrieves branch name from PR identifier
-func getBranchFromPR(prIdentifier, repoName string) (string, error) {
+func getBranchFromPR(prIdentifier, repoName, remote string) (string, error) {
 	if mockGetPRBranch != nil {
 		return mockGetPRBranch(prIdentifier, repoName)
 	}
-	branch, err := github.GetPRBranch(prIdentifier, repoName)
+	branch, err := github.GetPRBranch(prIdentifier, repoName, remote)
 	if err != nil {
 		return "", fmt.Errorf("failed to get PR branch: %w", err)
 	}
@@ -112,24 +127,94 @@ func checkExistingWorktree(branch string) (*git.Worktree, error) {
 	if err != nil {
 		return nil, fmt.Errorf("failed to check existing worktree: %w", err)
 	}
+	if existing != nil && existing.Branch != branch {
+		// Matched by directory name only: another branch maps to the same directory,
+		// which checkWorktreePathAvailable reports when creating the worktree
+		return nil, nil
+	}
+	return existing, nil
+}
+
+// checkExistingDetachedWorktree returns the detached worktree named name if it is
+// already checked out at commit
+func checkExistingDetachedWorktree(name, commit string) (*git.Worktree, error) {
+	var existing *git.Worktree
+	var err error
+	if mockFindWorktree != nil {
+		existing, err = mockFindWorktree(name)
+	} else {
+		existing, err = git.FindWorktree(name)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to check existing worktree: %w", err)
+	}
+	if existing == nil || !existing.IsDetached || existing.Commit != commit {
+		return nil, nil
+	}
 	return existing, nil
 }
 
-// ensureBranchExists checks and fetches branch if necessary
-func ensureBranchExists(branch string, createBranch bool, fromPR bool) error {
+// resolveRemoteBranch determines the remote that a branch is fetched from when it
+// doesn't exist locally. A remote-qualified branch such as "upstream/feature/x" selects
+// that remote and is stripped to the branch name, unless a local branch with the full
+// name exists or --remote is given. With literal (new branches and PR branches), the
+// branch name is used as is. Returns the remote and the branch name.
+func resolveRemoteBranch(branch, remoteFlag string, literal bool) (string, string, error) {
+	remotes, err := listRemotes()
+	if err != nil {
+		return "", "", fmt.Errorf("failed to list remotes: %w", err)
+	}
+
+	if remoteFlag != "" {
+		if !slices.Contains(remotes, remoteFlag) {
+			return "", "", errors.NewInvalidInputError(remoteFlag, "no such remote", nil)
+		}
+		return remoteFlag, branch, nil
+	}
+
+	if literal {
+		return git.DefaultRemote(), branch, nil
+	}
+
+	remote, name, ok := git.SplitRemoteBranch(branch, remotes)
+	if !ok {
+		return git.DefaultRemote(), branch, nil
+	}
+	exists, err := branchExists(branch)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to check branch: %w", err)
+	}
+	if exists {
+		return git.DefaultRemote(), branch, nil
+	}
+	return remote, name, nil
+}
+
+// listRemotes returns the names of the configured remotes
+func listRemotes() ([]string, error) {
+	if mockListRemotes != nil {
+		return mockListRemotes()
+	}
+	return git.ListRemotes()
+}
+
+// branchExists checks whether a local branch exists
+func branchExists(branch string) (bool, error) {
+	if mockBranchExists != nil {
+		return mockBranchExists(branch)
+	}
+	return git.BranchExists(branch)
+}
+
+// ensureBranchExists checks and fetches branch from remote if necessary
+func ensureBranchExists(remote, branch string, createBranch bool, fromPR bool) error {
 	// If creating a new branch (and not from PR), it will be created with worktree
 	if createBranch && !fromPR {
 		return nil
 	}
 
 	// Check if branch exists locally
-	var exists bool
-	var err error
-	if mockBranchExists != nil {
-		exists, err = mockBranchExists(branch)
-	} else {
-		exists, err = git.BranchExists(branch)
-	}
+	exists, err := branchExists(branch)
 	if err != nil {
 		return fmt.Errorf("failed to check branch: %w", err)
 	}
@@ -138,20 +223,20 @@ func ensureBranchExists(branch string, createBranch bool, fromPR bool) error {
 		// Try to fetch from remote
 		var remoteExists bool
 		if mockRemoteBranchExists != nil {
-			remoteExists, err = mockRemoteBranchExists(branch)
+			remoteExists, err = mockRemoteBranchExists(remote, branch)
 		} else {
-			remoteExists, err = git.RemoteBranchExists(branch)
+			remoteExists, err = git.RemoteBranchExists(remote, branch)
 		}
 		if err != nil {
 			return fmt.Errorf("failed to check remote branch: %w", err)
 		}
 
 		if remoteExists {
-			fmt.Printf("Fetching branch %s from origin...\n", branch)
+			fmt.Printf("Fetching branch %s from %s...\n", branch, remote)
 			if mockFetchBranch != nil {
-				err = mockFetchBranch(branch)
+				err = mockFetchBranch(remote, branch)
 			} else {
-				err = git.FetchBranch(branch)
+				err = git.FetchBranch(remote, branch)
 			}
 			if err != nil {
 				return err
@@ -164,8 +249,30 @@ func ensureBranchExists(branch string, createBranch bool, fromPR bool) error {
 	return nil
 }
 
-// syncFiles synchronizes files from main worktree to the new worktree
-func syncFiles(wtPath string, mode syncMode) error {
+// setPushUpstream configures a new branch to track <remote>/<branch>.
+// Failures are reported as warnings since the worktree has already been created.
+func setPushUpstream(branch, remote string) {
+	var set bool
+	var err error
+	if mockSetPushUpstream != nil {
+		set, err = mockSetPushUpstream(branch, remote)
+	} else {
+		set, err = git.SetPushUpstream(branch, remote)
+	}
+	if err != nil {
+		fmt.Printf("⚠ Warning: Failed to set upstream of %s: %v\n", branch, err)
+		return
+	}
+	if set {
+		fmt.Printf("✓ Upstream set: %s/%s\n", remote, branch)
+		return
+	}
+	fmt.Printf("ℹ %s doesn't exist on %s yet. Run 'git push -u %s %s' to publish it and set the upstream\n", branch, remote, remote, branch)
+}
+
+// syncFiles synchronizes files from main worktree to the new worktree.
+// Only files allowed by filter are copied.
+func syncFiles(wtPath string, mode syncMode, filter *filesync.Filter) error {
 	mainWtPath, err := getMainWorktreePath()
 	if err != nil {
 		return fmt.Errorf("failed to get main worktree path: %w", err)
@@ -173,121 +280,204 @@ func syncFiles(wtPath string, mode syncMode) error {
 
 	switch mode {
 	case syncAll:
-		return syncAllDiffs(mainWtPath, wtPath)
+		return syncAllDiffs(mainWtPath, wtPath, filter)
 	case syncIgnored:
-		return syncIgnoredFiles(mainWtPath, wtPath)
+		return syncIgnoredFiles(mainWtPath, wtPath, filter)
 	default:
 		return nil
 	}
 }
 
-// getMainWorktreePath returns the path of the main worktree
-func getMainWorktreePath() (string, error) {
-	return git.GetMainWorktreePath()
-}
+// newSyncFilter builds the filter for synced files from the sync settings of
+// the user config and the project's gw.yaml
+func newSyncFilter(projectConfig *config.ProjectConfig) (*filesync.Filter, error) {
+	var syncConfig config.SyncConfig
+	if globalConfig != nil {
+		syncConfig = globalConfig.Sync
+	}
+	if projectConfig != nil {
+		syncConfig = syncConfig.Merge(projectConfig.Sync)
+	}
 
-// syncAllDiffs syncs all files with differences between main worktree and HEAD
-func syncAllDiffs(mainWtPath, newWtPath string) error {
-	fmt.Println("Syncing all changed files...")
+	maxSize, err := syncConfig.MaxSizeBytes()
+	if err != nil {
+		return nil, err
+	}
+	filter, err := filesync.NewFilter(syncConfig.Include, syncConfig.Exclude, maxSize)
+	if err != nil {
+		return nil, err
+	}
 
-	// Get all modified, untracked, and staged files
-	files, err := git.GetChangedFiles(mainWtPath)
+	defaultStrategy, err := filesync.ParseStrategy(syncConfig.Strategy)
 	if err != nil {
-		return err
+		return nil, err
 	}
+	var rules []filesync.Rule
+	for _, r := range syncConfig.Rules {
+		strategy, err := filesync.ParseStrategy(r.Strategy)
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, filesync.Rule{Pattern: r.Pattern, Strategy: strategy})
+	}
+	if err := filter.SetStrategies(defaultStrategy, rules); err != nil {
+		return nil, err
+	}
+	return filter, nil
+}
 
-	copiedCount := 0
-	for _, filePath := range files {
-		srcPath := filepath.Join(mainWtPath, filePath)
-		dstPath := filepath.Join(newWtPath, filePath)
+// getMainWorktreePath returns the path of the main worktree
+func getMainWorktreePath() (string, error) {
+	return git.GetMainWorktreePath()
+}
 
-		// Check if source file exists (skip deleted files)
-		if _, err := os.Stat(srcPath); os.IsNotExist(err) {
-			continue
+// getProjectRoot returns the directory where gw.yaml is looked up: the current
+// worktree, or the main worktree when gw runs in a bare repository directory.
+// A bare repository without worktrees has no gw.yaml, so its git directory is returned.
+func getProjectRoot() (string, error) {
+	repoRoot, err := git.GetRepoRoot()
+	if err == nil {
+		return repoRoot, nil
+	}
+	if mainPath, mainErr := git.GetMainWorktreePath(); mainErr == nil {
+		return mainPath, nil
+	}
+	if git.IsBareRepository() {
+		if commonDir, cdErr := git.GetCommonDir(); cdErr == nil {
+			return commonDir, nil
 		}
+	}
+	return "", err
+}
 
-		if err := copyFile(srcPath, dstPath); err != nil {
-			fmt.Printf("  Warning: Failed to copy %s: %v\n", filePath, err)
-			continue
+// syncAllDiffs replicates the uncommitted changes of the main worktree in the new worktree.
+// Staged and unstaged changes are applied as patches, so they stay staged and unstaged
+// and include deletions, renames and symlinks. Untracked files are copied.
+func syncAllDiffs(mainWtPath, newWtPath string, filter *filesync.Filter) error {
+	fmt.Println("Syncing all changed files...")
+
+	for _, cached := range []bool{true, false} {
+		if err := applyChanges(mainWtPath, newWtPath, cached, filter); err != nil {
+			// The patches don't apply when the new worktree is at a different commit
+			fmt.Printf("⚠ Failed to apply the changes of the main worktree: %v\n", err)
+			fmt.Println("  Copying the changed files instead")
+			return copyChangedFiles(mainWtPath, newWtPath, filter)
 		}
-		copiedCount++
 	}
 
-	fmt.Printf("✓ Synced %d changed files\n", copiedCount)
+	files, err := git.GetUntrackedFiles(mainWtPath)
+	if err != nil {
+		return err
+	}
+	runSync(mainWtPath, newWtPath, files, filter, "untracked")
 	return nil
 }
 
-// syncIgnoredFiles syncs gitignored files from main worktree
-func syncIgnoredFiles(mainWtPath, newWtPath string) error {
-	fmt.Println("Syncing gitignored files...")
+// applyChanges applies the staged changes (with cached) or the unstaged changes
+// of the main worktree that filter allows to the new worktree
+func applyChanges(mainWtPath, newWtPath string, cached bool, filter *filesync.Filter) error {
+	kind := "unstaged"
+	if cached {
+		kind = "staged"
+	}
 
-	// Get list of all ignored files (including those in global gitignore)
-	files, err := git.GetIgnoredFiles(mainWtPath)
+	paths, err := git.DiffPaths(mainWtPath, cached)
 	if err != nil {
 		return err
 	}
+	allowed := filterChangedPaths(mainWtPath, paths, filter)
+	skipped := ""
+	if excluded := len(paths) - len(allowed); excluded > 0 {
+		skipped = fmt.Sprintf(" (%d skipped by sync settings)", excluded)
+	}
+	if len(allowed) == 0 {
+		if skipped != "" {
+			fmt.Printf("ℹ No %s changes to apply%s\n", kind, skipped)
+		}
+		return nil
+	}
 
-	copiedCount := 0
-	for _, filePath := range files {
-		srcPath := filepath.Join(mainWtPath, filePath)
-		dstPath := filepath.Join(newWtPath, filePath)
+	// Only limit the patch to the allowed files when some are left out,
+	// so a long list of changes doesn't end up on the command line
+	var pathspec []string
+	if len(allowed) < len(paths) {
+		pathspec = allowed
+	}
 
-		// Check if source is a file (skip directories)
-		info, err := os.Stat(srcPath)
-		if err != nil {
-			continue
-		}
-		if info.IsDir() {
-			continue
-		}
+	patch, err := os.CreateTemp("", "gw-sync-*.patch")
+	if err != nil {
+		return fmt.Errorf("failed to create patch file: %w", err)
+	}
+	patch.Close()
+	defer os.Remove(patch.Name())
 
-		if err := copyFile(srcPath, dstPath); err != nil {
-			fmt.Printf("  Warning: Failed to copy %s: %v\n", filePath, err)
-			continue
-		}
-		copiedCount++
+	if err := git.WritePatch(mainWtPath, patch.Name(), cached, pathspec); err != nil {
+		return fmt.Errorf("failed to create patch of %s changes: %w", kind, err)
+	}
+	if info, err := os.Stat(patch.Name()); err == nil && info.Size() == 0 {
+		return nil
+	}
+	if err := git.ApplyPatch(newWtPath, patch.Name(), cached); err != nil {
+		return fmt.Errorf("failed to apply %s changes: %w", kind, err)
 	}
 
-	fmt.Printf("✓ Synced %d gitignored files\n", copiedCount)
+	fmt.Printf("✓ Applied %s changes to %d files%s\n", kind, len(allowed), skipped)
 	return nil
 }
 
-// copyFile copies a file from src to dst, creating directories as needed
-func copyFile(src, dst string) error {
-	// Create destination directory if it doesn't exist
-	dstDir := filepath.Dir(dst)
-	if err := os.MkdirAll(dstDir, 0755); err != nil {
-		return fmt.Errorf("failed to create directory: %w", err)
+// filterChangedPaths returns the changed files that filter allows.
+// Deleted files are only matched against the patterns.
+func filterChangedPaths(wtPath string, paths []string, filter *filesync.Filter) []string {
+	var allowed []string
+	for _, p := range paths {
+		if !filter.MatchPath(p) {
+			continue
+		}
+		if info, err := os.Lstat(filepath.Join(wtPath, p)); err == nil && !filter.MatchSize(info.Size()) {
+			continue
+		}
+		allowed = append(allowed, p)
 	}
+	return allowed
+}
 
-	// Open source file
-	srcFile, err := os.Open(src)
-	if err != nil {
-		return fmt.Errorf("failed to open source file: %w", err)
+// copyChangedFiles copies the current contents of the changed and untracked files
+// of the main worktree. Deletions and the staged state are not replicated.
+func copyChangedFiles(mainWtPath, newWtPath string, filter *filesync.Filter) error {
+	var files []string
+	seen := make(map[string]bool)
+	for _, list := range []func() ([]string, error){
+		func() ([]string, error) { return git.DiffPaths(mainWtPath, true) },
+		func() ([]string, error) { return git.DiffPaths(mainWtPath, false) },
+		func() ([]string, error) { return git.GetUntrackedFiles(mainWtPath) },
+	} {
+		paths, err := list()
+		if err != nil {
+			return err
+		}
+		for _, p := range paths {
+			if !seen[p] {
+				seen[p] = true
+				files = append(files, p)
+			}
+		}
 	}
-	defer srcFile.Close()
 
-	// Create destination file
-	dstFile, err := os.Create(dst)
-	if err != nil {
-		return fmt.Errorf("failed to create destination file: %w", err)
-	}
-	defer dstFile.Close()
+	runSync(mainWtPath, newWtPath, files, filter, "changed")
+	return nil
+}
 
-	// Copy contents
-	if _, err := io.Copy(dstFile, srcFile); err != nil {
-		return fmt.Errorf("failed to copy file contents: %w", err)
-	}
+// syncIgnoredFiles syncs gitignored files from main worktree
+func syncIgnoredFiles(mainWtPath, newWtPath string, filter *filesync.Filter) error {
+	fmt.Println("Syncing gitignored files...")
 
-	// Copy permissions
-	srcInfo, err := os.Stat(src)
+	// Get list of all ignored files (including those in global gitignore)
+	files, err := git.GetIgnoredFiles(mainWtPath)
 	if err != nil {
-		return fmt.Errorf("failed to get source file info: %w", err)
-	}
-	if err := os.Chmod(dst, srcInfo.Mode()); err != nil {
-		return fmt.Errorf("failed to set file permissions: %w", err)
+		return err
 	}
 
+	runSync(mainWtPath, newWtPath, files, filter, "gitignored")
 	return nil
 }
 
@@ -308,23 +498,74 @@ func determineSyncMode(configSync, configSyncIgnored, flagSyncAll, flagSyncIgnor
 	return syncNone
 }
 
-// createWorktree creates a new worktree for the given branch
-func createWorktree(repoName, branch string, createBranch bool, from string, openEditor string, mode syncMode) error {
+// resolveDetachedRef resolves the tag, commit or ref given to --detach.
+// It returns the name used for the worktree directory and the commit to check out.
+func resolveDetachedRef(ref string) (string, string, error) {
+	var name, commit string
+	var err error
+	if mockDetachedName != nil {
+		name, commit, err = mockDetachedName(ref)
+	} else {
+		name, commit, err = git.DetachedName(ref)
+	}
+	if err != nil {
+		return "", "", errors.NewInvalidInputError(ref, "not a tag, commit or ref (run 'git fetch --tags' to get remote tags)", nil)
+	}
+	return name, commit, nil
+}
+
+// createWorktreeOptions holds the options of a single createWorktree call
+type createWorktreeOptions struct {
+	// createBranch creates branch instead of checking out an existing one
+	createBranch bool
+	// detach checks out from with a detached HEAD; branch only names the directory
+	detach bool
+	// from is the start point of a new branch, or the commit of a detached worktree
+	from string
+	// pr is the pull request the branch came from, if any
+	pr string
+	// openEditor is the editor command to open the worktree with (empty for none)
+	openEditor string
+	// syncMode selects the files synced from the main worktree
+	syncMode syncMode
+	// customPath overrides the configured naming convention when it is not empty
+	customPath string
+	// carryFrom is the worktree whose uncommitted changes are moved into the new
+	// worktree (empty for none)
+	carryFrom string
+}
+
+// createWorktree creates a worktree for branch and runs the configured sync, hooks and editor
+func createWorktree(repoName, branch string, opts *createWorktreeOptions) error {
 	var wtPath string
 	var err error
-	if mockWorktreePath != nil {
+	switch {
+	case opts.customPath != "":
+		wtPath, err = filepath.Abs(opts.customPath)
+	case mockWorktreePath != nil:
 		wtPath, err = mockWorktreePath(repoName, branch)
-	} else {
+	default:
 		wtPath, err = git.WorktreePath(repoName, branch)
 	}
 	if err != nil {
 		return fmt.Errorf("failed to generate worktree path: %w", err)
 	}
 
-	fmt.Printf("Creating worktree at %s for branch %s...\n", wtPath, branch)
+	if err := checkWorktreePathAvailable(wtPath, branch); err != nil {
+		return err
+	}
+
+	// Hooks see an empty GW_BRANCH for detached worktrees
+	hookBranch := branch
+	if opts.detach {
+		hookBranch = ""
+		fmt.Printf("Creating detached worktree at %s for %s...\n", wtPath, branch)
+	} else {
+		fmt.Printf("Creating worktree at %s for branch %s...\n", wtPath, branch)
+	}
 
 	// Load project config for hooks
-	repoRoot, err := git.GetRepoRoot()
+	repoRoot, err := getProjectRoot()
 	if err != nil {
 		return fmt.Errorf("failed to get repository root: %w", err)
 	}
@@ -336,41 +577,58 @@ func createWorktree(repoName, branch string, createBranch bool, from string, ope
 	// Execute pre-add hooks
 	if projectConfig != nil && len(projectConfig.Hooks.PreAdd) > 0 {
 		fmt.Println("\nExecuting pre-add hooks...")
-		if err := config.ExecuteHooks(projectConfig, config.HookPreAdd, wtPath, branch, repoRoot); err != nil {
+		if err := config.ExecuteHooks(projectConfig, config.HookPreAdd, wtPath, hookBranch, repoRoot); err != nil {
 			return fmt.Errorf("pre-add hook failed: %w", err)
 		}
 	}
 
-	if mockAdd != nil {
-		err = mockAdd(wtPath, branch, createBranch, from)
-	} else {
-		err = git.Add(wtPath, branch, createBranch, from)
+	switch {
+	case opts.detach && mockAddDetached != nil:
+		err = mockAddDetached(wtPath, opts.from)
+	case opts.detach:
+		err = git.AddDetached(wtPath, opts.from)
+	case mockAdd != nil:
+		err = mockAdd(wtPath, branch, opts.createBranch, opts.from)
+	default:
+		err = git.Add(wtPath, branch, opts.createBranch, opts.from)
 	}
 	if err != nil {
 		return err
 	}
 
 	fmt.Printf("✓ Worktree created: %s\n", wtPath)
+	recordNewWorktree(wtPath, branch, opts.createBranch, opts.detach, opts.from, opts.pr)
 
 	// Sync files if requested
-	if mode != syncNone {
-		if err := syncFiles(wtPath, mode); err != nil {
+	if opts.syncMode != syncNone {
+		filter, err := newSyncFilter(projectConfig)
+		if err == nil {
+			err = syncFiles(wtPath, opts.syncMode, filter)
+		}
+		if err != nil {
 			fmt.Printf("⚠ Warning: Failed to sync files: %v\n", err)
 		}
 	}
 
+	// Carry uncommitted changes; the worktree stays even if they don't apply
+	if opts.carryFrom != "" {
+		if err := carryUncommitted(opts.carryFrom, wtPath); err != nil {
+			return err
+		}
+	}
+
 	// Execute post-add hooks from project config
 	if projectConfig != nil && len(projectConfig.Hooks.PostAdd) > 0 {
 		fmt.Println("\nExecuting post-add hooks...")
-		if err := config.ExecuteHooks(projectConfig, config.HookPostAdd, wtPath, branch, repoRoot); err != nil {
+		if err := config.ExecuteHooks(projectConfig, config.HookPostAdd, wtPath, hookBranch, repoRoot); err != nil {
 			// Don't fail if post-add hooks fail, just warn
 			fmt.Printf("⚠ Post-add hook failed: %v\n", err)
 		}
 	}
 
 	// Open in editor
-	if openEditor != "" {
-		if err := openInEditor(openEditor, wtPath); err != nil {
+	if opts.openEditor != "" {
+		if err := openInEditor(opts.openEditor, wtPath); err != nil {
 			// Editor launch failure is just a warning (worktree creation is treated as successful)
 			fmt.Printf("⚠ Warning: Failed to open editor: %v\n", err)
 		}
@@ -379,6 +637,62 @@ func createWorktree(repoName, branch string, createBranch bool, from string, ope
 	return nil
 }
 
+// recordNewWorktree records the metadata of a worktree created by createWorktree
+func recordNewWorktree(path, branch string, createBranch, detach bool, from, pr string) {
+	base := ""
+	if createBranch {
+		// git creates the branch from the current HEAD when no start point is given
+		base = from
+		if base == "" {
+			if current, err := git.GetCurrentBranch(); err == nil && current != "HEAD" {
+				base = current
+			}
+		}
+	}
+
+	now := time.Now().UTC()
+	updateMetadata(path, func(m *metadata.Worktree) {
+		*m = metadata.Worktree{Base: base, PR: pr, CreatedAt: &now, LastUsedAt: &now}
+		if !detach {
+			m.Branch = branch
+		}
+	})
+}
+
+// checkWorktreePathAvailable makes sure no worktree or file exists at path yet.
+// Different branches can map to the same directory (e.g., "feature/a-b" and
+// "feature-a/b"), so this is checked before running git worktree add.
+func checkWorktreePathAvailable(path, branch string) error {
+	var worktrees []git.Worktree
+	var err error
+	if mockListWorktrees != nil {
+		worktrees, err = mockListWorktrees()
+	} else {
+		worktrees, err = git.List()
+	}
+	if err != nil {
+		return fmt.Errorf("failed to list worktrees: %w", err)
+	}
+
+	suggestion := git.AlternativeWorktreePath(path, branch)
+	for _, wt := range worktrees {
+		if filepath.Clean(wt.Path) != filepath.Clean(path) {
+			continue
+		}
+		var cause error
+		if wt.Branch != "" {
+			cause = fmt.Errorf("directory is used by branch %s", wt.Branch)
+		}
+		return errors.NewWorktreeExistsErrorWithSuggestion(path, branch, suggestion, cause)
+	}
+
+	if _, err := os.Stat(path); err == nil {
+		return errors.NewWorktreeExistsErrorWithSuggestion(path, branch, suggestion, fmt.Errorf("path already exists"))
+	}
+
+	return nil
+}
+
 // openInEditor opens the specified path in the given editor
 func openInEditor(editor, path string) error {
 	if mockOpenInEditor != nil {
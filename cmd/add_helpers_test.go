@@ -3,10 +3,13 @@ package cmd
 import (
 	"errors"
 	"fmt"
+	"path/filepath"
 	"testing"
 
+	gwerrors "github.com/t98o84/gw/internal/errors"
 	"github.com/t98o84/gw/internal/fzf"
 	"github.com/t98o84/gw/internal/git"
+	"github.com/t98o84/gw/internal/metadata"
 )
 
 // setupMocks initializes all mock functions to their default implementations
@@ -17,9 +20,15 @@ func setupMocks() {
 	mockBranchExists = nil
 	mockRemoteBranchExists = nil
 	mockFetchBranch = nil
+	mockListRemotes = nil
+	mockSetPushUpstream = nil
 	mockWorktreePath = nil
+	mockListWorktrees = nil
 	mockAdd = nil
+	mockAddDetached = nil
+	mockDetachedName = nil
 	mockOpenInEditor = nil
+	mockMetadataStore = nil
 }
 
 // resetMocks is called after each test
@@ -31,7 +40,7 @@ func resetMocks() {
 type mockSelector struct {
 	selectBranchFunc    func(branches []string) (string, error)
 	selectWorktreeFunc  func(worktrees []*git.Worktree, excludeMain bool) (*git.Worktree, error)
-	selectWorktreesFunc func(worktrees []*git.Worktree, excludeMain bool, multi bool) ([]*git.Worktree, error)
+	selectWorktreesFunc func(worktrees []*git.Worktree, excludeMain bool, multi bool, excludeLocked bool) ([]*git.Worktree, error)
 	isAvailableFunc     func() bool
 }
 
@@ -57,13 +66,13 @@ func (m *mockSelector) SelectWorktree(worktrees []*git.Worktree, excludeMain boo
 	return nil, nil
 }
 
-func (m *mockSelector) SelectWorktrees(worktrees []*git.Worktree, excludeMain bool, multi bool) ([]*git.Worktree, error) {
+func (m *mockSelector) SelectWorktrees(worktrees []*git.Worktree, excludeMain bool, multi bool, excludeLocked bool) ([]*git.Worktree, error) {
 	if m.selectWorktreesFunc != nil {
-		return m.selectWorktreesFunc(worktrees, excludeMain, multi)
+		return m.selectWorktreesFunc(worktrees, excludeMain, multi, excludeLocked)
 	}
 	var selected []*git.Worktree
 	for _, wt := range worktrees {
-		if !excludeMain || !wt.IsMain {
+		if (!excludeMain || !wt.IsMain) && (!excludeLocked || !wt.Locked) {
 			selected = append(selected, wt)
 			if !multi {
 				break
@@ -73,6 +82,8 @@ func (m *mockSelector) SelectWorktrees(worktrees []*git.Worktree, excludeMain bo
 	return selected, nil
 }
 
+func (m *mockSelector) SetCurrent(path string) {}
+
 func (m *mockSelector) IsAvailable() bool {
 	if m.isAvailableFunc != nil {
 		return m.isAvailableFunc()
@@ -282,7 +293,7 @@ func TestGetBranchFromPR(t *testing.T) {
 			defer resetMocks()
 			tt.setupMock()
 
-			got, err := getBranchFromPR(tt.prIdentifier, tt.repoName)
+			got, err := getBranchFromPR(tt.prIdentifier, tt.repoName, "origin")
 			if (err != nil) != tt.wantErr {
 				t.Errorf("getBranchFromPR() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -458,6 +469,20 @@ func TestCheckExistingWorktree(t *testing.T) {
 			wantErr:     true,
 			errContains: "failed to check existing worktree",
 		},
+		{
+			name:   "other branch with the same directory name",
+			branch: "feature-a/b",
+			setupMock: func() {
+				mockFindWorktree = func(branch string) (*git.Worktree, error) {
+					return &git.Worktree{
+						Path:   "/path/to/repo-feature-a-b",
+						Branch: "feature/a-b",
+					}, nil
+				}
+			},
+			want:    nil,
+			wantErr: false,
+		},
 		{
 			name:   "main worktree",
 			branch: "main",
@@ -549,10 +574,10 @@ func TestEnsureBranchExists(t *testing.T) {
 				mockBranchExists = func(branch string) (bool, error) {
 					return false, nil
 				}
-				mockRemoteBranchExists = func(branch string) (bool, error) {
+				mockRemoteBranchExists = func(remote, branch string) (bool, error) {
 					return true, nil
 				}
-				mockFetchBranch = func(branch string) error {
+				mockFetchBranch = func(remote, branch string) error {
 					return nil
 				}
 			},
@@ -567,10 +592,10 @@ func TestEnsureBranchExists(t *testing.T) {
 				mockBranchExists = func(branch string) (bool, error) {
 					return false, nil
 				}
-				mockRemoteBranchExists = func(branch string) (bool, error) {
+				mockRemoteBranchExists = func(remote, branch string) (bool, error) {
 					return true, nil
 				}
-				mockFetchBranch = func(branch string) error {
+				mockFetchBranch = func(remote, branch string) error {
 					return errors.New("fetch failed")
 				}
 			},
@@ -586,7 +611,7 @@ func TestEnsureBranchExists(t *testing.T) {
 				mockBranchExists = func(branch string) (bool, error) {
 					return false, nil
 				}
-				mockRemoteBranchExists = func(branch string) (bool, error) {
+				mockRemoteBranchExists = func(remote, branch string) (bool, error) {
 					return false, nil
 				}
 			},
@@ -602,7 +627,7 @@ func TestEnsureBranchExists(t *testing.T) {
 				mockBranchExists = func(branch string) (bool, error) {
 					return false, nil
 				}
-				mockRemoteBranchExists = func(branch string) (bool, error) {
+				mockRemoteBranchExists = func(remote, branch string) (bool, error) {
 					return false, nil
 				}
 			},
@@ -630,7 +655,7 @@ func TestEnsureBranchExists(t *testing.T) {
 				mockBranchExists = func(branch string) (bool, error) {
 					return false, nil
 				}
-				mockRemoteBranchExists = func(branch string) (bool, error) {
+				mockRemoteBranchExists = func(remote, branch string) (bool, error) {
 					return false, errors.New("git ls-remote failed")
 				}
 			},
@@ -645,7 +670,7 @@ func TestEnsureBranchExists(t *testing.T) {
 			defer resetMocks()
 			tt.setupMock()
 
-			err := ensureBranchExists(tt.branch, tt.createBranch, tt.fromPR)
+			err := ensureBranchExists("origin", tt.branch, tt.createBranch, tt.fromPR)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("ensureBranchExists() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -659,6 +684,108 @@ func TestEnsureBranchExists(t *testing.T) {
 	}
 }
 
+func TestEnsureBranchExists_FetchesFromRemote(t *testing.T) {
+	setupMocks()
+	defer resetMocks()
+
+	var checked, fetched string
+	mockBranchExists = func(branch string) (bool, error) {
+		return false, nil
+	}
+	mockRemoteBranchExists = func(remote, branch string) (bool, error) {
+		checked = remote + "/" + branch
+		return true, nil
+	}
+	mockFetchBranch = func(remote, branch string) error {
+		fetched = remote + "/" + branch
+		return nil
+	}
+
+	if err := ensureBranchExists("upstream", "feature/x", false, false); err != nil {
+		t.Fatalf("ensureBranchExists() error = %v", err)
+	}
+	if checked != "upstream/feature/x" {
+		t.Errorf("checked remote branch = %q, want %q", checked, "upstream/feature/x")
+	}
+	if fetched != "upstream/feature/x" {
+		t.Errorf("fetched remote branch = %q, want %q", fetched, "upstream/feature/x")
+	}
+}
+
+func TestResolveRemoteBranch(t *testing.T) {
+	tests := []struct {
+		name        string
+		branch      string
+		remoteFlag  string
+		literal     bool
+		localExists bool
+		wantRemote  string
+		wantBranch  string
+		wantErr     bool
+	}{
+		{name: "plain branch", branch: "feature/x", wantRemote: "origin", wantBranch: "feature/x"},
+		{name: "remote-qualified branch", branch: "upstream/feature/x", wantRemote: "upstream", wantBranch: "feature/x"},
+		{name: "local branch with a remote prefix", branch: "upstream/feature/x", localExists: true, wantRemote: "origin", wantBranch: "upstream/feature/x"},
+		{name: "literal branch", branch: "upstream/feature/x", literal: true, wantRemote: "origin", wantBranch: "upstream/feature/x"},
+		{name: "remote flag", branch: "feature/x", remoteFlag: "upstream", wantRemote: "upstream", wantBranch: "feature/x"},
+		{name: "unknown remote flag", branch: "feature/x", remoteFlag: "fork", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setupMocks()
+			defer resetMocks()
+			mockListRemotes = func() ([]string, error) {
+				return []string{"origin", "upstream"}, nil
+			}
+			mockBranchExists = func(branch string) (bool, error) {
+				return tt.localExists, nil
+			}
+
+			remote, branch, err := resolveRemoteBranch(tt.branch, tt.remoteFlag, tt.literal)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("resolveRemoteBranch() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if remote != tt.wantRemote || branch != tt.wantBranch {
+				t.Errorf("resolveRemoteBranch() = (%q, %q), want (%q, %q)", remote, branch, tt.wantRemote, tt.wantBranch)
+			}
+		})
+	}
+}
+
+func TestSetPushUpstream(t *testing.T) {
+	tests := []struct {
+		name string
+		set  bool
+		err  error
+	}{
+		{name: "upstream set", set: true},
+		{name: "branch not pushed yet"},
+		{name: "failure is only a warning", err: errors.New("config failed")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setupMocks()
+			defer resetMocks()
+
+			var got string
+			mockSetPushUpstream = func(branch, remote string) (bool, error) {
+				got = remote + "/" + branch
+				return tt.set, tt.err
+			}
+
+			setPushUpstream("feature/new", "upstream")
+			if got != "upstream/feature/new" {
+				t.Errorf("setPushUpstream() configured %q, want %q", got, "upstream/feature/new")
+			}
+		})
+	}
+}
+
 // TestCreateWorktree tests the createWorktree function
 func TestCreateWorktree(t *testing.T) {
 	tests := []struct {
@@ -826,6 +953,7 @@ func TestCreateWorktree(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			setupMocks()
 			defer resetMocks()
+			useTestMetadataStore(t)
 			tt.setupMock()
 
 			// Use origin/main for the new test case
@@ -834,7 +962,7 @@ func TestCreateWorktree(t *testing.T) {
 				from = "origin/main"
 			}
 
-			err := createWorktree(tt.repoName, tt.branch, tt.createBranch, from, tt.openEditor, syncNone)
+			err := createWorktree(tt.repoName, tt.branch, &createWorktreeOptions{createBranch: tt.createBranch, from: from, openEditor: tt.openEditor})
 			if (err != nil) != tt.wantErr {
 				t.Errorf("createWorktree() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -848,7 +976,208 @@ func TestCreateWorktree(t *testing.T) {
 	}
 }
 
+func TestCreateWorktree_Detached(t *testing.T) {
+	setupMocks()
+	defer resetMocks()
+	store := useTestMetadataStore(t)
+
+	mockWorktreePath = func(repoName, branch string) (string, error) {
+		return "/path/to/test-repo-" + branch, nil
+	}
+	mockAdd = func(path string, branch string, createBranch bool, from string) error {
+		return errors.New("expected a detached worktree")
+	}
+	var gotPath, gotRef string
+	mockAddDetached = func(path string, ref string) error {
+		gotPath, gotRef = path, ref
+		return nil
+	}
+
+	if err := createWorktree("test-repo", "v1.2.0", &createWorktreeOptions{detach: true, from: "1a2b3c4d5e6f"}); err != nil {
+		t.Fatalf("createWorktree() error = %v", err)
+	}
+	if gotPath != "/path/to/test-repo-v1.2.0" || gotRef != "1a2b3c4d5e6f" {
+		t.Errorf("AddDetached(%q, %q), want (%q, %q)", gotPath, gotRef, "/path/to/test-repo-v1.2.0", "1a2b3c4d5e6f")
+	}
+
+	m, err := store.Get("/path/to/test-repo-v1.2.0")
+	if err != nil || m == nil {
+		t.Fatalf("store.Get() = %v, %v, want the recorded metadata", m, err)
+	}
+	if m.Branch != "" || m.Base != "" || m.CreatedAt == nil {
+		t.Errorf("recorded metadata = %+v, want no branch or base and a creation time", m)
+	}
+}
+
+func TestCreateWorktree_RecordsMetadata(t *testing.T) {
+	setupMocks()
+	defer resetMocks()
+	store := useTestMetadataStore(t)
+
+	mockWorktreePath = func(repoName, branch string) (string, error) {
+		return "/path/to/test-repo-feature-new", nil
+	}
+	mockAdd = func(path string, branch string, createBranch bool, from string) error {
+		return nil
+	}
+
+	if err := createWorktree("test-repo", "feature/new", &createWorktreeOptions{createBranch: true, from: "origin/develop", pr: "123"}); err != nil {
+		t.Fatalf("createWorktree() error = %v", err)
+	}
+
+	m, err := store.Get("/path/to/test-repo-feature-new")
+	if err != nil || m == nil {
+		t.Fatalf("store.Get() = %v, %v, want the recorded metadata", m, err)
+	}
+	if m.Branch != "feature/new" || m.Base != "origin/develop" || m.PR != "123" {
+		t.Errorf("recorded metadata = %+v, want branch feature/new, base origin/develop and PR 123", m)
+	}
+	if m.CreatedAt == nil || m.LastUsedAt == nil {
+		t.Errorf("recorded metadata = %+v, want the creation and last used times", m)
+	}
+}
+
+// useTestMetadataStore makes the commands record worktree metadata in a temporary store
+func useTestMetadataStore(t *testing.T) *metadata.Store {
+	t.Helper()
+	store := metadata.Open(t.TempDir())
+	mockMetadataStore = func() (*metadata.Store, error) {
+		return store, nil
+	}
+	return store
+}
+
+func TestResolveDetachedRef(t *testing.T) {
+	setupMocks()
+	defer resetMocks()
+
+	mockDetachedName = func(ref string) (string, string, error) {
+		if ref == "v1.2.0" {
+			return "v1.2.0", "1a2b3c4d5e6f", nil
+		}
+		return "", "", errors.New("unknown revision")
+	}
+
+	name, commit, err := resolveDetachedRef("v1.2.0")
+	if err != nil {
+		t.Fatalf("resolveDetachedRef() error = %v", err)
+	}
+	if name != "v1.2.0" || commit != "1a2b3c4d5e6f" {
+		t.Errorf("resolveDetachedRef() = (%q, %q), want (%q, %q)", name, commit, "v1.2.0", "1a2b3c4d5e6f")
+	}
+
+	_, _, err = resolveDetachedRef("no-such-tag")
+	if !gwerrors.IsInvalidInputError(err) {
+		t.Errorf("resolveDetachedRef() error = %v, want InvalidInputError", err)
+	}
+}
+
+func TestCheckExistingDetachedWorktree(t *testing.T) {
+	tests := []struct {
+		name     string
+		existing *git.Worktree
+		want     bool
+	}{
+		{name: "no worktree", existing: nil, want: false},
+		{name: "detached at the commit", existing: &git.Worktree{Path: "/path/to/repo-v1.2.0", Commit: "abc123", IsDetached: true}, want: true},
+		{name: "detached at another commit", existing: &git.Worktree{Path: "/path/to/repo-v1.2.0", Commit: "def456", IsDetached: true}, want: false},
+		{name: "branch worktree", existing: &git.Worktree{Path: "/path/to/repo-v1.2.0", Branch: "v1.2.0", Commit: "abc123"}, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setupMocks()
+			defer resetMocks()
+
+			mockFindWorktree = func(branch string) (*git.Worktree, error) {
+				return tt.existing, nil
+			}
+
+			got, err := checkExistingDetachedWorktree("v1.2.0", "abc123")
+			if err != nil {
+				t.Fatalf("checkExistingDetachedWorktree() error = %v", err)
+			}
+			if (got != nil) != tt.want {
+				t.Errorf("checkExistingDetachedWorktree() = %v, want existing %v", got, tt.want)
+			}
+		})
+	}
+}
+
 // TestOpenInEditor tests the openInEditor function
+func TestCheckWorktreePathAvailable(t *testing.T) {
+	existingDir := t.TempDir()
+
+	tests := []struct {
+		name           string
+		path           string
+		branch         string
+		worktrees      []git.Worktree
+		listErr        error
+		wantErr        bool
+		wantSuggestion bool
+	}{
+		{
+			name:   "path is free",
+			path:   filepath.Join(existingDir, "repo-feature-new"),
+			branch: "feature/new",
+			worktrees: []git.Worktree{
+				{Path: "/path/to/repo", Branch: "main", IsMain: true},
+			},
+			wantErr: false,
+		},
+		{
+			name:   "path is used by another branch",
+			path:   "/path/to/repo-feature-a-b",
+			branch: "feature-a/b",
+			worktrees: []git.Worktree{
+				{Path: "/path/to/repo", Branch: "main", IsMain: true},
+				{Path: "/path/to/repo-feature-a-b", Branch: "feature/a-b"},
+			},
+			wantErr:        true,
+			wantSuggestion: true,
+		},
+		{
+			name:           "path exists on disk",
+			path:           existingDir,
+			branch:         "feature/new",
+			worktrees:      []git.Worktree{{Path: "/path/to/repo", Branch: "main", IsMain: true}},
+			wantErr:        true,
+			wantSuggestion: true,
+		},
+		{
+			name:    "listing worktrees fails",
+			path:    "/path/to/repo-feature-new",
+			branch:  "feature/new",
+			listErr: errors.New("git worktree list failed"),
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setupMocks()
+			defer resetMocks()
+			mockListWorktrees = func() ([]git.Worktree, error) {
+				return tt.worktrees, tt.listErr
+			}
+
+			err := checkWorktreePathAvailable(tt.path, tt.branch)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("checkWorktreePathAvailable() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantSuggestion {
+				if !gwerrors.IsWorktreeExistsError(err) {
+					t.Fatalf("checkWorktreePathAvailable() error = %v, want WorktreeExistsError", err)
+				}
+				if want := git.AlternativeWorktreePath(tt.path, tt.branch); gwerrors.WorktreeExistsSuggestion(err) != want {
+					t.Errorf("suggestion = %q, want %q", gwerrors.WorktreeExistsSuggestion(err), want)
+				}
+			}
+		})
+	}
+}
+
 func TestOpenInEditor(t *testing.T) {
 	tests := []struct {
 		name        string
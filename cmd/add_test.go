@@ -90,3 +90,42 @@ func TestAddCmd_NoSyncIgnoredFlag(t *testing.T) {
 		t.Fatal("Expected 'no-sync-ignored' flag to be defined")
 	}
 }
+
+func TestAddCmd_PathFlag(t *testing.T) {
+	flag := addCmd.Flags().Lookup("path")
+	if flag == nil {
+		t.Fatal("Expected 'path' flag to be defined")
+	}
+}
+
+func TestAddCmd_RemoteFlag(t *testing.T) {
+	flag := addCmd.Flags().Lookup("remote")
+	if flag == nil {
+		t.Fatal("Expected 'remote' flag to be defined")
+	}
+}
+
+func TestAddCmd_TrackFlags(t *testing.T) {
+	for _, name := range []string{"track", "no-track"} {
+		if addCmd.Flags().Lookup(name) == nil {
+			t.Errorf("Expected '%s' flag to be defined", name)
+		}
+	}
+}
+
+func TestAddCmd_DetachFlag(t *testing.T) {
+	flag := addCmd.Flags().Lookup("detach")
+	if flag == nil {
+		t.Fatal("Expected 'detach' flag to be defined")
+	}
+
+	if flag.Shorthand != "d" {
+		t.Errorf("detach flag shorthand = %q, want %q", flag.Shorthand, "d")
+	}
+}
+
+func TestAddCmd_CarryFlag(t *testing.T) {
+	if addCmd.Flags().Lookup("carry") == nil {
+		t.Fatal("Expected 'carry' flag to be defined")
+	}
+}
@@ -0,0 +1,155 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/t98o84/gw/internal/errors"
+	"github.com/t98o84/gw/internal/git"
+)
+
+var carryCmd = &cobra.Command{
+	Use:   "carry [name]",
+	Short: "Move uncommitted changes from the current worktree to another worktree",
+	Long: `Move the uncommitted changes of the current worktree, including untracked
+files, to another worktree. Staged changes stay staged. The current worktree is
+left clean.
+
+The changes are saved with 'git stash' first. If they don't apply to the
+target worktree, e.g. because of conflicting changes there, they are kept in
+the stash and the command shows how to restore them. Ignored files are not
+carried.
+
+Use 'gw add --carry' to move the changes into a new worktree. Unlike
+'gw add --sync', which copies the changes of the main worktree and leaves them
+there, carrying moves the changes of the current worktree.
+
+The name can be a branch name, suffix, directory name or full path. If no name
+is specified and fzf is available, an interactive selector will be shown.
+
+Examples:
+  gw carry feature/hoge
+  gw carry             # Interactive selection with fzf`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runCarry,
+}
+
+func init() {
+	rootCmd.AddCommand(carryCmd)
+}
+
+func runCarry(cmd *cobra.Command, args []string) error {
+	source, err := findCarrySource()
+	if err != nil {
+		return err
+	}
+
+	var target *git.Worktree
+	if len(args) == 0 {
+		target, err = selectWorktreeWithFzf(false, true)
+		if err != nil {
+			return err
+		}
+		if target == nil {
+			return nil // User cancelled
+		}
+	} else {
+		identifier := args[0]
+		target, err = git.FindWorktree(identifier)
+		if err != nil {
+			return fmt.Errorf("failed to find worktree: %w", err)
+		}
+		if target == nil {
+			return errors.NewWorktreeNotFoundError(identifier, nil)
+		}
+	}
+
+	if target.Path == source.Path {
+		return errors.NewInvalidInputError(target.Path, "cannot carry changes into the current worktree", nil)
+	}
+	if target.IsBare || target.Prunable {
+		return errors.NewInvalidInputError(target.Path, "worktree has no working tree to carry changes into", nil)
+	}
+
+	if err := carryUncommitted(source.Path, target.Path); err != nil {
+		return err
+	}
+	touchWorktree(target)
+	return nil
+}
+
+// findCarrySource returns the worktree gw is running in, whose changes are carried
+func findCarrySource() (*git.Worktree, error) {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get current directory: %w", err)
+	}
+	worktrees, err := git.List()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list worktrees: %w", err)
+	}
+
+	source := findCurrentWorktree(cwd, worktrees)
+	if source == nil || source.IsBare {
+		return nil, errors.NewNotInWorktreeError(cwd, nil)
+	}
+	return source, nil
+}
+
+// carryIntoExisting carries the changes for 'gw add --carry' when the worktree
+// already exists. carryFrom is empty without --carry.
+func carryIntoExisting(carryFrom string, wt *git.Worktree) error {
+	if carryFrom == "" {
+		return nil
+	}
+	if wt.Path == carryFrom {
+		return errors.NewInvalidInputError(wt.Path, "cannot carry changes into the current worktree", nil)
+	}
+	return carryUncommitted(carryFrom, wt.Path)
+}
+
+// carryUncommitted carries the uncommitted changes of the worktree at src to the
+// worktree at dst, if there are any
+func carryUncommitted(src, dst string) error {
+	dirty, err := git.IsDirty(src)
+	if err != nil {
+		return fmt.Errorf("failed to check for uncommitted changes: %w", err)
+	}
+	if !dirty {
+		fmt.Println("ℹ No uncommitted changes to carry")
+		return nil
+	}
+	return carryChanges(src, dst)
+}
+
+// carryChanges moves the uncommitted changes of the worktree at src, including
+// untracked files, to the worktree at dst. The changes are stashed first, so if
+// they don't apply to dst they stay in the stash and can be restored.
+func carryChanges(src, dst string) error {
+	fmt.Printf("Carrying uncommitted changes from %s...\n", src)
+
+	commit, err := git.StashPush(src, "gw carry to "+dst)
+	if err != nil {
+		return fmt.Errorf("failed to stash the changes: %w", err)
+	}
+	if commit == "" {
+		// git status reports changes that the stash can't save, e.g. untracked
+		// files inside a submodule
+		fmt.Println("ℹ No uncommitted changes to carry")
+		return nil
+	}
+
+	if err := git.StashApply(dst, commit); err != nil {
+		fmt.Printf("⚠ The changes are kept in the stash (%s). Restore them in %s with:\n", shortHash(commit), src)
+		fmt.Printf("    git stash apply --index %s\n", commit)
+		return fmt.Errorf("failed to apply the changes to %s: %w", dst, err)
+	}
+
+	if err := git.StashDrop(src, commit); err != nil {
+		// The changes were carried; only the stash entry is left behind
+		fmt.Printf("⚠ Warning: Failed to drop stash entry %s: %v\n", shortHash(commit), err)
+	}
+	fmt.Printf("✓ Changes carried to %s\n", dst)
+	return nil
+}
@@ -0,0 +1,136 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// setupCarryRepo creates a repository with a commit and a linked worktree on
+// the feature branch, and returns the paths of both worktrees
+func setupCarryRepo(t *testing.T) (string, string) {
+	t.Helper()
+	requireGit(t)
+	dir := t.TempDir()
+	mainPath := filepath.Join(dir, "repo")
+	wtPath := filepath.Join(dir, "repo-feature")
+
+	if err := os.Mkdir(mainPath, 0755); err != nil {
+		t.Fatal(err)
+	}
+	gitOutput(t, mainPath, "init", "-q", "-b", "main")
+	for _, name := range []string{"staged.txt", "modified.txt"} {
+		if err := os.WriteFile(filepath.Join(mainPath, name), []byte("base\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	gitOutput(t, mainPath, "add", ".")
+	gitOutput(t, mainPath, "commit", "-q", "-m", "init")
+	gitOutput(t, mainPath, "worktree", "add", "-q", "-b", "feature", wtPath)
+	return mainPath, wtPath
+}
+
+func TestCarryChanges(t *testing.T) {
+	mainPath, wtPath := setupCarryRepo(t)
+
+	if err := os.WriteFile(filepath.Join(mainPath, "staged.txt"), []byte("staged\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	gitOutput(t, mainPath, "add", "staged.txt")
+	if err := os.WriteFile(filepath.Join(mainPath, "modified.txt"), []byte("modified\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(mainPath, "new.txt"), []byte("new\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	want := gitOutput(t, mainPath, "status", "--porcelain")
+
+	if err := carryUncommitted(mainPath, wtPath); err != nil {
+		t.Fatalf("carryUncommitted() error = %v", err)
+	}
+
+	if got := gitOutput(t, wtPath, "status", "--porcelain"); got != want {
+		t.Errorf("status of the target =\n%s\nwant\n%s", got, want)
+	}
+	if got := gitOutput(t, mainPath, "status", "--porcelain"); got != "" {
+		t.Errorf("status of the source = %q, want a clean worktree", got)
+	}
+	if got := gitOutput(t, mainPath, "stash", "list"); got != "" {
+		t.Errorf("stash list = %q, want the stash entry dropped", got)
+	}
+}
+
+func TestCarryChanges_ApplyFails(t *testing.T) {
+	mainPath, wtPath := setupCarryRepo(t)
+
+	if err := os.WriteFile(filepath.Join(mainPath, "new.txt"), []byte("carried\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	// An untracked file in the target blocks the carried one
+	if err := os.WriteFile(filepath.Join(wtPath, "new.txt"), []byte("in the way\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := carryUncommitted(mainPath, wtPath); err == nil {
+		t.Fatal("carryUncommitted() error = nil, want error when the changes don't apply")
+	}
+
+	// The changes are kept in the stash and can be restored in the source
+	if got := gitOutput(t, mainPath, "stash", "list"); !strings.Contains(got, "gw carry to") {
+		t.Fatalf("stash list = %q, want the carried changes", got)
+	}
+	gitOutput(t, mainPath, "stash", "pop", "--index")
+	data, err := os.ReadFile(filepath.Join(mainPath, "new.txt"))
+	if err != nil || string(data) != "carried\n" {
+		t.Errorf("restored new.txt = %q, %v, want %q", data, err, "carried\n")
+	}
+	if data, _ := os.ReadFile(filepath.Join(wtPath, "new.txt")); string(data) != "in the way\n" {
+		t.Errorf("target new.txt = %q, want it untouched", data)
+	}
+}
+
+func TestCarryUncommitted_Clean(t *testing.T) {
+	mainPath, wtPath := setupCarryRepo(t)
+
+	if err := carryUncommitted(mainPath, wtPath); err != nil {
+		t.Fatalf("carryUncommitted() error = %v", err)
+	}
+	if got := gitOutput(t, mainPath, "stash", "list"); got != "" {
+		t.Errorf("stash list = %q, want no stash entry for a clean worktree", got)
+	}
+}
+
+func TestCarryUncommitted_NothingToStash(t *testing.T) {
+	mainPath, wtPath := setupCarryRepo(t)
+
+	// An embedded repository with only untracked content makes git status
+	// report changes that git stash has nothing to save for
+	subPath := filepath.Join(mainPath, "sub")
+	gitOutput(t, mainPath, "init", "-q", "sub")
+	gitOutput(t, subPath, "commit", "-q", "--allow-empty", "-m", "sub")
+	gitOutput(t, mainPath, "add", "sub")
+	gitOutput(t, mainPath, "commit", "-q", "-m", "add sub")
+
+	// An older, unrelated stash entry of the user
+	if err := os.WriteFile(filepath.Join(mainPath, "modified.txt"), []byte("my old work\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	gitOutput(t, mainPath, "stash", "push", "-q", "-m", "my old work")
+	oldStash := gitOutput(t, mainPath, "stash", "list")
+
+	if err := os.WriteFile(filepath.Join(subPath, "untracked.txt"), []byte("untracked\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := carryUncommitted(mainPath, wtPath); err != nil {
+		t.Fatalf("carryUncommitted() error = %v", err)
+	}
+
+	if got := gitOutput(t, mainPath, "stash", "list"); got != oldStash {
+		t.Errorf("stash list = %q, want the existing entry %q kept", got, oldStash)
+	}
+	if data, _ := os.ReadFile(filepath.Join(wtPath, "modified.txt")); string(data) != "base\n" {
+		t.Errorf("target modified.txt = %q, want the old stash entry not applied", data)
+	}
+}
@@ -0,0 +1,154 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/t98o84/gw/internal/errors"
+	"github.com/t98o84/gw/internal/git"
+)
+
+var cloneBare bool
+
+var cloneCmd = &cobra.Command{
+	Use:   "clone [flags] <url> [dir]",
+	Short: "Clone a repository into a worktree-friendly layout",
+	Long: `Clone a repository as a bare repository and create a worktree for its
+default branch.
+
+By default the repository is cloned into a container directory that holds the
+bare repository in .bare and all worktrees next to it:
+
+  ex-repo/
+  ├── .bare/
+  ├── .git       (contains "gitdir: ./.bare")
+  └── main/
+
+With --bare, the bare repository is cloned into <dir>.git and worktrees are
+created next to it (ex-repo.git, ex-repo-main, ...).
+
+remote.origin.fetch is configured so that remote branches are visible to
+'gw add', and the default branch tracks its remote branch. worktree.path in
+the config file is used for the first worktree if it is set.
+
+Examples:
+  gw clone https://github.com/owner/ex-repo.git
+    Creates ex-repo/.bare and the worktree ex-repo/main
+
+  gw clone --bare git@github.com:owner/ex-repo.git
+    Creates ex-repo.git and the worktree ex-repo-main
+
+  gw clone https://github.com/owner/ex-repo.git work/ex
+    Clones into work/ex instead of ex-repo`,
+	Args: cobra.RangeArgs(1, 2),
+	RunE: runClone,
+}
+
+func init() {
+	cloneCmd.Flags().BoolVar(&cloneBare, "bare", false, "Clone into <dir>.git with worktrees next to it instead of a container directory")
+	rootCmd.AddCommand(cloneCmd)
+}
+
+func runClone(cmd *cobra.Command, args []string) error {
+	url := args[0]
+	var dir string
+	if len(args) == 2 {
+		dir = args[1]
+	}
+
+	repoDir, gitDir, err := cloneDestination(url, dir, cloneBare)
+	if err != nil {
+		return err
+	}
+	if _, err := os.Stat(repoDir); err == nil {
+		return errors.NewInvalidInputError(repoDir, "destination already exists", nil)
+	}
+
+	fmt.Printf("Cloning %s into %s...\n", url, gitDir)
+	if err := os.MkdirAll(filepath.Dir(gitDir), 0755); err != nil {
+		return fmt.Errorf("failed to create directory: %w", err)
+	}
+	if err := git.CloneBare(url, gitDir); err != nil {
+		if gitDir != repoDir {
+			// Don't leave an empty container directory behind
+			os.RemoveAll(repoDir)
+		}
+		return fmt.Errorf("failed to clone repository: %w", err)
+	}
+	if gitDir != repoDir {
+		if err := os.WriteFile(filepath.Join(repoDir, ".git"), []byte("gitdir: ./.bare\n"), 0644); err != nil {
+			return fmt.Errorf("failed to write .git file: %w", err)
+		}
+	}
+
+	// Run the remaining git commands inside the new repository
+	if err := os.Chdir(repoDir); err != nil {
+		return fmt.Errorf("failed to change directory: %w", err)
+	}
+
+	// A bare clone doesn't set up remote-tracking branches by default
+	if err := git.SetConfig("remote.origin.fetch", "+refs/heads/*:refs/remotes/origin/*"); err != nil {
+		return fmt.Errorf("failed to configure remote: %w", err)
+	}
+	fmt.Println("Fetching remote branches...")
+	if err := git.FetchAll(false); err != nil {
+		return fmt.Errorf("failed to fetch: %w", err)
+	}
+	if err := git.SetRemoteHead("origin"); err != nil {
+		fmt.Printf("⚠ Warning: Failed to set origin/HEAD: %v\n", err)
+	}
+	fmt.Printf("✓ Repository cloned: %s\n", repoDir)
+
+	branch, err := git.GetCurrentBranch()
+	if err != nil || branch == "" || branch == "HEAD" {
+		fmt.Println("ℹ The repository has no commits yet, so no worktree was created")
+		return nil
+	}
+	if exists, err := git.BranchExists(branch); err != nil || !exists {
+		fmt.Printf("ℹ Default branch %s was not found, so no worktree was created\n", branch)
+		return nil
+	}
+
+	repoName, err := git.GetRepoName()
+	if err != nil {
+		return fmt.Errorf("failed to get repository name: %w", err)
+	}
+	if err := createWorktree(repoName, branch, &createWorktreeOptions{}); err != nil {
+		return err
+	}
+
+	if err := git.SetUpstream(branch, "origin/"+branch); err != nil {
+		fmt.Printf("⚠ Warning: Failed to set upstream of %s: %v\n", branch, err)
+	}
+
+	return nil
+}
+
+// cloneDestination determines the repository directory and the bare git directory.
+// In the container layout the git directory is <dir>/.bare; with bare it is <dir> itself.
+func cloneDestination(url, dir string, bare bool) (repoDir string, gitDir string, err error) {
+	if dir == "" {
+		name := git.RemoteRepoName(url)
+		if name == "" || name == "." || name == ".." {
+			return "", "", errors.NewInvalidInputError(url, "cannot determine the repository name; specify the directory", nil)
+		}
+		dir = name
+		if bare {
+			dir += ".git"
+		}
+	} else if bare && !strings.HasSuffix(dir, ".git") {
+		dir += ".git"
+	}
+
+	repoDir, err = filepath.Abs(dir)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to resolve path %s: %w", dir, err)
+	}
+	if bare {
+		return repoDir, repoDir, nil
+	}
+	return repoDir, filepath.Join(repoDir, ".bare"), nil
+}
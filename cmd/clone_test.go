@@ -0,0 +1,206 @@
+package cmd
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCloneCmd_Use(t *testing.T) {
+	if cloneCmd.Use != "clone [flags] <url> [dir]" {
+		t.Errorf("cloneCmd.Use = %q, want %q", cloneCmd.Use, "clone [flags] <url> [dir]")
+	}
+	if cloneCmd.Flags().Lookup("bare") == nil {
+		t.Error("Expected 'bare' flag to be defined")
+	}
+}
+
+func TestCloneDestination(t *testing.T) {
+	cwd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name        string
+		url         string
+		dir         string
+		bare        bool
+		wantRepoDir string
+		wantGitDir  string
+		wantErr     bool
+	}{
+		{
+			name:        "container layout from url",
+			url:         "git@github.com:owner/ex-repo.git",
+			wantRepoDir: filepath.Join(cwd, "ex-repo"),
+			wantGitDir:  filepath.Join(cwd, "ex-repo", ".bare"),
+		},
+		{
+			name:        "bare layout from url",
+			url:         "https://github.com/owner/ex-repo",
+			bare:        true,
+			wantRepoDir: filepath.Join(cwd, "ex-repo.git"),
+			wantGitDir:  filepath.Join(cwd, "ex-repo.git"),
+		},
+		{
+			name:        "container layout with dir",
+			url:         "https://github.com/owner/ex-repo.git",
+			dir:         "/tmp/work/ex",
+			wantRepoDir: "/tmp/work/ex",
+			wantGitDir:  "/tmp/work/ex/.bare",
+		},
+		{
+			name:        "bare layout with dir",
+			url:         "https://github.com/owner/ex-repo.git",
+			dir:         "/tmp/work/ex",
+			bare:        true,
+			wantRepoDir: "/tmp/work/ex.git",
+			wantGitDir:  "/tmp/work/ex.git",
+		},
+		{
+			name:    "no repository name",
+			url:     "",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repoDir, gitDir, err := cloneDestination(tt.url, tt.dir, tt.bare)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("cloneDestination() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if repoDir != tt.wantRepoDir || gitDir != tt.wantGitDir {
+				t.Errorf("cloneDestination() = (%q, %q), want (%q, %q)", repoDir, gitDir, tt.wantRepoDir, tt.wantGitDir)
+			}
+		})
+	}
+}
+
+// requireGit skips the test if git is not installed and sets a commit identity
+func requireGit(t *testing.T) {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	t.Setenv("GIT_AUTHOR_NAME", "gw test")
+	t.Setenv("GIT_AUTHOR_EMAIL", "gw@example.com")
+	t.Setenv("GIT_COMMITTER_NAME", "gw test")
+	t.Setenv("GIT_COMMITTER_EMAIL", "gw@example.com")
+}
+
+// setupCloneRemote creates a repository with a commit on main and a dev branch,
+// and returns its file:// URL
+func setupCloneRemote(t *testing.T) string {
+	t.Helper()
+	requireGit(t)
+
+	src := filepath.Join(t.TempDir(), "ex-repo")
+	for _, args := range [][]string{
+		{"init", "-q", "-b", "main", src},
+		{"-C", src, "commit", "-q", "--allow-empty", "-m", "initial commit"},
+		{"-C", src, "branch", "dev"},
+	} {
+		if out, err := exec.Command("git", args...).CombinedOutput(); err != nil {
+			t.Fatalf("git %v failed: %v\n%s", args, err, out)
+		}
+	}
+	return "file://" + src
+}
+
+// chdirForTest changes the working directory and restores it after the test
+func chdirForTest(t *testing.T, dir string) {
+	t.Helper()
+	cwd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.Chdir(cwd) })
+}
+
+func gitOutput(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+	out, err := exec.Command("git", append([]string{"-C", dir}, args...)...).CombinedOutput()
+	if err != nil {
+		t.Fatalf("git %v failed: %v\n%s", args, err, out)
+	}
+	return strings.TrimSpace(string(out))
+}
+
+func TestRunClone(t *testing.T) {
+	tests := []struct {
+		name         string
+		bare         bool
+		wantGitDir   string
+		wantWorktree string
+	}{
+		{
+			name:         "container layout",
+			bare:         false,
+			wantGitDir:   "ex-repo/.bare",
+			wantWorktree: "ex-repo/main",
+		},
+		{
+			name:         "bare layout",
+			bare:         true,
+			wantGitDir:   "ex-repo.git",
+			wantWorktree: "ex-repo-main",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			url := setupCloneRemote(t)
+			workDir, err := filepath.EvalSymlinks(t.TempDir())
+			if err != nil {
+				t.Fatal(err)
+			}
+			chdirForTest(t, workDir)
+
+			cloneBare = tt.bare
+			defer func() { cloneBare = false }()
+
+			if err := runClone(cloneCmd, []string{url}); err != nil {
+				t.Fatalf("runClone() error = %v", err)
+			}
+
+			gitDir := filepath.Join(workDir, tt.wantGitDir)
+			if got := gitOutput(t, gitDir, "config", "--bool", "core.bare"); got != "true" {
+				t.Errorf("core.bare = %q, want true", got)
+			}
+			if got := gitOutput(t, gitDir, "config", "remote.origin.fetch"); got != "+refs/heads/*:refs/remotes/origin/*" {
+				t.Errorf("remote.origin.fetch = %q", got)
+			}
+			if got := gitOutput(t, gitDir, "branch", "-r", "--format=%(refname:short)"); !strings.Contains(got, "origin/dev") {
+				t.Errorf("remote branches = %q, want origin/dev to be visible", got)
+			}
+
+			worktree := filepath.Join(workDir, tt.wantWorktree)
+			if got := gitOutput(t, worktree, "rev-parse", "--abbrev-ref", "HEAD"); got != "main" {
+				t.Errorf("worktree branch = %q, want main", got)
+			}
+			if got := gitOutput(t, worktree, "rev-parse", "--abbrev-ref", "main@{upstream}"); got != "origin/main" {
+				t.Errorf("upstream = %q, want origin/main", got)
+			}
+		})
+	}
+}
+
+func TestRunClone_DestinationExists(t *testing.T) {
+	url := setupCloneRemote(t)
+	workDir := t.TempDir()
+	chdirForTest(t, workDir)
+	if err := os.Mkdir(filepath.Join(workDir, "ex-repo"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := runClone(cloneCmd, []string{url}); err == nil {
+		t.Error("runClone() error = nil, want error when the destination exists")
+	}
+}
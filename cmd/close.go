@@ -72,12 +72,14 @@ func runClose(cmd *cobra.Command, args []string) error {
 		nil,
 		nil,
 		nil,
+		nil,
 		false,
 		false,
 		false,
 		noYesValue,
 		false,
 		false,
+		false,
 	)
 
 	// Get current directory
@@ -102,6 +104,9 @@ func runClose(cmd *cobra.Command, args []string) error {
 	if currentWT.IsMain {
 		return errors.NewInvalidInputError("main worktree", "cannot close the main worktree", nil)
 	}
+	if currentWT.IsBare {
+		return errors.NewInvalidInputError("bare repository", "cannot close the bare repository", nil)
+	}
 
 	// Get main worktree path
 	mainPath, err := git.GetMainWorktreePath()
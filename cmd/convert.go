@@ -0,0 +1,748 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/t98o84/gw/internal/errors"
+	"github.com/t98o84/gw/internal/git"
+)
+
+var convertConfig = struct {
+	Bare bool
+	Undo bool
+	Yes  bool
+}{}
+
+var convertCmd = &cobra.Command{
+	Use:   "convert [flags]",
+	Short: "Convert a regular clone into the bare repository layout",
+	Long: `Convert a regular clone with a .git directory into the layout created by
+'gw clone', keeping all linked worktrees and uncommitted changes.
+
+By default the repository directory becomes a container directory:
+
+  ex-repo/              ex-repo/
+  ├── .git/       =>    ├── .bare/
+  └── (files)           ├── .git       (contains "gitdir: ./.bare")
+                        └── main/      (files)
+
+With --bare, .git is moved to ex-repo.git next to the repository and the main
+worktree is renamed to ex-repo-main.
+
+The former main worktree becomes the primary worktree. Linked worktrees are
+re-pointed with 'git worktree repair' and renamed to match the naming
+convention (or worktree.path). Locked worktrees keep their location.
+Worktrees inside the main worktree (e.g. with worktree.path set to
+"{{.RepoRoot}}/.worktrees/...") are moved out before its files are moved;
+they can't be locked.
+
+Every step is recorded in <git dir>/gw/convert.json. If a step fails, the
+completed steps are rolled back, and 'gw convert --undo' restores the original
+layout after a successful conversion.
+
+The conversion is refused while a merge, rebase, cherry-pick, revert or bisect
+is in progress, and for repositories with submodules.
+
+Examples:
+  gw convert           # Convert into the container layout
+  gw convert --bare    # Convert into the ex-repo.git layout
+  gw convert --undo    # Restore the original layout`,
+	Args: cobra.NoArgs,
+	RunE: runConvert,
+}
+
+func init() {
+	convertCmd.Flags().BoolVar(&convertConfig.Bare, "bare", false, "Move .git to <repo>.git next to the worktrees instead of a container directory")
+	convertCmd.Flags().BoolVar(&convertConfig.Undo, "undo", false, "Restore the layout from before 'gw convert'")
+	convertCmd.Flags().BoolVarP(&convertConfig.Yes, "yes", "y", false, "Skip confirmation prompt")
+	rootCmd.AddCommand(convertCmd)
+}
+
+const (
+	convertLayoutContainer = "container"
+	convertLayoutBare      = "bare"
+
+	// convertStagingDir holds the main worktree's files inside the container during conversion
+	convertStagingDir = ".gw-primary"
+	// convertNestedSuffix names the directory next to the main worktree that holds
+	// the worktrees nested in it during conversion
+	convertNestedSuffix = ".gw-nested"
+)
+
+// convertMove records a worktree that was moved during conversion
+type convertMove struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// convertJournal records a conversion so that it can be undone
+type convertJournal struct {
+	Version int    `json:"version"`
+	Layout  string `json:"layout"`
+	// MainPath is the original main worktree (and the container directory)
+	MainPath string `json:"main_path"`
+	// OriginalGitDir is the original .git directory
+	OriginalGitDir string `json:"original_git_dir"`
+	// GitDir is the bare repository after conversion
+	GitDir string `json:"git_dir"`
+	// PrimaryPath is where the former main worktree was before it was renamed
+	PrimaryPath string `json:"primary_path"`
+	// AdminDir is the worktree administrative directory created for the former main worktree
+	AdminDir string        `json:"admin_dir"`
+	Moves    []convertMove `json:"moves"`
+	// NestedDir holds the worktrees that were inside the main worktree, and
+	// Relocated records their moves there
+	NestedDir string        `json:"nested_dir,omitempty"`
+	Relocated []convertMove `json:"relocated,omitempty"`
+	// Staged and GitDirMoved record how far the conversion got, so that a
+	// conversion that failed partway can be rolled back
+	Staged      bool `json:"staged,omitempty"`
+	GitDirMoved bool `json:"git_dir_moved,omitempty"`
+}
+
+// convertJournalPath returns the journal location in a git directory
+func convertJournalPath(gitDir string) string {
+	return filepath.Join(gitDir, "gw", "convert.json")
+}
+
+// dir returns the git directory the journal is kept in, which moves with the repository
+func (j *convertJournal) dir() string {
+	if j.GitDirMoved {
+		return j.GitDir
+	}
+	return j.OriginalGitDir
+}
+
+// layoutRoot returns the directory git commands run in at the recorded progress
+func (j *convertJournal) layoutRoot() string {
+	if j.GitDirMoved && j.Layout == convertLayoutBare {
+		return j.GitDir
+	}
+	return j.MainPath
+}
+
+func (j *convertJournal) save() error {
+	path := convertJournalPath(j.dir())
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create journal directory: %w", err)
+	}
+	data, err := json.MarshalIndent(j, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode journal: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write journal: %w", err)
+	}
+	return nil
+}
+
+func loadConvertJournal(gitDir string) (*convertJournal, error) {
+	data, err := os.ReadFile(convertJournalPath(gitDir))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, errors.NewInvalidInputError(gitDir, "no conversion to undo (convert.json not found)", nil)
+		}
+		return nil, fmt.Errorf("failed to read journal: %w", err)
+	}
+	var j convertJournal
+	if err := json.Unmarshal(data, &j); err != nil {
+		return nil, fmt.Errorf("failed to parse journal: %w", err)
+	}
+	return &j, nil
+}
+
+// convertPlan holds what gw convert will do
+type convertPlan struct {
+	repoName string
+	branch   string
+	journal  *convertJournal
+	// primaryTarget is the final location of the former main worktree
+	primaryTarget string
+	linked        []git.Worktree
+	// nested are the moves of the worktrees inside the main worktree to the journal's NestedDir
+	nested []convertMove
+}
+
+func runConvert(cmd *cobra.Command, args []string) error {
+	if convertConfig.Undo {
+		return runConvertUndo()
+	}
+
+	plan, err := planConvert(convertConfig.Bare)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Converting %s into the %s layout:\n", plan.journal.MainPath, plan.journal.Layout)
+	fmt.Printf("  git directory:    %s -> %s\n", plan.journal.OriginalGitDir, plan.journal.GitDir)
+	fmt.Printf("  primary worktree: %s -> %s\n", plan.journal.MainPath, plan.primaryTarget)
+	if len(plan.linked) > 0 {
+		fmt.Printf("  linked worktrees: %d (repaired and renamed to match the naming convention)\n", len(plan.linked))
+	}
+
+	if !convertConfig.Yes {
+		ok, err := confirm("Convert the repository?")
+		if err != nil {
+			return err
+		}
+		if !ok {
+			fmt.Println("Aborted")
+			return nil
+		}
+	}
+
+	return executeConvert(plan)
+}
+
+// planConvert checks that the current repository can be converted and computes the new layout
+func planConvert(bare bool) (*convertPlan, error) {
+	if git.IsBareRepository() {
+		return nil, errors.NewInvalidInputError(".", "the repository already uses a bare layout", nil)
+	}
+
+	worktrees, err := git.List()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list worktrees: %w", err)
+	}
+	if len(worktrees) == 0 || !worktrees[0].IsMain {
+		return nil, errors.NewInvalidInputError(".", "main worktree not found", nil)
+	}
+	main := worktrees[0]
+	if main.Branch == "" {
+		return nil, errors.NewInvalidInputError(main.Path, "the main worktree must be on a branch", nil)
+	}
+
+	gitDir := filepath.Join(main.Path, ".git")
+	if info, err := os.Stat(gitDir); err != nil || !info.IsDir() {
+		return nil, errors.NewInvalidInputError(gitDir, "only repositories with a .git directory can be converted", nil)
+	}
+	if err := checkConvertable(gitDir); err != nil {
+		return nil, err
+	}
+
+	repoName, err := git.GetRepoName()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get repository name: %w", err)
+	}
+
+	suffix := git.BranchToSuffix(main.Branch)
+	plan := &convertPlan{
+		repoName: repoName,
+		branch:   main.Branch,
+		journal: &convertJournal{
+			Version:        1,
+			MainPath:       main.Path,
+			OriginalGitDir: gitDir,
+		},
+	}
+	if bare {
+		plan.journal.Layout = convertLayoutBare
+		plan.journal.GitDir = filepath.Join(filepath.Dir(main.Path), repoName+".git")
+		plan.journal.PrimaryPath = main.Path
+		plan.primaryTarget = filepath.Join(filepath.Dir(main.Path), repoName+"-"+suffix)
+	} else {
+		plan.journal.Layout = convertLayoutContainer
+		plan.journal.GitDir = filepath.Join(main.Path, ".bare")
+		plan.journal.PrimaryPath = filepath.Join(main.Path, convertStagingDir)
+		plan.primaryTarget = filepath.Join(main.Path, suffix)
+	}
+
+	// Worktrees inside the main worktree would be moved along with its files,
+	// so they are moved out first (see convertLayout)
+	for _, wt := range worktrees[1:] {
+		rel, err := filepath.Rel(main.Path, wt.Path)
+		if wt.Prunable || err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			continue
+		}
+		if wt.Locked {
+			return nil, errors.NewInvalidInputError(wt.Path, "locked worktree inside the main worktree; unlock it or move it out first", nil)
+		}
+		if plan.journal.NestedDir == "" {
+			plan.journal.NestedDir = filepath.Join(filepath.Dir(main.Path), filepath.Base(main.Path)+convertNestedSuffix)
+		}
+		plan.nested = append(plan.nested, convertMove{From: wt.Path, To: filepath.Join(plan.journal.NestedDir, rel)})
+	}
+
+	// In the container layout the primary worktree is created inside the main
+	// worktree after its files were moved away, so only the bare layout can collide
+	for _, path := range []string{plan.journal.GitDir, plan.journal.PrimaryPath, plan.primaryTarget, plan.journal.NestedDir} {
+		if path == "" || path == main.Path || (!bare && path == plan.primaryTarget) {
+			continue
+		}
+		if _, err := os.Lstat(path); err == nil {
+			return nil, errors.NewInvalidInputError(path, "path already exists", nil)
+		}
+	}
+
+	plan.linked = worktrees[1:]
+	return plan, nil
+}
+
+// checkConvertable refuses repositories in states that can't be moved safely
+func checkConvertable(gitDir string) error {
+	for _, name := range []string{"MERGE_HEAD", "CHERRY_PICK_HEAD", "REVERT_HEAD", "BISECT_LOG", "rebase-merge", "rebase-apply"} {
+		if _, err := os.Stat(filepath.Join(gitDir, name)); err == nil {
+			return errors.NewInvalidInputError(gitDir, "an operation is in progress ("+name+"); finish or abort it first", nil)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(gitDir, "modules")); err == nil {
+		return errors.NewInvalidInputError(gitDir, "repositories with submodules are not supported", nil)
+	}
+	if worktree, err := git.GetConfig("core.worktree"); err == nil && worktree != "" {
+		return errors.NewInvalidInputError(gitDir, "repositories with core.worktree are not supported", nil)
+	}
+	return nil
+}
+
+// executeConvert performs the conversion described by plan. If a step fails,
+// the steps completed so far are rolled back.
+func executeConvert(plan *convertPlan) error {
+	j := plan.journal
+
+	// Record the conversion before the first change, so that it can always be rolled back
+	if err := j.save(); err != nil {
+		return err
+	}
+	if err := convertLayout(plan); err != nil {
+		fmt.Printf("⚠ Conversion failed: %v\n", err)
+		fmt.Println("Restoring the original layout...")
+		if rbErr := undoConvert(j); rbErr != nil {
+			return fmt.Errorf("%w (restoring the original layout failed: %v; run 'gw convert --undo' to retry)", err, rbErr)
+		}
+		return err
+	}
+
+	fmt.Printf("✓ Repository converted: %s\n", j.MainPath)
+	fmt.Printf("  Primary worktree: %s\n", plan.primaryTarget)
+	fmt.Println("  Run 'gw convert --undo' to restore the original layout")
+	fmt.Fprintf(os.Stderr, "ℹ If your shell was in a moved directory, change to %s\n", plan.primaryTarget)
+	return nil
+}
+
+// convertLayout performs the steps of the conversion, recording each one in the journal
+func convertLayout(plan *convertPlan) error {
+	j := plan.journal
+
+	// nestedFrom maps the holding location of each nested worktree to its original path
+	nestedFrom := make(map[string]string)
+	if len(plan.nested) > 0 {
+		if err := os.Chdir(j.MainPath); err != nil {
+			return fmt.Errorf("failed to change directory: %w", err)
+		}
+		for _, move := range plan.nested {
+			fmt.Printf("Moving nested worktree %s to %s...\n", move.From, move.To)
+			if err := moveWorktreeTo(move.From, move.To); err != nil {
+				return err
+			}
+			j.Relocated = append(j.Relocated, move)
+			if err := j.save(); err != nil {
+				return err
+			}
+			nestedFrom[move.To] = move.From
+			for i := range plan.linked {
+				if plan.linked[i].Path == move.From {
+					plan.linked[i].Path = move.To
+				}
+			}
+		}
+	}
+
+	// Work from a directory that is not moved
+	if err := os.Chdir(filepath.Dir(j.MainPath)); err != nil {
+		return fmt.Errorf("failed to change directory: %w", err)
+	}
+
+	if j.Layout == convertLayoutContainer {
+		j.Staged = true
+		if err := j.save(); err != nil {
+			return err
+		}
+		fmt.Printf("Moving files of the main worktree to %s...\n", j.PrimaryPath)
+		if err := moveDirEntries(j.MainPath, j.PrimaryPath, ".git"); err != nil {
+			return err
+		}
+	}
+
+	fmt.Printf("Moving %s to %s...\n", j.OriginalGitDir, j.GitDir)
+	if err := os.Rename(j.OriginalGitDir, j.GitDir); err != nil {
+		return fmt.Errorf("failed to move git directory: %w", err)
+	}
+	j.GitDirMoved = true
+	if err := j.save(); err != nil {
+		return err
+	}
+	if j.Layout == convertLayoutContainer {
+		if err := os.WriteFile(filepath.Join(j.MainPath, ".git"), []byte("gitdir: ./.bare\n"), 0644); err != nil {
+			return fmt.Errorf("failed to write .git file: %w", err)
+		}
+	}
+
+	if err := os.Chdir(j.layoutRoot()); err != nil {
+		return fmt.Errorf("failed to change directory: %w", err)
+	}
+	if err := git.SetConfig("core.bare", "true"); err != nil {
+		return fmt.Errorf("failed to mark the repository as bare: %w", err)
+	}
+
+	j.AdminDir = primaryAdminDir(j.GitDir, git.BranchToSuffix(plan.branch))
+	if err := j.save(); err != nil {
+		return err
+	}
+	if err := linkPrimaryWorktree(j.GitDir, j.PrimaryPath, j.AdminDir); err != nil {
+		return err
+	}
+
+	var linkedPaths []string
+	for _, wt := range plan.linked {
+		if !wt.Prunable {
+			linkedPaths = append(linkedPaths, wt.Path)
+		}
+	}
+	if len(linkedPaths) > 0 {
+		fmt.Println("Repairing linked worktrees...")
+		if err := git.Repair(linkedPaths...); err != nil {
+			return fmt.Errorf("failed to repair worktrees: %w", err)
+		}
+	}
+
+	// Rename the worktrees to match the new layout
+	if err := convertMoveWorktree(j, j.PrimaryPath, plan.primaryTarget); err != nil {
+		return err
+	}
+	for _, wt := range plan.linked {
+		switch {
+		case wt.Prunable:
+			fmt.Printf("ℹ Skipping stale worktree: %s\n", wt.Path)
+			continue
+		case wt.Locked:
+			fmt.Printf("ℹ Keeping locked worktree in place: %s\n", wt.Path)
+			continue
+		}
+
+		original, nested := nestedFrom[wt.Path]
+		target := ""
+		if wt.Branch != "" {
+			path, err := git.WorktreePath(plan.repoName, wt.Branch)
+			switch {
+			case err != nil:
+				fmt.Printf("⚠ Keeping %s in place: %v\n", wt.Path, err)
+			case filepath.Clean(path) == filepath.Clean(wt.Path):
+				continue
+			default:
+				if _, err := os.Lstat(path); err == nil {
+					fmt.Printf("⚠ Keeping %s in place: %s already exists\n", wt.Path, path)
+				} else {
+					target = path
+				}
+			}
+		}
+		if target == "" && nested {
+			// A nested worktree can't stay in the holding directory, so it keeps
+			// its place inside the former main worktree, now the primary worktree
+			rel, err := filepath.Rel(j.MainPath, original)
+			if err != nil {
+				return fmt.Errorf("failed to locate %s: %w", original, err)
+			}
+			target = filepath.Join(plan.primaryTarget, rel)
+		}
+		if target == "" {
+			continue
+		}
+		if err := convertMoveWorktree(j, wt.Path, target); err != nil {
+			return err
+		}
+	}
+	if j.NestedDir != "" {
+		removeEmptyDirs(j.NestedDir)
+	}
+	return nil
+}
+
+// convertMoveWorktree moves a worktree and records the move in the journal
+func convertMoveWorktree(j *convertJournal, from, to string) error {
+	fmt.Printf("Moving worktree %s to %s...\n", from, to)
+	if err := moveWorktreeTo(from, to); err != nil {
+		return err
+	}
+	j.Moves = append(j.Moves, convertMove{From: from, To: to})
+	return j.save()
+}
+
+// moveWorktreeTo moves a worktree with 'git worktree move', creating the parent directory of to
+func moveWorktreeTo(from, to string) error {
+	if err := os.MkdirAll(filepath.Dir(to), 0755); err != nil {
+		return fmt.Errorf("failed to create parent directory: %w", err)
+	}
+	if err := git.Move(from, to); err != nil {
+		return fmt.Errorf("failed to move worktree %s: %w", from, err)
+	}
+	return nil
+}
+
+// removeEmptyDirs removes dir and the directories below it, as long as they are empty
+func removeEmptyDirs(dir string) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return
+	}
+	for _, entry := range entries {
+		if entry.IsDir() {
+			removeEmptyDirs(filepath.Join(dir, entry.Name()))
+		}
+	}
+	// Fails for directories that still have content, which is what we want
+	os.Remove(dir)
+}
+
+// primaryAdminDir returns an unused administrative directory under <gitDir>/worktrees
+// for the former main worktree
+func primaryAdminDir(gitDir, name string) string {
+	adminDir := filepath.Join(gitDir, "worktrees", name)
+	for i := 1; ; i++ {
+		if _, err := os.Lstat(adminDir); os.IsNotExist(err) {
+			return adminDir
+		}
+		adminDir = filepath.Join(gitDir, "worktrees", name+strconv.Itoa(i))
+	}
+}
+
+// linkPrimaryWorktree turns the former main worktree into a linked worktree of the
+// bare repository by creating its administrative directory adminDir under <gitDir>/worktrees
+func linkPrimaryWorktree(gitDir, worktreePath, adminDir string) error {
+	if err := os.MkdirAll(filepath.Join(adminDir, "logs"), 0755); err != nil {
+		return fmt.Errorf("failed to create worktree directory: %w", err)
+	}
+
+	head, err := os.ReadFile(filepath.Join(gitDir, "HEAD"))
+	if err != nil {
+		return fmt.Errorf("failed to read HEAD: %w", err)
+	}
+	files := map[string][]byte{
+		"HEAD":      head,
+		"commondir": []byte("../..\n"),
+		"gitdir":    []byte(filepath.Join(worktreePath, ".git") + "\n"),
+	}
+	for name, data := range files {
+		if err := os.WriteFile(filepath.Join(adminDir, name), data, 0644); err != nil {
+			return fmt.Errorf("failed to write %s: %w", name, err)
+		}
+	}
+
+	// The index, reflog and worktree config belong to the worktree, not the repository
+	for _, name := range []string{"index", filepath.Join("logs", "HEAD"), "config.worktree"} {
+		if err := renameIfExists(filepath.Join(gitDir, name), filepath.Join(adminDir, name)); err != nil {
+			return err
+		}
+	}
+
+	if err := os.WriteFile(filepath.Join(worktreePath, ".git"), []byte("gitdir: "+adminDir+"\n"), 0644); err != nil {
+		return fmt.Errorf("failed to write .git file: %w", err)
+	}
+	return nil
+}
+
+// unlinkPrimaryWorktree reverses linkPrimaryWorktree, also when it stopped partway
+func unlinkPrimaryWorktree(gitDir, worktreePath, adminDir string) error {
+	head, err := os.ReadFile(filepath.Join(adminDir, "HEAD"))
+	switch {
+	case err == nil:
+		if err := os.WriteFile(filepath.Join(gitDir, "HEAD"), head, 0644); err != nil {
+			return fmt.Errorf("failed to write HEAD: %w", err)
+		}
+	case !os.IsNotExist(err):
+		return fmt.Errorf("failed to read HEAD: %w", err)
+	}
+	for _, name := range []string{"index", filepath.Join("logs", "HEAD"), "config.worktree"} {
+		if err := renameIfExists(filepath.Join(adminDir, name), filepath.Join(gitDir, name)); err != nil {
+			return err
+		}
+	}
+	if err := os.RemoveAll(adminDir); err != nil {
+		return fmt.Errorf("failed to remove %s: %w", adminDir, err)
+	}
+	if err := os.Remove(filepath.Join(worktreePath, ".git")); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove .git file: %w", err)
+	}
+	return nil
+}
+
+// renameIfExists renames from to to, ignoring a missing source
+func renameIfExists(from, to string) error {
+	if _, err := os.Lstat(from); os.IsNotExist(err) {
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(to), 0755); err != nil {
+		return fmt.Errorf("failed to create directory: %w", err)
+	}
+	if err := os.Rename(from, to); err != nil {
+		return fmt.Errorf("failed to move %s: %w", from, err)
+	}
+	return nil
+}
+
+// moveDirEntries moves all entries of src into dst (created if needed),
+// except the entry named skip and dst itself
+func moveDirEntries(src, dst, skip string) error {
+	entries, err := os.ReadDir(src)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", src, err)
+	}
+	if err := os.MkdirAll(dst, 0755); err != nil {
+		return fmt.Errorf("failed to create %s: %w", dst, err)
+	}
+	for _, entry := range entries {
+		from := filepath.Join(src, entry.Name())
+		if entry.Name() == skip || from == dst {
+			continue
+		}
+		if err := os.Rename(from, filepath.Join(dst, entry.Name())); err != nil {
+			return fmt.Errorf("failed to move %s: %w", from, err)
+		}
+	}
+	return nil
+}
+
+// runConvertUndo restores the layout recorded by gw convert
+func runConvertUndo() error {
+	commonDir, err := git.GetCommonDir()
+	if err != nil {
+		return errors.NewNotAGitRepoError(".", err)
+	}
+	j, err := loadConvertJournal(commonDir)
+	if err != nil {
+		return err
+	}
+
+	if !convertConfig.Yes {
+		ok, err := confirm(fmt.Sprintf("Restore the original layout of %s?", j.MainPath))
+		if err != nil {
+			return err
+		}
+		if !ok {
+			fmt.Println("Aborted")
+			return nil
+		}
+	}
+
+	if err := undoConvert(j); err != nil {
+		return err
+	}
+	fmt.Printf("✓ Original layout restored: %s\n", j.MainPath)
+	return nil
+}
+
+// undoConvert restores the layout from before the conversion recorded in j.
+// Conversions that stopped partway are rolled back as far as they got.
+func undoConvert(j *convertJournal) error {
+	if err := os.Chdir(j.layoutRoot()); err != nil {
+		return fmt.Errorf("failed to change directory: %w", err)
+	}
+
+	// Move the worktrees back, newest move first
+	for i := len(j.Moves) - 1; i >= 0; i-- {
+		move := j.Moves[i]
+		fmt.Printf("Moving worktree %s back to %s...\n", move.To, move.From)
+		if err := moveWorktreeTo(move.To, move.From); err != nil {
+			return err
+		}
+		j.Moves = j.Moves[:i]
+		if err := j.save(); err != nil {
+			return err
+		}
+	}
+
+	var linkedPaths []string
+	if j.GitDirMoved {
+		worktrees, err := git.List()
+		if err != nil {
+			return fmt.Errorf("failed to list worktrees: %w", err)
+		}
+		for _, wt := range worktrees {
+			path := filepath.Clean(wt.Path)
+			if wt.IsBare || wt.Prunable || path == filepath.Clean(j.PrimaryPath) || path == filepath.Clean(j.MainPath) {
+				continue
+			}
+			linkedPaths = append(linkedPaths, wt.Path)
+		}
+
+		if j.AdminDir != "" {
+			if err := unlinkPrimaryWorktree(j.GitDir, j.PrimaryPath, j.AdminDir); err != nil {
+				return err
+			}
+			j.AdminDir = ""
+			if err := j.save(); err != nil {
+				return err
+			}
+		}
+		if err := git.SetConfig("core.bare", "false"); err != nil {
+			return fmt.Errorf("failed to mark the repository as non-bare: %w", err)
+		}
+
+		if err := os.Chdir(filepath.Dir(j.MainPath)); err != nil {
+			return fmt.Errorf("failed to change directory: %w", err)
+		}
+		if j.Layout == convertLayoutContainer {
+			if err := os.Remove(filepath.Join(j.MainPath, ".git")); err != nil && !os.IsNotExist(err) {
+				return fmt.Errorf("failed to remove .git file: %w", err)
+			}
+		}
+		fmt.Printf("Moving %s back to %s...\n", j.GitDir, j.OriginalGitDir)
+		if err := os.Rename(j.GitDir, j.OriginalGitDir); err != nil {
+			return fmt.Errorf("failed to move git directory: %w", err)
+		}
+		j.GitDirMoved = false
+		if err := j.save(); err != nil {
+			return err
+		}
+	}
+
+	if j.Staged {
+		if err := moveDirEntries(j.PrimaryPath, j.MainPath, ""); err != nil {
+			return err
+		}
+		if err := os.Remove(j.PrimaryPath); err != nil {
+			return fmt.Errorf("failed to remove %s: %w", j.PrimaryPath, err)
+		}
+		j.Staged = false
+		if err := j.save(); err != nil {
+			return err
+		}
+	}
+
+	if err := os.Chdir(j.MainPath); err != nil {
+		return fmt.Errorf("failed to change directory: %w", err)
+	}
+	if len(linkedPaths) > 0 {
+		fmt.Println("Repairing linked worktrees...")
+		if err := git.Repair(linkedPaths...); err != nil {
+			return fmt.Errorf("failed to repair worktrees: %w", err)
+		}
+	}
+
+	// Move the nested worktrees back into the main worktree
+	for i := len(j.Relocated) - 1; i >= 0; i-- {
+		move := j.Relocated[i]
+		fmt.Printf("Moving worktree %s back to %s...\n", move.To, move.From)
+		if err := moveWorktreeTo(move.To, move.From); err != nil {
+			return err
+		}
+		j.Relocated = j.Relocated[:i]
+		if err := j.save(); err != nil {
+			return err
+		}
+	}
+	if j.NestedDir != "" {
+		removeEmptyDirs(j.NestedDir)
+	}
+
+	if err := os.Remove(convertJournalPath(j.dir())); err != nil {
+		fmt.Printf("⚠ Warning: Failed to remove journal: %v\n", err)
+	}
+	return nil
+}
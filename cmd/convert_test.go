@@ -0,0 +1,276 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestConvertCmd_Flags(t *testing.T) {
+	for _, name := range []string{"bare", "undo", "yes"} {
+		if convertCmd.Flags().Lookup(name) == nil {
+			t.Errorf("Expected '%s' flag to be defined", name)
+		}
+	}
+}
+
+// setupConvertRepo creates a regular clone at <tmp>/ex-repo with uncommitted
+// changes and a linked worktree at <tmp>/elsewhere, and returns <tmp>
+func setupConvertRepo(t *testing.T) string {
+	t.Helper()
+	requireGit(t)
+
+	base, err := filepath.EvalSymlinks(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	repo := filepath.Join(base, "ex-repo")
+	gitOutput(t, base, "init", "-q", "-b", "main", repo)
+	if err := os.WriteFile(filepath.Join(repo, "a.txt"), []byte("a\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	gitOutput(t, repo, "add", "a.txt")
+	gitOutput(t, repo, "commit", "-q", "-m", "initial commit")
+	gitOutput(t, repo, "worktree", "add", "-q", "-b", "feature/x", filepath.Join(base, "elsewhere"))
+
+	// Uncommitted changes must survive the conversion
+	if err := os.WriteFile(filepath.Join(repo, "a.txt"), []byte("changed\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(repo, "untracked.txt"), []byte("new\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return base
+}
+
+func TestRunConvert(t *testing.T) {
+	tests := []struct {
+		name        string
+		bare        bool
+		wantGitDir  string
+		wantPrimary string
+		wantFeature string
+	}{
+		{
+			name:        "container layout",
+			wantGitDir:  "ex-repo/.bare",
+			wantPrimary: "ex-repo/main",
+			wantFeature: "ex-repo/feature-x",
+		},
+		{
+			name:        "bare layout",
+			bare:        true,
+			wantGitDir:  "ex-repo.git",
+			wantPrimary: "ex-repo-main",
+			wantFeature: "ex-repo-feature-x",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			base := setupConvertRepo(t)
+			chdirForTest(t, filepath.Join(base, "ex-repo"))
+			convertConfig.Bare = tt.bare
+			convertConfig.Yes = true
+			defer func() { convertConfig.Bare, convertConfig.Undo, convertConfig.Yes = false, false, false }()
+
+			if err := runConvert(convertCmd, nil); err != nil {
+				t.Fatalf("runConvert() error = %v", err)
+			}
+
+			if got := gitOutput(t, filepath.Join(base, tt.wantGitDir), "config", "--bool", "core.bare"); got != "true" {
+				t.Errorf("core.bare = %q, want true", got)
+			}
+			primary := filepath.Join(base, tt.wantPrimary)
+			status := gitOutput(t, primary, "status", "--porcelain")
+			if !strings.Contains(status, "M a.txt") || !strings.Contains(status, "?? untracked.txt") {
+				t.Errorf("primary worktree status = %q, want the uncommitted changes", status)
+			}
+			if got := gitOutput(t, filepath.Join(base, tt.wantFeature), "rev-parse", "--abbrev-ref", "HEAD"); got != "feature/x" {
+				t.Errorf("linked worktree branch = %q, want feature/x", got)
+			}
+
+			// Undo from inside the primary worktree
+			chdirForTest(t, primary)
+			convertConfig.Undo = true
+			if err := runConvert(convertCmd, nil); err != nil {
+				t.Fatalf("runConvert() with --undo error = %v", err)
+			}
+
+			repo := filepath.Join(base, "ex-repo")
+			if info, err := os.Stat(filepath.Join(repo, ".git")); err != nil || !info.IsDir() {
+				t.Fatalf(".git is not a directory after undo: %v", err)
+			}
+			if got := gitOutput(t, repo, "config", "--bool", "core.bare"); got != "false" {
+				t.Errorf("core.bare = %q, want false", got)
+			}
+			status = gitOutput(t, repo, "status", "--porcelain")
+			if !strings.Contains(status, "M a.txt") || !strings.Contains(status, "?? untracked.txt") {
+				t.Errorf("main worktree status = %q, want the uncommitted changes", status)
+			}
+			if got := gitOutput(t, filepath.Join(base, "elsewhere"), "rev-parse", "--abbrev-ref", "HEAD"); got != "feature/x" {
+				t.Errorf("linked worktree branch = %q, want feature/x", got)
+			}
+			if _, err := os.Stat(filepath.Join(base, tt.wantPrimary)); tt.bare && !os.IsNotExist(err) {
+				t.Errorf("%s still exists after undo", tt.wantPrimary)
+			}
+		})
+	}
+}
+
+func TestRunConvert_Refused(t *testing.T) {
+	tests := []struct {
+		name  string
+		setup func(t *testing.T, repo string)
+	}{
+		{
+			name: "merge in progress",
+			setup: func(t *testing.T, repo string) {
+				if err := os.WriteFile(filepath.Join(repo, ".git", "MERGE_HEAD"), []byte("0000000000000000000000000000000000000000\n"), 0644); err != nil {
+					t.Fatal(err)
+				}
+			},
+		},
+		{
+			name: "detached main worktree",
+			setup: func(t *testing.T, repo string) {
+				gitOutput(t, repo, "checkout", "-q", "--detach")
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			base := setupConvertRepo(t)
+			repo := filepath.Join(base, "ex-repo")
+			tt.setup(t, repo)
+			chdirForTest(t, repo)
+			convertConfig.Yes = true
+			defer func() { convertConfig.Yes = false }()
+
+			if err := runConvert(convertCmd, nil); err == nil {
+				t.Fatal("runConvert() error = nil, want error")
+			}
+			if info, err := os.Stat(filepath.Join(repo, ".git")); err != nil || !info.IsDir() {
+				t.Errorf(".git was modified: %v", err)
+			}
+		})
+	}
+}
+
+func TestRunConvert_UndoWithoutJournal(t *testing.T) {
+	base := setupConvertRepo(t)
+	chdirForTest(t, filepath.Join(base, "ex-repo"))
+	convertConfig.Undo = true
+	convertConfig.Yes = true
+	defer func() { convertConfig.Undo, convertConfig.Yes = false, false }()
+
+	if err := runConvert(convertCmd, nil); err == nil {
+		t.Error("runConvert() with --undo error = nil, want error without a journal")
+	}
+}
+
+// addNestedWorktree adds a worktree for feature/nested inside the main worktree,
+// where a worktree.path like "{{.RepoRoot}}/.worktrees/..." puts worktrees
+func addNestedWorktree(t *testing.T, repo string) string {
+	t.Helper()
+	path := filepath.Join(repo, ".worktrees", "nested")
+	gitOutput(t, repo, "worktree", "add", "-q", "-b", "feature/nested", path)
+	return path
+}
+
+func TestRunConvert_NestedWorktree(t *testing.T) {
+	tests := []struct {
+		name       string
+		bare       bool
+		wantNested string
+	}{
+		{name: "container layout", wantNested: "ex-repo/feature-nested"},
+		{name: "bare layout", bare: true, wantNested: "ex-repo-feature-nested"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			base := setupConvertRepo(t)
+			repo := filepath.Join(base, "ex-repo")
+			nested := addNestedWorktree(t, repo)
+			chdirForTest(t, repo)
+			convertConfig.Bare = tt.bare
+			convertConfig.Yes = true
+			defer func() { convertConfig.Bare, convertConfig.Undo, convertConfig.Yes = false, false, false }()
+
+			if err := runConvert(convertCmd, nil); err != nil {
+				t.Fatalf("runConvert() error = %v", err)
+			}
+			if got := gitOutput(t, filepath.Join(base, tt.wantNested), "rev-parse", "--abbrev-ref", "HEAD"); got != "feature/nested" {
+				t.Errorf("nested worktree branch = %q, want feature/nested", got)
+			}
+			if _, err := os.Lstat(filepath.Join(base, "ex-repo"+convertNestedSuffix)); !os.IsNotExist(err) {
+				t.Errorf("holding directory for nested worktrees was not removed: %v", err)
+			}
+
+			convertConfig.Undo = true
+			if err := runConvert(convertCmd, nil); err != nil {
+				t.Fatalf("runConvert() with --undo error = %v", err)
+			}
+			if got := gitOutput(t, nested, "rev-parse", "--abbrev-ref", "HEAD"); got != "feature/nested" {
+				t.Errorf("nested worktree branch after undo = %q, want feature/nested", got)
+			}
+			if got := gitOutput(t, repo, "worktree", "list", "--porcelain"); strings.Contains(got, "prunable") {
+				t.Errorf("worktree list after undo has stale entries:\n%s", got)
+			}
+		})
+	}
+}
+
+func TestExecuteConvert_RollsBackOnFailure(t *testing.T) {
+	for _, bare := range []bool{false, true} {
+		t.Run(fmt.Sprintf("bare=%v", bare), func(t *testing.T) {
+			base := setupConvertRepo(t)
+			repo := filepath.Join(base, "ex-repo")
+			nested := addNestedWorktree(t, repo)
+			chdirForTest(t, repo)
+
+			plan, err := planConvert(bare)
+			if err != nil {
+				t.Fatalf("planConvert() error = %v", err)
+			}
+			// A linked worktree that disappears after planning makes the repair fail,
+			// after the git directory was moved and the primary worktree linked
+			if err := os.RemoveAll(filepath.Join(base, "elsewhere")); err != nil {
+				t.Fatal(err)
+			}
+
+			if err := executeConvert(plan); err == nil {
+				t.Fatal("executeConvert() error = nil, want error")
+			}
+
+			if info, err := os.Stat(filepath.Join(repo, ".git")); err != nil || !info.IsDir() {
+				t.Fatalf(".git is not a directory after the rollback: %v", err)
+			}
+			if got := gitOutput(t, repo, "config", "--bool", "core.bare"); got != "false" {
+				t.Errorf("core.bare = %q, want false", got)
+			}
+			status := gitOutput(t, repo, "status", "--porcelain")
+			if !strings.Contains(status, "M a.txt") || !strings.Contains(status, "?? untracked.txt") {
+				t.Errorf("main worktree status = %q, want the uncommitted changes", status)
+			}
+			if got := gitOutput(t, nested, "rev-parse", "--abbrev-ref", "HEAD"); got != "feature/nested" {
+				t.Errorf("nested worktree branch = %q, want feature/nested", got)
+			}
+			for _, path := range []string{
+				filepath.Join(repo, convertStagingDir),
+				filepath.Join(repo, ".bare"),
+				filepath.Join(base, "ex-repo.git"),
+				filepath.Join(base, "ex-repo"+convertNestedSuffix),
+				convertJournalPath(filepath.Join(repo, ".git")),
+			} {
+				if _, err := os.Lstat(path); !os.IsNotExist(err) {
+					t.Errorf("%s was left behind: %v", path, err)
+				}
+			}
+		})
+	}
+}
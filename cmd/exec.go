@@ -56,7 +56,7 @@ func runExec(cmd *cobra.Command, args []string) error {
 
 	// If no worktree found, use fzf to select
 	if wt == nil {
-		wt, err = selectWorktreeWithFzf(false)
+		wt, err = selectWorktreeWithFzf(false, false)
 		if err != nil {
 			return err
 		}
@@ -65,6 +65,8 @@ func runExec(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	touchWorktree(wt)
+
 	// Execute command in the worktree directory
 	execCommand := exec.Command(command[0], command[1:]...)
 	execCommand.Dir = wt.Path
@@ -29,7 +29,7 @@ func init() {
 }
 
 func runFd(cmd *cobra.Command, args []string) error {
-	wt, err := selectWorktreeWithFzf(false)
+	wt, err := selectWorktreeWithFzf(false, false)
 	if err != nil {
 		return err
 	}
@@ -1,48 +1,105 @@
 package cmd
 
 import (
+	"os"
+	"sort"
+	"time"
+
 	"github.com/t98o84/gw/internal/fzf"
 	"github.com/t98o84/gw/internal/git"
+	"github.com/t98o84/gw/internal/metadata"
 	"github.com/t98o84/gw/internal/shell"
 )
 
 // selectWorktreeWithFzf shows an interactive worktree selector using fzf
 // excludeMain: if true, excludes the main worktree from the list
-func selectWorktreeWithFzf(excludeMain bool) (*git.Worktree, error) {
+// excludeCurrent: if true, excludes the worktree gw is running in from the list
+func selectWorktreeWithFzf(excludeMain bool, excludeCurrent bool) (*git.Worktree, error) {
 	selector := fzf.NewSelector(shell.NewRealExecutor())
-	return selectWorktreeWithSelector(selector, excludeMain)
+	return selectWorktreeWithSelector(selector, excludeMain, excludeCurrent)
 }
 
-func selectWorktreeWithSelector(selector fzf.Selector, excludeMain bool) (*git.Worktree, error) {
-	worktrees, err := git.List()
+func selectWorktreeWithSelector(selector fzf.Selector, excludeMain bool, excludeCurrent bool) (*git.Worktree, error) {
+	worktreePtrs, err := listWorktreesForSelection(selector, excludeCurrent)
 	if err != nil {
 		return nil, err
 	}
-	// Convert []Worktree to []*Worktree
-	worktreePtrs := make([]*git.Worktree, len(worktrees))
-	for i := range worktrees {
-		worktreePtrs[i] = &worktrees[i]
-	}
 	return selector.SelectWorktree(worktreePtrs, excludeMain)
 }
 
 // selectWorktreesWithFzf shows an interactive worktree selector using fzf with multi-select support
 // excludeMain: if true, excludes the main worktree from the list
 // multi: if true, allows selecting multiple worktrees (use Tab to select)
-func selectWorktreesWithFzf(excludeMain bool, multi bool) ([]*git.Worktree, error) {
+// excludeLocked: if true, excludes locked worktrees from the list
+func selectWorktreesWithFzf(excludeMain bool, multi bool, excludeLocked bool) ([]*git.Worktree, error) {
 	selector := fzf.NewSelector(shell.NewRealExecutor())
-	return selectWorktreesWithSelector(selector, excludeMain, multi)
+	return selectWorktreesWithSelector(selector, excludeMain, multi, excludeLocked)
+}
+
+func selectWorktreesWithSelector(selector fzf.Selector, excludeMain bool, multi bool, excludeLocked bool) ([]*git.Worktree, error) {
+	worktreePtrs, err := listWorktreesForSelection(selector, false)
+	if err != nil {
+		return nil, err
+	}
+	return selector.SelectWorktrees(worktreePtrs, excludeMain, multi, excludeLocked)
 }
 
-func selectWorktreesWithSelector(selector fzf.Selector, excludeMain bool, multi bool) ([]*git.Worktree, error) {
+// listWorktreesForSelection lists the worktrees in the order they are offered by the
+// selectors: most recently used first, with the current worktree last (or left out).
+// The current worktree is marked in selector when it is listed.
+func listWorktreesForSelection(selector fzf.Selector, excludeCurrent bool) ([]*git.Worktree, error) {
 	worktrees, err := git.List()
 	if err != nil {
 		return nil, err
 	}
-	// Convert []Worktree to []*Worktree
-	worktreePtrs := make([]*git.Worktree, len(worktrees))
+	currentPath, _ := os.Getwd()
+	if current := findCurrentWorktree(currentPath, worktrees); current != nil && !excludeCurrent {
+		selector.SetCurrent(current.Path)
+	}
+	return orderByRecentUse(worktrees, loadMetadata(), currentPath, excludeCurrent), nil
+}
+
+// orderByRecentUse orders worktrees by their recorded last use, most recent first.
+// Worktrees that were never used keep the order git lists them in, after the used ones.
+// The worktree containing currentPath is moved to the end, or left out with excludeCurrent.
+func orderByRecentUse(worktrees []git.Worktree, recorded map[string]metadata.Worktree, currentPath string, excludeCurrent bool) []*git.Worktree {
+	var current *git.Worktree
+	if currentPath != "" {
+		current = findCurrentWorktree(currentPath, worktrees)
+	}
+
+	ordered := make([]*git.Worktree, 0, len(worktrees))
+	lastUsed := make(map[*git.Worktree]*time.Time)
 	for i := range worktrees {
-		worktreePtrs[i] = &worktrees[i]
+		wt := &worktrees[i]
+		if wt == current {
+			continue
+		}
+		ordered = append(ordered, wt)
+		if m := lookupMetadata(recorded, wt); m != nil {
+			lastUsed[wt] = m.LastUsedAt
+		}
+	}
+	sort.SliceStable(ordered, func(i, j int) bool {
+		a, b := lastUsed[ordered[i]], lastUsed[ordered[j]]
+		return a != nil && (b == nil || a.After(*b))
+	})
+
+	if current != nil && !excludeCurrent {
+		ordered = append(ordered, current)
+	}
+	return ordered
+}
+
+// previousWorktree returns the most recently used worktree other than the one
+// containing currentPath, or nil if no other worktree was used with gw
+func previousWorktree(worktrees []git.Worktree, recorded map[string]metadata.Worktree, currentPath string) *git.Worktree {
+	ordered := orderByRecentUse(worktrees, recorded, currentPath, true)
+	if len(ordered) == 0 {
+		return nil
+	}
+	if m := lookupMetadata(recorded, ordered[0]); m == nil || m.LastUsedAt == nil {
+		return nil
 	}
-	return selector.SelectWorktrees(worktreePtrs, excludeMain, multi)
+	return ordered[0]
 }
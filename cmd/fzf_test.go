@@ -1,7 +1,12 @@
 package cmd
 
 import (
+	"reflect"
 	"testing"
+	"time"
+
+	"github.com/t98o84/gw/internal/git"
+	"github.com/t98o84/gw/internal/metadata"
 )
 
 // Note: fzf functions require interactive input, so we only test
@@ -26,3 +31,86 @@ func TestSelectWorktreeWithFzf_FunctionExists(t *testing.T) {
 	// We don't call it to avoid fzf interaction
 	t.Log("selectWorktreeWithFzf function exists with correct signature")
 }
+
+func TestOrderByRecentUse(t *testing.T) {
+	used := func(minutesAgo int) metadata.Worktree {
+		t := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC).Add(-time.Duration(minutesAgo) * time.Minute)
+		return metadata.Worktree{LastUsedAt: &t}
+	}
+	worktrees := []git.Worktree{
+		{Path: "/repo", Branch: "main", IsMain: true},
+		{Path: "/repo-a", Branch: "a"},
+		{Path: "/repo-b", Branch: "b"},
+		{Path: "/repo-c", Branch: "c"},
+		{Path: "/repo-d", Branch: "d"},
+	}
+	recorded := map[string]metadata.Worktree{
+		"/repo-b": used(30),
+		"/repo-c": used(0),
+		"/repo-d": used(5),
+		"/repo-a": {Branch: "a"},
+	}
+
+	tests := []struct {
+		name           string
+		currentPath    string
+		excludeCurrent bool
+		want           []string
+	}{
+		{
+			name: "most recently used first",
+			want: []string{"/repo-c", "/repo-d", "/repo-b", "/repo", "/repo-a"},
+		},
+		{
+			name:        "current worktree last",
+			currentPath: "/repo-c/src",
+			want:        []string{"/repo-d", "/repo-b", "/repo", "/repo-a", "/repo-c"},
+		},
+		{
+			name:           "current worktree excluded",
+			currentPath:    "/repo-c",
+			excludeCurrent: true,
+			want:           []string{"/repo-d", "/repo-b", "/repo", "/repo-a"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := orderByRecentUse(worktrees, recorded, tt.currentPath, tt.excludeCurrent)
+			var paths []string
+			for _, wt := range got {
+				paths = append(paths, wt.Path)
+			}
+			if !reflect.DeepEqual(paths, tt.want) {
+				t.Errorf("orderByRecentUse() = %v, want %v", paths, tt.want)
+			}
+		})
+	}
+}
+
+func TestPreviousWorktree(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	earlier := now.Add(-time.Hour)
+	worktrees := []git.Worktree{
+		{Path: "/repo", Branch: "main", IsMain: true},
+		{Path: "/repo-a", Branch: "a"},
+		{Path: "/repo-b", Branch: "b"},
+	}
+	recorded := map[string]metadata.Worktree{
+		"/repo-a": {LastUsedAt: &earlier},
+		"/repo-b": {LastUsedAt: &now},
+	}
+
+	// Like cd -, the previous worktree is the last one used before the current one
+	if got := previousWorktree(worktrees, recorded, "/repo-b"); got == nil || got.Path != "/repo-a" {
+		t.Errorf("previousWorktree() = %v, want /repo-a", got)
+	}
+	// Switching from a worktree that wasn't entered with gw goes back to the last used one
+	if got := previousWorktree(worktrees, recorded, "/repo"); got == nil || got.Path != "/repo-b" {
+		t.Errorf("previousWorktree() = %v, want /repo-b", got)
+	}
+	// Nothing to go back to
+	if got := previousWorktree(worktrees, map[string]metadata.Worktree{"/repo-b": {LastUsedAt: &now}}, "/repo-b"); got != nil {
+		t.Errorf("previousWorktree() = %v, want nil", got)
+	}
+}
@@ -0,0 +1,324 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/t98o84/gw/internal/config"
+	"github.com/t98o84/gw/internal/errors"
+	"github.com/t98o84/gw/internal/git"
+	"github.com/t98o84/gw/internal/metadata"
+)
+
+var gcConfig = struct {
+	OlderThan     string
+	UnusedFor     string
+	Yes           bool
+	Branch        bool
+	Force         bool
+	IncludeLocked bool
+	IncludeDirty  bool
+	DryRun        bool
+	NoYes         bool
+	NoBranch      bool
+}{}
+
+var gcCmd = &cobra.Command{
+	Use:   "gc [flags]",
+	Short: "Remove worktrees that are old or haven't been used for a while",
+	Long: `Remove worktrees by age, using the creation and last use times gw records
+for each worktree (see 'gw info').
+
+  --older-than  Select worktrees created with gw longer ago than this
+  --unused-for  Select worktrees not used with 'gw sw' or 'gw exec' for this
+                long (worktrees never used count from their creation)
+
+With both flags, a worktree must match both. Durations are given in days (30d),
+weeks (2w) or as Go durations (12h). Worktrees without recorded times, e.g.
+those created without gw, and worktrees whose branch differs from the recorded
+one are never selected. Recorded times of worktrees git no longer knows about
+are deleted.
+
+The main worktree and the current worktree are never removed. Locked worktrees
+are skipped unless --include-locked is given, and worktrees with uncommitted
+changes unless --include-dirty is given. Worktrees are removed like 'gw rm': the
+pre_remove and post_remove hooks run, and with --branch only merged branches
+are deleted unless --force is given.
+
+The candidates are listed and a confirmation prompt is shown unless --yes is
+given or rm.force is set in the config file. Use --yes when running gw gc from
+cron, since there is nobody to answer the prompt.
+
+Examples:
+  gw gc --older-than 30d                 # List candidates and confirm removal
+  gw gc --unused-for 14d -n              # Only list candidates
+  gw gc --older-than 30d --unused-for 14d -y -b
+    Remove old, unused worktrees and their merged branches without confirmation`,
+	Args: cobra.NoArgs,
+	RunE: runGc,
+}
+
+func init() {
+	gcCmd.Flags().StringVar(&gcConfig.OlderThan, "older-than", "", "Select worktrees created longer ago than this (e.g. 30d)")
+	gcCmd.Flags().StringVar(&gcConfig.UnusedFor, "unused-for", "", "Select worktrees not used for this long (e.g. 14d)")
+	gcCmd.Flags().BoolVarP(&gcConfig.Yes, "yes", "y", false, "Skip confirmation prompt")
+	gcCmd.Flags().BoolVarP(&gcConfig.Branch, "branch", "b", false, "Also delete the associated git branches")
+	gcCmd.Flags().BoolVarP(&gcConfig.Force, "force", "f", false, "Delete the branches with --branch even if they aren't merged")
+	gcCmd.Flags().BoolVar(&gcConfig.IncludeLocked, "include-locked", false, "Also remove locked worktrees")
+	gcCmd.Flags().BoolVar(&gcConfig.IncludeDirty, "include-dirty", false, "Also remove worktrees with uncommitted changes")
+	gcCmd.Flags().BoolVarP(&gcConfig.DryRun, "dry-run", "n", false, "Only list the worktrees that would be removed")
+	// Negation flags
+	gcCmd.Flags().BoolVar(&gcConfig.NoYes, "no-yes", false, "Force disable automatic confirmation (overrides config and --yes)")
+	gcCmd.Flags().BoolVar(&gcConfig.NoBranch, "no-branch", false, "Force disable branch deletion (overrides config and --branch)")
+	rootCmd.AddCommand(gcCmd)
+}
+
+// gcCandidate is a worktree selected for removal by gw gc
+type gcCandidate struct {
+	worktree *git.Worktree
+	reason   string
+}
+
+// gcState holds the criteria and repository state used to select gc candidates
+type gcState struct {
+	now time.Time
+	// olderThan and unusedFor are zero when the criterion is not used
+	olderThan time.Duration
+	unusedFor time.Duration
+	// includeLocked and includeDirty select locked and dirty worktrees too
+	includeLocked bool
+	includeDirty  bool
+	// recorded is the worktree metadata keyed by metadata.Key
+	recorded map[string]metadata.Worktree
+	// currentPath is the path of the worktree gw is running in
+	currentPath string
+	// isDirty reports whether a worktree has uncommitted changes
+	isDirty func(path string) (bool, error)
+}
+
+func runGc(cmd *cobra.Command, args []string) error {
+	// Load configuration
+	cfg := config.LoadOrDefault()
+
+	// Validate flag conflicts
+	if gcConfig.OlderThan == "" && gcConfig.UnusedFor == "" {
+		return fmt.Errorf("specify --older-than, --unused-for or both")
+	}
+	if gcConfig.Yes && gcConfig.NoYes {
+		return fmt.Errorf("cannot use --yes and --no-yes together")
+	}
+	if gcConfig.Branch && gcConfig.NoBranch {
+		return fmt.Errorf("cannot use --branch and --no-branch together")
+	}
+
+	state := &gcState{
+		now:           time.Now(),
+		includeLocked: gcConfig.IncludeLocked,
+		includeDirty:  gcConfig.IncludeDirty,
+		isDirty:       git.IsDirty,
+	}
+	var err error
+	if gcConfig.OlderThan != "" {
+		if state.olderThan, err = parseAge(gcConfig.OlderThan); err != nil {
+			return err
+		}
+	}
+	if gcConfig.UnusedFor != "" {
+		if state.unusedFor, err = parseAge(gcConfig.UnusedFor); err != nil {
+			return err
+		}
+	}
+	if cwd, err := os.Getwd(); err == nil {
+		state.currentPath = cwd
+	}
+
+	// Merge with command-line flags (flags take precedence)
+	var yesFlagPtr *bool
+	if cmd.Flags().Changed("yes") {
+		yesFlagPtr = &gcConfig.Yes
+	}
+	var branchFlagPtr *bool
+	if cmd.Flags().Changed("branch") {
+		branchFlagPtr = &gcConfig.Branch
+	}
+	mergedConfig := cfg.MergeWithFlags(
+		nil,
+		nil,
+		nil,
+		yesFlagPtr,
+		branchFlagPtr,
+		nil,
+		nil,
+		nil,
+		nil,
+		false,
+		false,
+		false,
+		false,
+		gcConfig.NoYes,
+		gcConfig.NoBranch,
+		false,
+	)
+
+	worktrees, err := git.List()
+	if err != nil {
+		return fmt.Errorf("failed to list worktrees: %w", err)
+	}
+	if !gcConfig.DryRun {
+		expireMetadata(worktrees)
+	}
+	state.recorded = loadMetadata()
+
+	candidates := collectGcCandidates(worktrees, state)
+	if len(candidates) == 0 {
+		fmt.Println("No worktrees to remove")
+		return nil
+	}
+
+	fmt.Println("Worktrees to remove:")
+	for _, c := range candidates {
+		fmt.Printf("  %s\t%s\t(%s)\n", filepath.Base(c.worktree.Path), c.worktree.Branch, c.reason)
+	}
+
+	if gcConfig.DryRun {
+		return nil
+	}
+
+	if !mergedConfig.Rm.Force {
+		ok, err := confirm(fmt.Sprintf("Remove %d worktree(s)?", len(candidates)))
+		if err != nil {
+			return err
+		}
+		if !ok {
+			fmt.Println("Aborted")
+			return nil
+		}
+	}
+
+	// Locked and dirty candidates were only selected with --include-locked or
+	// --include-dirty, so they are removed by force. Branches are another matter:
+	// they may hold unpushed work, so only --force deletes unmerged branches
+	opts, err := newRemoveOptions(gcConfig.IncludeLocked || gcConfig.IncludeDirty, mergedConfig.Rm.Branch)
+	if err != nil {
+		return err
+	}
+	opts.forceBranch = gcConfig.Force
+
+	failed := 0
+	for _, c := range candidates {
+		if err := removeWorktree(c.worktree, opts); err != nil {
+			// Keep going so one broken worktree doesn't block the rest of the cleanup
+			fmt.Printf("⚠ %v\n", err)
+			failed++
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("failed to remove %d of %d worktree(s)", failed, len(candidates))
+	}
+
+	return nil
+}
+
+// collectGcCandidates selects the worktrees that gw gc should remove
+func collectGcCandidates(worktrees []git.Worktree, state *gcState) []gcCandidate {
+	var current *git.Worktree
+	if state.currentPath != "" {
+		current = findCurrentWorktree(state.currentPath, worktrees)
+	}
+
+	var candidates []gcCandidate
+	for i := range worktrees {
+		wt := &worktrees[i]
+		if wt.IsMain || wt.IsBare {
+			continue
+		}
+
+		reason, ok := gcReason(wt, lookupMetadata(state.recorded, wt), state)
+		if !ok {
+			continue
+		}
+
+		if wt.Locked && !state.includeLocked {
+			fmt.Printf("ℹ Skipping locked worktree: %s\n", wt.Path)
+			continue
+		}
+		if current != nil && current.Path == wt.Path {
+			fmt.Printf("ℹ Skipping current worktree: %s\n", wt.Path)
+			continue
+		}
+		if !wt.Prunable && !state.includeDirty {
+			dirty, err := state.isDirty(wt.Path)
+			if err != nil {
+				fmt.Printf("⚠ Skipping %s: %v\n", wt.Path, err)
+				continue
+			}
+			if dirty {
+				fmt.Printf("ℹ Skipping worktree with uncommitted changes: %s\n", wt.Path)
+				continue
+			}
+		}
+
+		candidates = append(candidates, gcCandidate{worktree: wt, reason: reason})
+	}
+
+	return candidates
+}
+
+// gcReason reports whether wt with the given metadata matches the gc criteria,
+// and describes why
+func gcReason(wt *git.Worktree, m *metadata.Worktree, state *gcState) (string, bool) {
+	if m == nil {
+		return "", false
+	}
+	// The metadata is keyed by path, so it may describe an earlier worktree at
+	// the same path or a branch that was switched away from
+	if m.Branch != wt.Branch {
+		return "", false
+	}
+
+	var parts []string
+	if state.olderThan > 0 {
+		if m.CreatedAt == nil || state.now.Sub(*m.CreatedAt) < state.olderThan {
+			return "", false
+		}
+		parts = append(parts, "created "+formatAge(state.now.Sub(*m.CreatedAt)))
+	}
+	if state.unusedFor > 0 {
+		lastUsed := m.LastUsedAt
+		if lastUsed == nil {
+			lastUsed = m.CreatedAt
+		}
+		if lastUsed == nil || state.now.Sub(*lastUsed) < state.unusedFor {
+			return "", false
+		}
+		parts = append(parts, "last used "+formatAge(state.now.Sub(*lastUsed)))
+	}
+	return strings.Join(parts, ", "), true
+}
+
+// parseAge parses an age such as "30d", "2w" or "12h"
+func parseAge(s string) (time.Duration, error) {
+	var d time.Duration
+	var err error
+	switch {
+	case strings.HasSuffix(s, "d"), strings.HasSuffix(s, "w"):
+		unit := 24 * time.Hour
+		if strings.HasSuffix(s, "w") {
+			unit *= 7
+		}
+		var n int
+		n, err = strconv.Atoi(s[:len(s)-1])
+		d = time.Duration(n) * unit
+	default:
+		d, err = time.ParseDuration(s)
+	}
+	if err != nil || d <= 0 {
+		return 0, errors.NewInvalidInputError(s, "invalid age (use e.g. 30d, 2w or 12h)", nil)
+	}
+	return d, nil
+}
@@ -0,0 +1,202 @@
+package cmd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/t98o84/gw/internal/git"
+	"github.com/t98o84/gw/internal/metadata"
+)
+
+func TestGcCmd_Flags(t *testing.T) {
+	tests := []struct {
+		name      string
+		shorthand string
+	}{
+		{name: "older-than"},
+		{name: "unused-for"},
+		{name: "yes", shorthand: "y"},
+		{name: "branch", shorthand: "b"},
+		{name: "force", shorthand: "f"},
+		{name: "include-locked"},
+		{name: "include-dirty"},
+		{name: "dry-run", shorthand: "n"},
+		{name: "no-yes"},
+		{name: "no-branch"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			flag := gcCmd.Flags().Lookup(tt.name)
+			if flag == nil {
+				t.Fatalf("Expected %q flag to be defined", tt.name)
+			}
+			if flag.Shorthand != tt.shorthand {
+				t.Errorf("%s flag shorthand = %q, want %q", tt.name, flag.Shorthand, tt.shorthand)
+			}
+		})
+	}
+}
+
+func TestCollectGcCandidates(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	daysAgo := func(n int) *time.Time {
+		t := now.Add(-time.Duration(n) * 24 * time.Hour)
+		return &t
+	}
+
+	worktrees := []git.Worktree{
+		{Path: "/repo", Branch: "main", IsMain: true},
+		{Path: "/repo-old", Branch: "old"},
+		{Path: "/repo-old-used", Branch: "old-used"},
+		{Path: "/repo-new", Branch: "new"},
+		{Path: "/repo-unknown", Branch: "unknown"},
+		{Path: "/repo-never-used", Branch: "never-used"},
+		{Path: "/repo-locked", Branch: "locked", Locked: true},
+		{Path: "/repo-dirty", Branch: "dirty"},
+		{Path: "/repo-current", Branch: "current"},
+		{Path: "/repo-stale", Branch: "stale", Prunable: true},
+		{Path: "/repo-reused", Branch: "reused"},
+		{Path: "/repo-switched", Branch: "switched"},
+	}
+	recorded := map[string]metadata.Worktree{
+		"/repo":                {Branch: "main", LastUsedAt: daysAgo(100)},
+		"/repo-old":            {Branch: "old", CreatedAt: daysAgo(40), LastUsedAt: daysAgo(20)},
+		"/repo-old-used":       {Branch: "old-used", CreatedAt: daysAgo(40), LastUsedAt: daysAgo(1)},
+		"/repo-new":            {Branch: "new", CreatedAt: daysAgo(5), LastUsedAt: daysAgo(5)},
+		"/repo-never-used":     {Branch: "never-used", CreatedAt: daysAgo(60)},
+		"/repo-locked":         {Branch: "locked", CreatedAt: daysAgo(40), LastUsedAt: daysAgo(20)},
+		"/repo-dirty":          {Branch: "dirty", CreatedAt: daysAgo(40), LastUsedAt: daysAgo(20)},
+		"/repo-current":        {Branch: "current", CreatedAt: daysAgo(40), LastUsedAt: daysAgo(20)},
+		"/repo-stale":          {Branch: "stale", CreatedAt: daysAgo(40), LastUsedAt: daysAgo(20)},
+		"/repo-only-last-used": {LastUsedAt: daysAgo(40)},
+		// Left behind by an earlier worktree at the same path
+		"/repo-reused": {Branch: "earlier", CreatedAt: daysAgo(60)},
+		// The branch was switched after the worktree was created
+		"/repo-switched": {Branch: "before-switch", CreatedAt: daysAgo(60)},
+	}
+
+	tests := []struct {
+		name          string
+		olderThan     time.Duration
+		unusedFor     time.Duration
+		includeLocked bool
+		includeDirty  bool
+		want          []string
+	}{
+		{
+			name:      "older than",
+			olderThan: 30 * 24 * time.Hour,
+			want:      []string{"/repo-old", "/repo-old-used", "/repo-never-used", "/repo-stale"},
+		},
+		{
+			name:      "unused for",
+			unusedFor: 14 * 24 * time.Hour,
+			want:      []string{"/repo-old", "/repo-never-used", "/repo-stale"},
+		},
+		{
+			name:      "both criteria must match",
+			olderThan: 50 * 24 * time.Hour,
+			unusedFor: 14 * 24 * time.Hour,
+			want:      []string{"/repo-never-used"},
+		},
+		{
+			name:          "include locked worktrees",
+			unusedFor:     14 * 24 * time.Hour,
+			includeLocked: true,
+			want:          []string{"/repo-old", "/repo-never-used", "/repo-locked", "/repo-stale"},
+		},
+		{
+			name:         "include dirty worktrees",
+			unusedFor:    14 * 24 * time.Hour,
+			includeDirty: true,
+			want:         []string{"/repo-old", "/repo-never-used", "/repo-dirty", "/repo-stale"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			state := &gcState{
+				now:           now,
+				olderThan:     tt.olderThan,
+				unusedFor:     tt.unusedFor,
+				includeLocked: tt.includeLocked,
+				includeDirty:  tt.includeDirty,
+				recorded:      recorded,
+				currentPath:   "/repo-current/sub",
+				isDirty: func(path string) (bool, error) {
+					return path == "/repo-dirty", nil
+				},
+			}
+
+			got := collectGcCandidates(worktrees, state)
+			var paths []string
+			for _, c := range got {
+				paths = append(paths, c.worktree.Path)
+			}
+			if len(paths) != len(tt.want) {
+				t.Fatalf("collectGcCandidates() = %v, want %v", paths, tt.want)
+			}
+			for i := range paths {
+				if paths[i] != tt.want[i] {
+					t.Errorf("collectGcCandidates() = %v, want %v", paths, tt.want)
+					break
+				}
+			}
+		})
+	}
+}
+
+func TestGcReason(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	created := now.Add(-40 * 24 * time.Hour)
+	used := now.Add(-20 * 24 * time.Hour)
+	state := &gcState{now: now, olderThan: 30 * 24 * time.Hour, unusedFor: 14 * 24 * time.Hour}
+
+	wt := &git.Worktree{Path: "/repo-feature", Branch: "feature"}
+	reason, ok := gcReason(wt, &metadata.Worktree{Branch: "feature", CreatedAt: &created, LastUsedAt: &used}, state)
+	if !ok {
+		t.Fatal("gcReason() = false, want true")
+	}
+	if want := "created 1 month ago, last used 20 days ago"; reason != want {
+		t.Errorf("gcReason() = %q, want %q", reason, want)
+	}
+
+	if _, ok := gcReason(wt, nil, state); ok {
+		t.Error("gcReason(nil) = true, want false for worktrees without metadata")
+	}
+	if _, ok := gcReason(wt, &metadata.Worktree{Branch: "other", CreatedAt: &created, LastUsedAt: &used}, state); ok {
+		t.Error("gcReason() = true, want false for metadata recorded for another branch")
+	}
+}
+
+func TestParseAge(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    time.Duration
+		wantErr bool
+	}{
+		{input: "30d", want: 30 * 24 * time.Hour},
+		{input: "2w", want: 14 * 24 * time.Hour},
+		{input: "12h", want: 12 * time.Hour},
+		{input: "90m", want: 90 * time.Minute},
+		{input: "0d", wantErr: true},
+		{input: "-1d", wantErr: true},
+		{input: "d", wantErr: true},
+		{input: "1.5d", wantErr: true},
+		{input: "30", wantErr: true},
+		{input: "month", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := parseAge(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseAge(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("parseAge(%q) = %v, want %v", tt.input, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,123 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/t98o84/gw/internal/errors"
+	"github.com/t98o84/gw/internal/git"
+	"github.com/t98o84/gw/internal/metadata"
+)
+
+var infoJSON bool
+
+var infoCmd = &cobra.Command{
+	Use:   "info [flags] [name]",
+	Short: "Show details of a worktree",
+	Long: `Show the details of a worktree, including what gw recorded when it was created.
+
+gw keeps a small metadata file in the git directory shared by all worktrees
+(<git common dir>/gw/worktrees.json). It records:
+  - Base: the ref the branch was created from with 'gw add -b'
+    (used by 'gw status' and 'gw rebase' instead of the default branch)
+  - PR: the pull request given to 'gw add --pr'
+  - Created: when the worktree was created with gw
+  - Last used: when the worktree was last entered with 'gw sw' or 'gw exec'
+
+Worktrees created without gw have no creation data until they are used.
+
+If no name is specified and fzf is available, an interactive selector will be shown.
+
+Examples:
+  gw info feature/hoge
+  gw info feature/hoge --json
+  gw info              # Interactive selection with fzf`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runInfo,
+}
+
+func init() {
+	infoCmd.Flags().BoolVar(&infoJSON, "json", false, "Print the details as JSON")
+	rootCmd.AddCommand(infoCmd)
+}
+
+func runInfo(cmd *cobra.Command, args []string) error {
+	var wt *git.Worktree
+	var err error
+
+	if len(args) == 0 {
+		wt, err = selectWorktreeWithFzf(false, false)
+		if err != nil {
+			return err
+		}
+		if wt == nil {
+			return nil // User cancelled
+		}
+	} else {
+		identifier := args[0]
+		wt, err = git.FindWorktree(identifier)
+		if err != nil {
+			return fmt.Errorf("failed to find worktree: %w", err)
+		}
+		if wt == nil {
+			return errors.NewWorktreeNotFoundError(identifier, nil)
+		}
+	}
+
+	meta := lookupMetadata(loadMetadata(), wt)
+
+	if infoJSON {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(newWorktreeJSON(wt, meta, detachedTags(wt, git.TagsAt)))
+	}
+
+	fmt.Print(formatWorktreeInfo(wt, meta, git.TagsAt, time.Now()))
+	return nil
+}
+
+// formatWorktreeInfo formats the details of a worktree for gw info
+func formatWorktreeInfo(wt *git.Worktree, meta *metadata.Worktree, tagsAt func(commit string) ([]string, error), now time.Time) string {
+	orDash := func(s string) string {
+		if s == "" {
+			return "-"
+		}
+		return s
+	}
+	formatTime := func(t *time.Time) string {
+		if t == nil {
+			return "-"
+		}
+		return fmt.Sprintf("%s (%s)", t.Local().Format("2006-01-02 15:04"), formatAge(now.Sub(*t)))
+	}
+
+	var b strings.Builder
+	w := tabwriter.NewWriter(&b, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(w, "Name:\t%s\n", filepath.Base(wt.Path))
+	fmt.Fprintf(w, "Path:\t%s\n", wt.Path)
+	fmt.Fprintf(w, "Branch:\t%s\n", orDash(branchLabel(wt, tagsAt)))
+	fmt.Fprintf(w, "Commit:\t%s\n", orDash(shortHash(wt.Commit)))
+	if markers := wt.Markers(); len(markers) > 0 {
+		fmt.Fprintf(w, "State:\t%s\n", strings.Join(markers, " "))
+	}
+	if wt.LockReason != "" {
+		fmt.Fprintf(w, "Lock reason:\t%s\n", wt.LockReason)
+	}
+
+	if meta == nil {
+		meta = &metadata.Worktree{}
+	}
+	fmt.Fprintf(w, "Base:\t%s\n", orDash(meta.Base))
+	fmt.Fprintf(w, "PR:\t%s\n", orDash(meta.PR))
+	fmt.Fprintf(w, "Created:\t%s\n", formatTime(meta.CreatedAt))
+	fmt.Fprintf(w, "Last used:\t%s\n", formatTime(meta.LastUsedAt))
+	w.Flush()
+
+	return b.String()
+}
@@ -0,0 +1,68 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/t98o84/gw/internal/git"
+	"github.com/t98o84/gw/internal/metadata"
+)
+
+func TestInfoCmd_Flags(t *testing.T) {
+	if infoCmd.Flags().Lookup("json") == nil {
+		t.Error("Expected 'json' flag to be defined")
+	}
+}
+
+func TestFormatWorktreeInfo(t *testing.T) {
+	now := time.Date(2024, 5, 4, 12, 0, 0, 0, time.UTC)
+	created := now.Add(-3 * 24 * time.Hour)
+	noTags := func(commit string) ([]string, error) { return nil, nil }
+
+	tests := []struct {
+		name     string
+		wt       git.Worktree
+		meta     *metadata.Worktree
+		contains []string
+	}{
+		{
+			name: "recorded worktree",
+			wt:   git.Worktree{Path: "/path/to/repo-feature", Branch: "feature", Commit: "1a2b3c4d5e6f", Locked: true, LockReason: "release"},
+			meta: &metadata.Worktree{Branch: "feature", Base: "origin/main", PR: "123", CreatedAt: &created, LastUsedAt: &now},
+			contains: []string{
+				"Name:         repo-feature\n",
+				"Branch:       feature\n",
+				"Commit:       1a2b3c4\n",
+				"State:        (locked)\n",
+				"Lock reason:  release\n",
+				"Base:         origin/main\n",
+				"PR:           123\n",
+				"(3 days ago)\n",
+				"(just now)\n",
+			},
+		},
+		{
+			name: "worktree without metadata",
+			wt:   git.Worktree{Path: "/path/to/repo", Branch: "main", Commit: "1a2b3c4d5e6f", IsMain: true},
+			contains: []string{
+				"State:      (main)\n",
+				"Base:       -\n",
+				"PR:         -\n",
+				"Created:    -\n",
+				"Last used:  -\n",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := formatWorktreeInfo(&tt.wt, tt.meta, noTags, now)
+			for _, want := range tt.contains {
+				if !strings.Contains(got, want) {
+					t.Errorf("formatWorktreeInfo() = %q, want it to contain %q", got, want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,105 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/t98o84/gw/internal/errors"
+	"github.com/t98o84/gw/internal/git"
+)
+
+var lockReason string
+
+var lockCmd = &cobra.Command{
+	Use:   "lock [flags] [name]",
+	Short: "Lock a worktree to protect it from removal",
+	Long: `Lock a worktree with 'git worktree lock'.
+
+Locked worktrees are marked with (locked) in 'gw ls', are never removed by
+'gw prune', and are refused by 'gw rm' unless --force is given. This is
+useful for worktrees on removable disks or long-running release branches.
+
+The name can be a branch name, suffix, directory name or full path.
+
+Examples:
+  gw lock release/1.2 --reason "hotfix worktree on external disk"
+  gw lock              # Interactive selection with fzf`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runLock,
+}
+
+var unlockCmd = &cobra.Command{
+	Use:   "unlock [name]",
+	Short: "Unlock a locked worktree",
+	Long: `Unlock a worktree that was locked with 'gw lock' or 'git worktree lock'.
+
+Examples:
+  gw unlock release/1.2
+  gw unlock            # Interactive selection with fzf`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runUnlock,
+}
+
+func init() {
+	lockCmd.Flags().StringVarP(&lockReason, "reason", "r", "", "Reason for locking the worktree")
+	rootCmd.AddCommand(lockCmd)
+	rootCmd.AddCommand(unlockCmd)
+}
+
+func runLock(cmd *cobra.Command, args []string) error {
+	wt, err := resolveLockTarget(args)
+	if err != nil || wt == nil {
+		return err
+	}
+
+	if wt.Locked {
+		fmt.Printf("ℹ Worktree is already locked: %s\n", wt.Path)
+		return nil
+	}
+
+	if err := git.Lock(wt.Path, lockReason); err != nil {
+		return fmt.Errorf("failed to lock %s: %w", wt.Path, err)
+	}
+	fmt.Printf("✓ Worktree locked: %s\n", wt.Path)
+	return nil
+}
+
+func runUnlock(cmd *cobra.Command, args []string) error {
+	wt, err := resolveLockTarget(args)
+	if err != nil || wt == nil {
+		return err
+	}
+
+	if !wt.Locked {
+		fmt.Printf("ℹ Worktree is not locked: %s\n", wt.Path)
+		return nil
+	}
+
+	if err := git.Unlock(wt.Path); err != nil {
+		return fmt.Errorf("failed to unlock %s: %w", wt.Path, err)
+	}
+	fmt.Printf("✓ Worktree unlocked: %s\n", wt.Path)
+	return nil
+}
+
+// resolveLockTarget finds the worktree to lock or unlock from the arguments,
+// falling back to fzf selection. Returns nil if the user cancelled.
+func resolveLockTarget(args []string) (*git.Worktree, error) {
+	if len(args) == 0 {
+		// The main worktree can't be locked, so don't offer it
+		return selectWorktreeWithFzf(true, false)
+	}
+
+	identifier := args[0]
+	wt, err := git.FindWorktree(identifier)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find worktree: %w", err)
+	}
+	if wt == nil {
+		return nil, errors.NewWorktreeNotFoundError(identifier, nil)
+	}
+	if wt.IsMain || wt.IsBare {
+		return nil, errors.NewInvalidInputError(identifier, "the main worktree cannot be locked or unlocked", nil)
+	}
+	return wt, nil
+}
@@ -0,0 +1,45 @@
+package cmd
+
+import (
+	"testing"
+)
+
+func TestLockCmd(t *testing.T) {
+	if lockCmd == nil {
+		t.Fatal("lockCmd should not be nil")
+	}
+
+	if lockCmd.Use != "lock [flags] [name]" {
+		t.Errorf("lockCmd.Use = %q, want %q", lockCmd.Use, "lock [flags] [name]")
+	}
+}
+
+func TestLockCmd_ReasonFlag(t *testing.T) {
+	flag := lockCmd.Flags().Lookup("reason")
+	if flag == nil {
+		t.Fatal("Expected 'reason' flag to be defined")
+	}
+
+	if flag.Shorthand != "r" {
+		t.Errorf("reason flag shorthand = %q, want %q", flag.Shorthand, "r")
+	}
+}
+
+func TestUnlockCmd(t *testing.T) {
+	if unlockCmd == nil {
+		t.Fatal("unlockCmd should not be nil")
+	}
+
+	if unlockCmd.Use != "unlock [name]" {
+		t.Errorf("unlockCmd.Use = %q, want %q", unlockCmd.Use, "unlock [name]")
+	}
+}
+
+func TestLockCmd_MaxOneArg(t *testing.T) {
+	if err := lockCmd.Args(lockCmd, []string{"a", "b"}); err == nil {
+		t.Error("lockCmd should reject more than one argument")
+	}
+	if err := unlockCmd.Args(unlockCmd, []string{"a", "b"}); err == nil {
+		t.Error("unlockCmd should reject more than one argument")
+	}
+}
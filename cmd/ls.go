@@ -1,14 +1,32 @@
 package cmd
 
 import (
+	"encoding/json"
 	"fmt"
+	"os"
+	"path"
 	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+	"time"
 
 	"github.com/spf13/cobra"
+	"github.com/t98o84/gw/internal/errors"
 	"github.com/t98o84/gw/internal/git"
+	"github.com/t98o84/gw/internal/metadata"
 )
 
-var lsPrintPath bool
+// lsJSONVersion is the version of the gw ls --json output format
+const lsJSONVersion = 1
+
+var (
+	lsPrintPath bool
+	lsJSON      bool
+	lsFormat    string
+	lsSort      string
+	lsFilters   []string
+)
 
 var lsCmd = &cobra.Command{
 	Use:     "ls",
@@ -16,26 +34,212 @@ var lsCmd = &cobra.Command{
 	Short:   "List all worktrees",
 	Long: `List all worktrees for the current repository.
 
-Shows the directory name for each worktree.`,
+Shows the directory name, branch and commit for each worktree, followed by
+state markers: (main), (bare), (locked) and (prunable). Detached worktrees are
+shown as "(detached)"; the tags at their commit are in the .tags JSON field and
+the .Tags template field.
+
+A worktree is prunable when git has lost track of its directory
+(e.g. it was deleted by hand). Use 'gw rm' to clean it up.
+
+Output formats:
+  --json      A JSON object with a "version" field and a "worktrees" array.
+              The version is incremented when the format changes incompatibly.
+  --format    A Go template executed for each worktree. Available fields:
+              .Name, .Path, .Branch, .Commit, .ShortCommit, .IsMain, .IsBare,
+              .IsDetached, .Tags, .Locked, .LockReason and .Prunable, and the
+              recorded .Base, .PR, .CreatedAt and .LastUsedAt (see 'gw info')
+
+Sorting (--sort): name, branch or path. Without --sort, the worktrees are
+listed in the order git lists them.
+
+Filters (--filter, can be repeated; all filters must match):
+  branch=<glob>         Branch matches the glob (* does not match /)
+  main, no-main         Only the main worktree / all but the main worktree
+  detached, no-detached Only detached / only non-detached worktrees
+  locked, no-locked     Only locked / only unlocked worktrees
+  prunable, no-prunable Only prunable / only non-prunable worktrees
+
+Examples:
+  gw ls --json
+  gw ls --format '{{.Branch}} {{.Path}}'
+  gw ls --sort branch --filter 'branch=feature/*'
+  gw ls --filter no-main --filter no-locked -p`,
 	RunE: runLs,
 }
 
 func init() {
 	lsCmd.Flags().BoolVarP(&lsPrintPath, "path", "p", false, "Print the full path instead of directory name")
+	lsCmd.Flags().BoolVar(&lsJSON, "json", false, "Print the worktrees as JSON")
+	lsCmd.Flags().StringVar(&lsFormat, "format", "", "Print each worktree using a Go template")
+	lsCmd.Flags().StringVar(&lsSort, "sort", "", "Sort by name, branch or path")
+	lsCmd.Flags().StringArrayVar(&lsFilters, "filter", nil, "Only list matching worktrees (branch=<glob>, [no-]main, [no-]detached, [no-]locked, [no-]prunable)")
 	rootCmd.AddCommand(lsCmd)
 }
 
+// worktreeJSON is the JSON representation of a worktree.
+// Field names are part of the versioned output of gw ls --json and gw status --json.
+type worktreeJSON struct {
+	Name     string `json:"name"`
+	Path     string `json:"path"`
+	Branch   string `json:"branch"`
+	Commit   string `json:"commit"`
+	Main     bool   `json:"main"`
+	Bare     bool   `json:"bare"`
+	Detached bool   `json:"detached"`
+	// Tags are the tags at the commit of a detached worktree
+	Tags       []string `json:"tags,omitempty"`
+	Locked     bool     `json:"locked"`
+	LockReason string   `json:"lock_reason,omitempty"`
+	Prunable   bool     `json:"prunable"`
+	// Metadata is what gw recorded about the worktree, if anything
+	Metadata *metadata.Worktree `json:"metadata,omitempty"`
+}
+
+// newWorktreeJSON converts a worktree, its recorded metadata and the tags at a
+// detached worktree's commit to their JSON representation
+func newWorktreeJSON(wt *git.Worktree, meta *metadata.Worktree, tags []string) worktreeJSON {
+	return worktreeJSON{
+		Name:       filepath.Base(wt.Path),
+		Path:       wt.Path,
+		Branch:     wt.Branch,
+		Commit:     wt.Commit,
+		Main:       wt.IsMain,
+		Bare:       wt.IsBare,
+		Detached:   wt.IsDetached,
+		Tags:       tags,
+		Locked:     wt.Locked,
+		LockReason: wt.LockReason,
+		Prunable:   wt.Prunable,
+		Metadata:   meta,
+	}
+}
+
+// lsOutput is the document printed by gw ls --json
+type lsOutput struct {
+	Version   int            `json:"version"`
+	Worktrees []worktreeJSON `json:"worktrees"`
+}
+
+// lsTemplateData is the data passed to --format templates.
+// The fields are a documented, stable interface (see the gw ls help), so they
+// are copied explicitly instead of exposing git.Worktree.
+type lsTemplateData struct {
+	// Name is the directory name of the worktree
+	Name   string
+	Path   string
+	Branch string
+	Commit string
+	// ShortCommit is the abbreviated commit hash
+	ShortCommit string
+	IsMain      bool
+	IsBare      bool
+	IsDetached  bool
+	// Tags are the tags at the commit of a detached worktree
+	Tags       []string
+	Locked     bool
+	LockReason string
+	Prunable   bool
+	// Base and PR are the recorded base branch and pull request, if any
+	Base string
+	PR   string
+	// CreatedAt and LastUsedAt are the zero time when they were not recorded
+	CreatedAt  time.Time
+	LastUsedAt time.Time
+}
+
+// newLsTemplateData returns the --format template data of a worktree
+func newLsTemplateData(wt git.Worktree, meta *metadata.Worktree, tags []string) lsTemplateData {
+	data := lsTemplateData{
+		Name:        filepath.Base(wt.Path),
+		Path:        wt.Path,
+		Branch:      wt.Branch,
+		Commit:      wt.Commit,
+		ShortCommit: shortHash(wt.Commit),
+		IsMain:      wt.IsMain,
+		IsBare:      wt.IsBare,
+		IsDetached:  wt.IsDetached,
+		Tags:        tags,
+		Locked:      wt.Locked,
+		LockReason:  wt.LockReason,
+		Prunable:    wt.Prunable,
+	}
+	if meta != nil {
+		data.Base = meta.Base
+		data.PR = meta.PR
+		if meta.CreatedAt != nil {
+			data.CreatedAt = *meta.CreatedAt
+		}
+		if meta.LastUsedAt != nil {
+			data.LastUsedAt = *meta.LastUsedAt
+		}
+	}
+	return data
+}
+
+// lsFilter reports whether a worktree should be listed
+type lsFilter func(wt *git.Worktree) bool
+
 func runLs(cmd *cobra.Command, args []string) error {
+	modes := 0
+	for _, enabled := range []bool{lsPrintPath, lsJSON, lsFormat != ""} {
+		if enabled {
+			modes++
+		}
+	}
+	if modes > 1 {
+		return fmt.Errorf("cannot use --path, --json and --format together")
+	}
+
+	filters, err := parseLsFilters(lsFilters)
+	if err != nil {
+		return err
+	}
+	var tmpl *template.Template
+	if lsFormat != "" {
+		tmpl, err = template.New("format").Option("missingkey=error").Parse(lsFormat)
+		if err != nil {
+			return errors.NewInvalidInputError(lsFormat, fmt.Sprintf("invalid template: %v", err), nil)
+		}
+	}
+
 	worktrees, err := git.List()
 	if err != nil {
 		return fmt.Errorf("failed to list worktrees: %w", err)
 	}
 
-	if len(worktrees) == 0 {
+	if len(worktrees) == 0 && !lsJSON && tmpl == nil {
 		fmt.Println("No worktrees found")
 		return nil
 	}
 
+	worktrees = filterWorktrees(worktrees, filters)
+	if err := sortWorktrees(worktrees, lsSort); err != nil {
+		return err
+	}
+
+	switch {
+	case lsJSON:
+		meta := loadMetadata()
+		output := lsOutput{Version: lsJSONVersion, Worktrees: []worktreeJSON{}}
+		for i := range worktrees {
+			output.Worktrees = append(output.Worktrees, newWorktreeJSON(&worktrees[i], lookupMetadata(meta, &worktrees[i]), detachedTags(&worktrees[i], git.TagsAt)))
+		}
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(output)
+	case tmpl != nil:
+		meta := loadMetadata()
+		for _, wt := range worktrees {
+			data := newLsTemplateData(wt, lookupMetadata(meta, &wt), detachedTags(&wt, git.TagsAt))
+			if err := tmpl.Execute(os.Stdout, data); err != nil {
+				return fmt.Errorf("failed to execute format template: %w", err)
+			}
+			fmt.Println()
+		}
+		return nil
+	}
+
 	for _, wt := range worktrees {
 		if lsPrintPath {
 			// -p flag specified, output full path only
@@ -43,13 +247,14 @@ func runLs(cmd *cobra.Command, args []string) error {
 		} else {
 			// -p flag not specified, output detailed information
 			name := filepath.Base(wt.Path)
+			// The tab format is a stable interface for scripts, so the tags are left out
 			branch := wt.Branch
-			if branch == "" {
+			if branch == "" && !wt.IsBare {
 				branch = "(detached)"
 			}
 			output := fmt.Sprintf("%s\t%s\t%s", name, branch, shortHash(wt.Commit))
-			if wt.IsMain {
-				output += "\t(main)"
+			for _, marker := range wt.Markers() {
+				output += "\t" + marker
 			}
 			fmt.Println(output)
 		}
@@ -58,6 +263,106 @@ func runLs(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// parseLsFilters parses the --filter values
+func parseLsFilters(values []string) ([]lsFilter, error) {
+	var filters []lsFilter
+	for _, value := range values {
+		if pattern, ok := strings.CutPrefix(value, "branch="); ok {
+			if _, err := path.Match(pattern, ""); err != nil {
+				return nil, errors.NewInvalidInputError(value, "invalid branch glob", nil)
+			}
+			filters = append(filters, func(wt *git.Worktree) bool {
+				matched, _ := path.Match(pattern, wt.Branch)
+				return wt.Branch != "" && matched
+			})
+			continue
+		}
+
+		name, negated := strings.CutPrefix(value, "no-")
+		var state func(wt *git.Worktree) bool
+		switch name {
+		case "main":
+			state = func(wt *git.Worktree) bool { return wt.IsMain }
+		case "detached":
+			state = func(wt *git.Worktree) bool { return wt.IsDetached }
+		case "locked":
+			state = func(wt *git.Worktree) bool { return wt.Locked }
+		case "prunable":
+			state = func(wt *git.Worktree) bool { return wt.Prunable }
+		default:
+			return nil, errors.NewInvalidInputError(value, "unknown filter (use branch=<glob>, [no-]main, [no-]detached, [no-]locked or [no-]prunable)", nil)
+		}
+		filters = append(filters, func(wt *git.Worktree) bool { return state(wt) != negated })
+	}
+	return filters, nil
+}
+
+// filterWorktrees returns the worktrees that match all filters
+func filterWorktrees(worktrees []git.Worktree, filters []lsFilter) []git.Worktree {
+	var matched []git.Worktree
+	for i := range worktrees {
+		ok := true
+		for _, filter := range filters {
+			if !filter(&worktrees[i]) {
+				ok = false
+				break
+			}
+		}
+		if ok {
+			matched = append(matched, worktrees[i])
+		}
+	}
+	return matched
+}
+
+// sortWorktrees sorts worktrees in place by the given key.
+// An empty key keeps the order git lists them in.
+func sortWorktrees(worktrees []git.Worktree, key string) error {
+	var value func(wt *git.Worktree) string
+	switch key {
+	case "":
+		return nil
+	case "name":
+		value = func(wt *git.Worktree) string { return filepath.Base(wt.Path) }
+	case "branch":
+		value = func(wt *git.Worktree) string { return wt.Branch }
+	case "path":
+		value = func(wt *git.Worktree) string { return wt.Path }
+	default:
+		return errors.NewInvalidInputError(key, "unknown sort key (use name, branch or path)", nil)
+	}
+
+	sort.SliceStable(worktrees, func(i, j int) bool {
+		return value(&worktrees[i]) < value(&worktrees[j])
+	})
+	return nil
+}
+
+// branchLabel returns the branch shown for a worktree by gw info and gw status.
+// Detached worktrees are labeled with the tags at their commit, if any.
+func branchLabel(wt *git.Worktree, tagsAt func(commit string) ([]string, error)) string {
+	if wt.Branch != "" || wt.IsBare {
+		return wt.Branch
+	}
+	if tags := detachedTags(wt, tagsAt); len(tags) > 0 {
+		return "(detached " + strings.Join(tags, ", ") + ")"
+	}
+	return "(detached)"
+}
+
+// detachedTags returns the tags at the commit of a detached worktree, or nil
+// for worktrees on a branch. Failures to list the tags are ignored.
+func detachedTags(wt *git.Worktree, tagsAt func(commit string) ([]string, error)) []string {
+	if wt.Branch != "" || wt.IsBare || wt.Commit == "" {
+		return nil
+	}
+	tags, err := tagsAt(wt.Commit)
+	if err != nil || len(tags) == 0 {
+		return nil
+	}
+	return tags
+}
+
 // shortHash returns the first 7 characters of a commit hash
 func shortHash(hash string) string {
 	if len(hash) > 7 {
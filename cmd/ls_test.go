@@ -1,7 +1,17 @@
 package cmd
 
 import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"strings"
 	"testing"
+	"text/template"
+	"time"
+
+	"github.com/t98o84/gw/internal/git"
+	"github.com/t98o84/gw/internal/metadata"
 )
 
 func TestLsCmd(t *testing.T) {
@@ -40,3 +50,220 @@ func TestLsCmd_PathFlag(t *testing.T) {
 		t.Errorf("path flag shorthand = %q, want %q", flag.Shorthand, "p")
 	}
 }
+
+func TestBranchLabel(t *testing.T) {
+	tagsAt := func(commit string) ([]string, error) {
+		switch commit {
+		case "abc123":
+			return []string{"v1.2.0", "latest"}, nil
+		case "fail":
+			return nil, fmt.Errorf("git error")
+		}
+		return nil, nil
+	}
+
+	tests := []struct {
+		name string
+		wt   git.Worktree
+		want string
+	}{
+		{name: "branch", wt: git.Worktree{Branch: "feature/a", Commit: "abc123"}, want: "feature/a"},
+		{name: "bare", wt: git.Worktree{IsBare: true}, want: ""},
+		{name: "detached at tags", wt: git.Worktree{Commit: "abc123", IsDetached: true}, want: "(detached v1.2.0, latest)"},
+		{name: "detached without tags", wt: git.Worktree{Commit: "def456", IsDetached: true}, want: "(detached)"},
+		{name: "tag lookup fails", wt: git.Worktree{Commit: "fail", IsDetached: true}, want: "(detached)"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := branchLabel(&tt.wt, tagsAt); got != tt.want {
+				t.Errorf("branchLabel() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLsCmd_OutputFlags(t *testing.T) {
+	for _, name := range []string{"json", "format", "sort", "filter"} {
+		if lsCmd.Flags().Lookup(name) == nil {
+			t.Errorf("Expected '%s' flag to be defined", name)
+		}
+	}
+}
+
+func TestParseLsFilters(t *testing.T) {
+	worktrees := []git.Worktree{
+		{Path: "/path/to/repo", Branch: "main", IsMain: true},
+		{Path: "/path/to/repo-feature-a", Branch: "feature/a"},
+		{Path: "/path/to/repo-feature-b", Branch: "feature/b", Locked: true},
+		{Path: "/path/to/repo-fix-deep-c", Branch: "fix/deep/c", Prunable: true},
+		{Path: "/path/to/repo-v1.2.0", Commit: "abc123", IsDetached: true},
+	}
+
+	tests := []struct {
+		name    string
+		filters []string
+		want    []string
+		wantErr bool
+	}{
+		{name: "no filters", filters: nil, want: []string{"repo", "repo-feature-a", "repo-feature-b", "repo-fix-deep-c", "repo-v1.2.0"}},
+		{name: "branch glob", filters: []string{"branch=feature/*"}, want: []string{"repo-feature-a", "repo-feature-b"}},
+		{name: "glob does not match slashes", filters: []string{"branch=fix/*"}, want: nil},
+		{name: "main", filters: []string{"main"}, want: []string{"repo"}},
+		{name: "no-main", filters: []string{"no-main"}, want: []string{"repo-feature-a", "repo-feature-b", "repo-fix-deep-c", "repo-v1.2.0"}},
+		{name: "detached", filters: []string{"detached"}, want: []string{"repo-v1.2.0"}},
+		{name: "locked", filters: []string{"locked"}, want: []string{"repo-feature-b"}},
+		{name: "prunable", filters: []string{"prunable"}, want: []string{"repo-fix-deep-c"}},
+		{name: "combined", filters: []string{"branch=feature/*", "no-locked"}, want: []string{"repo-feature-a"}},
+		{name: "unknown filter", filters: []string{"dirty"}, wantErr: true},
+		{name: "invalid glob", filters: []string{"branch=[feature"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filters, err := parseLsFilters(tt.filters)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseLsFilters() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			var got []string
+			for _, wt := range filterWorktrees(worktrees, filters) {
+				got = append(got, filepath.Base(wt.Path))
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("filterWorktrees() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSortWorktrees(t *testing.T) {
+	tests := []struct {
+		key     string
+		want    []string
+		wantErr bool
+	}{
+		{key: "", want: []string{"/b/repo", "/a/repo-zeta", "/c/repo-alpha"}},
+		{key: "name", want: []string{"/b/repo", "/c/repo-alpha", "/a/repo-zeta"}},
+		{key: "branch", want: []string{"/c/repo-alpha", "/b/repo", "/a/repo-zeta"}},
+		{key: "path", want: []string{"/a/repo-zeta", "/b/repo", "/c/repo-alpha"}},
+		{key: "size", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			worktrees := []git.Worktree{
+				{Path: "/b/repo", Branch: "main", IsMain: true},
+				{Path: "/a/repo-zeta", Branch: "zeta"},
+				{Path: "/c/repo-alpha", Branch: "alpha"},
+			}
+
+			err := sortWorktrees(worktrees, tt.key)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("sortWorktrees() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			var got []string
+			for _, wt := range worktrees {
+				got = append(got, wt.Path)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("sortWorktrees() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewWorktreeJSON(t *testing.T) {
+	wt := git.Worktree{Path: "/path/to/repo-hotfix", Branch: "hotfix", Commit: "abc123", Locked: true, LockReason: "release"}
+
+	data, err := json.Marshal(newWorktreeJSON(&wt, nil, nil))
+	if err != nil {
+		t.Fatalf("json.Marshal() error = %v", err)
+	}
+	want := `{"name":"repo-hotfix","path":"/path/to/repo-hotfix","branch":"hotfix","commit":"abc123","main":false,"bare":false,"detached":false,"locked":true,"lock_reason":"release","prunable":false}`
+	if string(data) != want {
+		t.Errorf("json.Marshal(newWorktreeJSON()) = %s, want %s", data, want)
+	}
+}
+
+func TestNewWorktreeJSON_Tags(t *testing.T) {
+	wt := git.Worktree{Path: "/path/to/repo-v1.2.0", Commit: "abc123", IsDetached: true}
+
+	data, err := json.Marshal(newWorktreeJSON(&wt, nil, []string{"v1.2.0", "latest"}))
+	if err != nil {
+		t.Fatalf("json.Marshal() error = %v", err)
+	}
+	want := `{"name":"repo-v1.2.0","path":"/path/to/repo-v1.2.0","branch":"","commit":"abc123","main":false,"bare":false,"detached":true,"tags":["v1.2.0","latest"],"locked":false,"prunable":false}`
+	if string(data) != want {
+		t.Errorf("json.Marshal(newWorktreeJSON()) = %s, want %s", data, want)
+	}
+
+	tmpl := template.Must(template.New("format").Parse("{{range .Tags}}{{.}} {{end}}"))
+	var b strings.Builder
+	if err := tmpl.Execute(&b, newLsTemplateData(wt, nil, []string{"v1.2.0", "latest"})); err != nil {
+		t.Fatalf("template.Execute() error = %v", err)
+	}
+	if got := b.String(); got != "v1.2.0 latest " {
+		t.Errorf("template output = %q, want %q", got, "v1.2.0 latest ")
+	}
+}
+
+func TestNewWorktreeJSON_Metadata(t *testing.T) {
+	wt := git.Worktree{Path: "/path/to/repo-hotfix", Branch: "hotfix", Commit: "abc123"}
+	created := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	meta := &metadata.Worktree{Branch: "hotfix", Base: "origin/main", PR: "42", CreatedAt: &created}
+
+	data, err := json.Marshal(newWorktreeJSON(&wt, meta, nil))
+	if err != nil {
+		t.Fatalf("json.Marshal() error = %v", err)
+	}
+	want := `"metadata":{"branch":"hotfix","base":"origin/main","pr":"42","created_at":"2024-05-01T12:00:00Z"}}`
+	if !strings.HasSuffix(string(data), want) {
+		t.Errorf("json.Marshal(newWorktreeJSON()) = %s, want it to end with %s", data, want)
+	}
+
+	tmpl := template.Must(template.New("format").Option("missingkey=error").Parse("{{.Branch}} {{.Base}} {{.PR}} {{.CreatedAt.Year}} {{.LastUsedAt.IsZero}}"))
+	var b strings.Builder
+	if err := tmpl.Execute(&b, newLsTemplateData(wt, meta, nil)); err != nil {
+		t.Fatalf("template.Execute() error = %v", err)
+	}
+	if got := b.String(); got != "hotfix origin/main 42 2024 true" {
+		t.Errorf("template output = %q, want %q", got, "hotfix origin/main 42 2024 true")
+	}
+}
+
+func TestNewLsTemplateData(t *testing.T) {
+	wt := git.Worktree{
+		Path:           "/path/to/repo-hotfix",
+		Branch:         "hotfix",
+		Commit:         "abc123def4567890",
+		Locked:         true,
+		LockReason:     "release",
+		Prunable:       true,
+		PrunableReason: "gitdir file points to non-existent location",
+	}
+
+	tmpl := template.Must(template.New("format").Parse(
+		"{{.Name}} {{.Path}} {{.Branch}} {{.Commit}} {{.ShortCommit}} {{.IsMain}} {{.IsBare}} {{.IsDetached}} {{.Locked}} {{.LockReason}} {{.Prunable}}"))
+	var b strings.Builder
+	if err := tmpl.Execute(&b, newLsTemplateData(wt, nil, nil)); err != nil {
+		t.Fatalf("template.Execute() error = %v", err)
+	}
+	want := "repo-hotfix /path/to/repo-hotfix hotfix abc123def4567890 abc123d false false false true release true"
+	if got := b.String(); got != want {
+		t.Errorf("template output = %q, want %q", got, want)
+	}
+
+	// Only the documented fields are available
+	tmpl = template.Must(template.New("format").Parse("{{.PrunableReason}}"))
+	if err := tmpl.Execute(&strings.Builder{}, newLsTemplateData(wt, nil, nil)); err == nil {
+		t.Error("template.Execute() expected an error for an undocumented field")
+	}
+}
@@ -0,0 +1,116 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/t98o84/gw/internal/git"
+	"github.com/t98o84/gw/internal/metadata"
+)
+
+// openMetadataStore opens the worktree metadata store of the current repository
+func openMetadataStore() (*metadata.Store, error) {
+	if mockMetadataStore != nil {
+		return mockMetadataStore()
+	}
+	commonDir, err := git.GetCommonDir()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get git common directory: %w", err)
+	}
+	return metadata.Open(commonDir), nil
+}
+
+// loadMetadata returns the metadata of all worktrees keyed by metadata.Key.
+// The metadata is informational, so a failure is only reported as a warning.
+func loadMetadata() map[string]metadata.Worktree {
+	store, err := openMetadataStore()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "⚠ Warning: Failed to load worktree metadata: %v\n", err)
+		return nil
+	}
+	worktrees, err := store.Load()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "⚠ Warning: Failed to load worktree metadata: %v\n", err)
+		return nil
+	}
+	return worktrees
+}
+
+// lookupMetadata returns the metadata recorded for wt, or nil if there is none
+func lookupMetadata(worktrees map[string]metadata.Worktree, wt *git.Worktree) *metadata.Worktree {
+	m, ok := worktrees[metadata.Key(wt.Path)]
+	if !ok {
+		return nil
+	}
+	return &m
+}
+
+// updateMetadata applies update to the metadata of the worktree at path.
+// Failures are only reported as warnings and never fail the command.
+func updateMetadata(path string, update func(m *metadata.Worktree)) {
+	store, err := openMetadataStore()
+	if err == nil {
+		err = store.Update(path, update)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "⚠ Warning: Failed to record worktree metadata: %v\n", err)
+	}
+}
+
+// touchWorktree records that wt was just used
+func touchWorktree(wt *git.Worktree) {
+	now := time.Now().UTC()
+	updateMetadata(wt.Path, func(m *metadata.Worktree) {
+		if m.Branch == "" {
+			m.Branch = wt.Branch
+		}
+		m.LastUsedAt = &now
+	})
+}
+
+// moveMetadata moves the metadata of a worktree that was moved from oldPath to newPath
+func moveMetadata(oldPath, newPath string) {
+	store, err := openMetadataStore()
+	if err == nil {
+		err = store.Move(oldPath, newPath)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "⚠ Warning: Failed to move worktree metadata: %v\n", err)
+	}
+}
+
+// forgetMetadata deletes the metadata of a removed worktree
+func forgetMetadata(path string) {
+	store, err := openMetadataStore()
+	if err == nil {
+		err = store.Delete(path)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "⚠ Warning: Failed to delete worktree metadata: %v\n", err)
+	}
+}
+
+// expireMetadata deletes the metadata of worktrees that git no longer lists,
+// so a new worktree created at the same path doesn't inherit it
+func expireMetadata(worktrees []git.Worktree) {
+	paths := make([]string, len(worktrees))
+	for i, wt := range worktrees {
+		paths[i] = wt.Path
+	}
+	store, err := openMetadataStore()
+	if err == nil {
+		err = store.Retain(paths)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "⚠ Warning: Failed to delete worktree metadata: %v\n", err)
+	}
+}
+
+// recordedBase returns the base recorded for wt, or fallback if none was recorded
+func recordedBase(worktrees map[string]metadata.Worktree, wt *git.Worktree, fallback string) string {
+	if m := lookupMetadata(worktrees, wt); m != nil && m.Base != "" {
+		return m.Base
+	}
+	return fallback
+}
@@ -0,0 +1,177 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/t98o84/gw/internal/errors"
+	"github.com/t98o84/gw/internal/git"
+	"github.com/t98o84/gw/internal/metadata"
+)
+
+var mvPath string
+
+var mvCmd = &cobra.Command{
+	Use:   "mv [flags] <name> [new-branch]",
+	Short: "Rename a worktree's branch and move its directory",
+	Long: `Rename the branch of a worktree and move its directory to match.
+
+The directory is moved to the location gw would use for the new branch name,
+so the worktree can still be found by its branch, suffix or directory name.
+Use --path to move the directory to a custom location instead. With --path and
+no new branch name, only the directory is moved.
+
+Main and locked worktrees cannot be moved.
+
+Examples:
+  gw mv feature/hoge feature/fuga
+    Renames the branch and moves ../ex-repo-feature-hoge to ../ex-repo-feature-fuga
+
+  gw mv feature/hoge --path ~/work/hoge
+    Moves the worktree directory only
+
+  gw mv feature/hoge feature/fuga --path ~/work/fuga
+    Renames the branch and moves the directory to a custom location`,
+	Args: cobra.RangeArgs(1, 2),
+	RunE: runMv,
+}
+
+func init() {
+	mvCmd.Flags().StringVar(&mvPath, "path", "", "Move the worktree directory to this location")
+	rootCmd.AddCommand(mvCmd)
+}
+
+func runMv(cmd *cobra.Command, args []string) error {
+	identifier := args[0]
+	var newBranch string
+	if len(args) == 2 {
+		newBranch = args[1]
+	}
+	if newBranch == "" && mvPath == "" {
+		return errors.NewInvalidInputError(identifier, "specify a new branch name or --path", nil)
+	}
+
+	wt, err := git.FindWorktree(identifier)
+	if err != nil {
+		return fmt.Errorf("failed to find worktree: %w", err)
+	}
+	if wt == nil {
+		return errors.NewWorktreeNotFoundError(identifier, nil)
+	}
+	if err := validateMoveSource(identifier, wt); err != nil {
+		return err
+	}
+
+	if newBranch != "" {
+		if wt.Branch == "" {
+			return errors.NewInvalidInputError(identifier, "worktree has no branch to rename (detached HEAD)", nil)
+		}
+		if newBranch == wt.Branch {
+			newBranch = ""
+		} else {
+			exists, err := git.BranchExists(newBranch)
+			if err != nil {
+				return fmt.Errorf("failed to check branch: %w", err)
+			}
+			if exists {
+				return errors.NewInvalidInputError(newBranch, "branch already exists", nil)
+			}
+		}
+	}
+
+	newPath, err := moveDestination(newBranch)
+	if err != nil {
+		return err
+	}
+	if newBranch == "" && filepath.Clean(newPath) == filepath.Clean(wt.Path) {
+		fmt.Println("Nothing to do")
+		return nil
+	}
+	if filepath.Clean(newPath) != filepath.Clean(wt.Path) {
+		if _, err := os.Stat(newPath); err == nil {
+			return errors.NewWorktreeExistsError(newPath, newBranch, nil)
+		}
+	}
+
+	if newBranch != "" {
+		fmt.Printf("Renaming branch %s to %s...\n", wt.Branch, newBranch)
+		if err := git.RenameBranch(wt.Branch, newBranch); err != nil {
+			return fmt.Errorf("failed to rename branch: %w", err)
+		}
+		fmt.Printf("✓ Branch renamed: %s\n", newBranch)
+	}
+
+	if filepath.Clean(newPath) != filepath.Clean(wt.Path) {
+		fmt.Printf("Moving worktree %s to %s...\n", wt.Path, newPath)
+		if err := os.MkdirAll(filepath.Dir(newPath), 0755); err != nil {
+			return fmt.Errorf("failed to create parent directory: %w", err)
+		}
+		if err := git.Move(wt.Path, newPath); err != nil {
+			if newBranch != "" {
+				// Roll back the rename so the branch and directory stay consistent
+				if rbErr := git.RenameBranch(newBranch, wt.Branch); rbErr != nil {
+					fmt.Printf("⚠ Failed to restore branch name %s: %v\n", wt.Branch, rbErr)
+				}
+			}
+			return fmt.Errorf("failed to move worktree: %w", err)
+		}
+		fmt.Printf("✓ Worktree moved: %s\n", newPath)
+
+		moveMetadata(wt.Path, newPath)
+
+		if cwd, err := os.Getwd(); err == nil && isPathWithin(cwd, wt.Path) {
+			fmt.Fprintf(os.Stderr, "ℹ Your shell is still in the old directory. Run 'gw sw %s' to follow the worktree.\n", filepath.Base(newPath))
+		}
+	}
+	if newBranch != "" {
+		updateMetadata(newPath, func(m *metadata.Worktree) { m.Branch = newBranch })
+	}
+
+	return nil
+}
+
+// validateMoveSource checks that the worktree can be moved
+func validateMoveSource(identifier string, wt *git.Worktree) error {
+	if wt.IsMain || wt.IsBare {
+		return errors.NewInvalidInputError(identifier, "cannot move the main worktree", nil)
+	}
+	if wt.Locked {
+		return errors.NewWorktreeLockedError(wt.Path, wt.LockReason, nil)
+	}
+	if wt.Prunable {
+		return errors.NewInvalidInputError(identifier, "worktree directory is missing (use 'gw prune' to clean it up)", nil)
+	}
+	return nil
+}
+
+// moveDestination determines the new directory of a moved worktree.
+// --path takes precedence; otherwise the naming convention for the new branch is used.
+func moveDestination(newBranch string) (string, error) {
+	if mvPath != "" {
+		path, err := filepath.Abs(mvPath)
+		if err != nil {
+			return "", fmt.Errorf("failed to resolve path %s: %w", mvPath, err)
+		}
+		return path, nil
+	}
+
+	repoName, err := git.GetRepoName()
+	if err != nil {
+		return "", fmt.Errorf("failed to get repository name: %w", err)
+	}
+	path, err := git.WorktreePath(repoName, newBranch)
+	if err != nil {
+		return "", fmt.Errorf("failed to generate worktree path: %w", err)
+	}
+	return path, nil
+}
+
+// isPathWithin reports whether path is dir itself or located inside dir
+func isPathWithin(path, dir string) bool {
+	path = filepath.Clean(path)
+	dir = filepath.Clean(dir)
+	return path == dir || strings.HasPrefix(path, dir+string(filepath.Separator))
+}
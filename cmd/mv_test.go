@@ -0,0 +1,78 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/t98o84/gw/internal/errors"
+	"github.com/t98o84/gw/internal/git"
+)
+
+func TestMvCmd(t *testing.T) {
+	if mvCmd == nil {
+		t.Fatal("mvCmd should not be nil")
+	}
+
+	if mvCmd.Use != "mv [flags] <name> [new-branch]" {
+		t.Errorf("mvCmd.Use = %q, want %q", mvCmd.Use, "mv [flags] <name> [new-branch]")
+	}
+}
+
+func TestMvCmd_PathFlag(t *testing.T) {
+	if flag := mvCmd.Flags().Lookup("path"); flag == nil {
+		t.Fatal("Expected 'path' flag to be defined")
+	}
+}
+
+func TestMvCmd_Args(t *testing.T) {
+	if err := mvCmd.Args(mvCmd, []string{}); err == nil {
+		t.Error("mvCmd should require at least one argument")
+	}
+	if err := mvCmd.Args(mvCmd, []string{"a", "b", "c"}); err == nil {
+		t.Error("mvCmd should reject more than two arguments")
+	}
+}
+
+func TestValidateMoveSource(t *testing.T) {
+	tests := []struct {
+		name       string
+		wt         *git.Worktree
+		wantErr    bool
+		wantLocked bool
+	}{
+		{name: "regular worktree", wt: &git.Worktree{Path: "/repo-feature", Branch: "feature"}},
+		{name: "main worktree", wt: &git.Worktree{Path: "/repo", Branch: "main", IsMain: true}, wantErr: true},
+		{name: "locked worktree", wt: &git.Worktree{Path: "/repo-hotfix", Locked: true}, wantErr: true, wantLocked: true},
+		{name: "prunable worktree", wt: &git.Worktree{Path: "/repo-gone", Prunable: true}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateMoveSource("id", tt.wt)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("validateMoveSource() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantLocked && !errors.IsWorktreeLockedError(err) {
+				t.Errorf("validateMoveSource() error = %v, want WorktreeLockedError", err)
+			}
+		})
+	}
+}
+
+func TestIsPathWithin(t *testing.T) {
+	tests := []struct {
+		path string
+		dir  string
+		want bool
+	}{
+		{path: "/repo-feature", dir: "/repo-feature", want: true},
+		{path: "/repo-feature/src/", dir: "/repo-feature", want: true},
+		{path: "/repo-feature-other", dir: "/repo-feature", want: false},
+		{path: "/repo", dir: "/repo-feature", want: false},
+	}
+
+	for _, tt := range tests {
+		if got := isPathWithin(tt.path, tt.dir); got != tt.want {
+			t.Errorf("isPathWithin(%q, %q) = %v, want %v", tt.path, tt.dir, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,38 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// Mock function for testing - nil in production
+var mockConfirm func(prompt string) (bool, error)
+
+// confirm asks a yes/no question on stderr and reads the answer from stdin.
+// Anything other than "y" or "yes" is treated as no.
+func confirm(prompt string) (bool, error) {
+	if mockConfirm != nil {
+		return mockConfirm(prompt)
+	}
+	return confirmWithReader(prompt, os.Stdin)
+}
+
+// confirmWithReader asks a yes/no question and reads the answer from the given reader
+func confirmWithReader(prompt string, in io.Reader) (bool, error) {
+	fmt.Fprintf(os.Stderr, "%s [y/N]: ", prompt)
+
+	answer, err := bufio.NewReader(in).ReadString('\n')
+	if err != nil && err != io.EOF {
+		return false, fmt.Errorf("failed to read answer: %w", err)
+	}
+
+	switch strings.ToLower(strings.TrimSpace(answer)) {
+	case "y", "yes":
+		return true, nil
+	default:
+		return false, nil
+	}
+}
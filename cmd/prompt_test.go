@@ -0,0 +1,33 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestConfirmWithReader(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  bool
+	}{
+		{name: "yes", input: "y\n", want: true},
+		{name: "full yes with spaces", input: "  YES \n", want: true},
+		{name: "no", input: "n\n", want: false},
+		{name: "empty answer defaults to no", input: "\n", want: false},
+		{name: "EOF defaults to no", input: "", want: false},
+		{name: "answer without newline", input: "y", want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := confirmWithReader("Continue?", strings.NewReader(tt.input))
+			if err != nil {
+				t.Fatalf("confirmWithReader() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("confirmWithReader() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
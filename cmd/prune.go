@@ -0,0 +1,293 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	"github.com/t98o84/gw/internal/config"
+	"github.com/t98o84/gw/internal/git"
+)
+
+var pruneConfig = struct {
+	Yes      bool
+	Branch   bool
+	Force    bool
+	DryRun   bool
+	Fetch    bool
+	NoYes    bool
+	NoBranch bool
+}{}
+
+var pruneCmd = &cobra.Command{
+	Use:   "prune [flags]",
+	Short: "Remove stale and merged worktrees",
+	Long: `Find and remove worktrees that are no longer needed.
+
+A worktree is a candidate for removal when:
+  - Its directory is gone (git reports it as prunable)
+  - Its branch is already merged into the default branch
+  - Its branch's upstream was deleted on the remote
+
+The main worktree, locked worktrees, the current worktree and worktrees with
+uncommitted changes are never removed. Branches that point at the same commit
+as the default branch are treated as new rather than merged or deleted upstream.
+
+The candidates are listed and a confirmation prompt is shown unless --yes is
+given or rm.force is set in the config file. The pre_remove and post_remove
+hooks from gw.yaml are executed for each removed worktree. With --branch, only
+branches merged into the default branch are deleted unless --force is given,
+so commits that were never pushed or merged are kept.
+
+Examples:
+  gw prune             # List candidates and confirm removal
+  gw prune -n          # Only list candidates
+  gw prune --fetch     # Fetch with --prune first to detect deleted upstreams
+  gw prune -y -b       # Remove without confirmation and delete merged branches
+  gw prune -b -f       # Also delete branches that aren't merged`,
+	Args: cobra.NoArgs,
+	RunE: runPrune,
+}
+
+func init() {
+	pruneCmd.Flags().BoolVarP(&pruneConfig.Yes, "yes", "y", false, "Skip confirmation prompt")
+	pruneCmd.Flags().BoolVarP(&pruneConfig.Branch, "branch", "b", false, "Also delete the associated git branches")
+	pruneCmd.Flags().BoolVarP(&pruneConfig.Force, "force", "f", false, "Delete the branches with --branch even if they aren't merged")
+	pruneCmd.Flags().BoolVarP(&pruneConfig.DryRun, "dry-run", "n", false, "Only list the worktrees that would be removed")
+	pruneCmd.Flags().BoolVar(&pruneConfig.Fetch, "fetch", false, "Run 'git fetch --all --prune' before looking for candidates")
+	// Negation flags
+	pruneCmd.Flags().BoolVar(&pruneConfig.NoYes, "no-yes", false, "Force disable automatic confirmation (overrides config and --yes)")
+	pruneCmd.Flags().BoolVar(&pruneConfig.NoBranch, "no-branch", false, "Force disable branch deletion (overrides config and --branch)")
+	rootCmd.AddCommand(pruneCmd)
+}
+
+// pruneReason describes why a worktree is a prune candidate
+type pruneReason string
+
+const (
+	pruneReasonStale  pruneReason = "directory is gone"
+	pruneReasonMerged pruneReason = "branch is merged"
+	pruneReasonGone   pruneReason = "upstream branch was deleted"
+)
+
+// pruneCandidate is a worktree selected for removal by gw prune
+type pruneCandidate struct {
+	worktree *git.Worktree
+	reason   pruneReason
+}
+
+// pruneState holds the repository state used to select prune candidates
+type pruneState struct {
+	// merged is the set of branches merged into the default branch
+	merged map[string]bool
+	// gone is the set of branches whose upstream was deleted
+	gone map[string]bool
+	// baseCommit is the commit of the default branch
+	baseCommit string
+	// currentPath is the path of the worktree gw is running in
+	currentPath string
+	// isDirty reports whether a worktree has uncommitted changes
+	isDirty func(path string) (bool, error)
+}
+
+func runPrune(cmd *cobra.Command, args []string) error {
+	// Load configuration
+	cfg := config.LoadOrDefault()
+
+	// Validate flag conflicts
+	if pruneConfig.Yes && pruneConfig.NoYes {
+		return fmt.Errorf("cannot use --yes and --no-yes together")
+	}
+	if pruneConfig.Branch && pruneConfig.NoBranch {
+		return fmt.Errorf("cannot use --branch and --no-branch together")
+	}
+
+	// Merge with command-line flags (flags take precedence)
+	var yesFlagPtr *bool
+	if cmd.Flags().Changed("yes") {
+		yesFlagPtr = &pruneConfig.Yes
+	}
+	var branchFlagPtr *bool
+	if cmd.Flags().Changed("branch") {
+		branchFlagPtr = &pruneConfig.Branch
+	}
+	mergedConfig := cfg.MergeWithFlags(
+		nil,
+		nil,
+		nil,
+		yesFlagPtr,
+		branchFlagPtr,
+		nil,
+		nil,
+		nil,
+		nil,
+		false,
+		false,
+		false,
+		false,
+		pruneConfig.NoYes,
+		pruneConfig.NoBranch,
+		false,
+	)
+
+	if pruneConfig.Fetch {
+		fmt.Println("Fetching remotes...")
+		if err := git.FetchAll(true); err != nil {
+			return fmt.Errorf("failed to fetch: %w", err)
+		}
+	}
+
+	state, err := loadPruneState()
+	if err != nil {
+		return err
+	}
+
+	worktrees, err := git.List()
+	if err != nil {
+		return fmt.Errorf("failed to list worktrees: %w", err)
+	}
+
+	candidates := collectPruneCandidates(worktrees, state)
+	if len(candidates) == 0 {
+		fmt.Println("No worktrees to prune")
+		return nil
+	}
+
+	fmt.Println("Worktrees to remove:")
+	for _, c := range candidates {
+		fmt.Printf("  %s\t%s\t(%s)\n", filepath.Base(c.worktree.Path), c.worktree.Branch, c.reason)
+	}
+
+	if pruneConfig.DryRun {
+		return nil
+	}
+
+	if !mergedConfig.Rm.Force {
+		ok, err := confirm(fmt.Sprintf("Remove %d worktree(s)?", len(candidates)))
+		if err != nil {
+			return err
+		}
+		if !ok {
+			fmt.Println("Aborted")
+			return nil
+		}
+	}
+
+	// Skipping the prompt doesn't force the removal: branches whose upstream was
+	// deleted may hold unmerged work, so only --force deletes unmerged branches
+	opts, err := newRemoveOptions(pruneConfig.Force, mergedConfig.Rm.Branch)
+	if err != nil {
+		return err
+	}
+
+	failed := 0
+	for _, c := range candidates {
+		if err := removeWorktree(c.worktree, opts); err != nil {
+			// Keep going so one broken worktree doesn't block the rest of the cleanup
+			fmt.Printf("⚠ %v\n", err)
+			failed++
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("failed to remove %d of %d worktree(s)", failed, len(candidates))
+	}
+
+	return nil
+}
+
+// loadPruneState collects the repository state needed to select prune candidates
+func loadPruneState() (*pruneState, error) {
+	state := &pruneState{
+		merged:  make(map[string]bool),
+		gone:    make(map[string]bool),
+		isDirty: git.IsDirty,
+	}
+
+	if cwd, err := os.Getwd(); err == nil {
+		state.currentPath = cwd
+	}
+
+	defaultBranch, err := git.DefaultBranch()
+	if err != nil {
+		return nil, err
+	}
+	state.baseCommit, err = git.ResolveCommit(defaultBranch)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve %s: %w", defaultBranch, err)
+	}
+
+	merged, err := git.MergedBranches(defaultBranch)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list merged branches: %w", err)
+	}
+	for _, branch := range merged {
+		state.merged[branch] = true
+	}
+
+	gone, err := git.GoneBranches()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list branches with deleted upstreams: %w", err)
+	}
+	for _, branch := range gone {
+		state.gone[branch] = true
+	}
+
+	return state, nil
+}
+
+// collectPruneCandidates selects the worktrees that gw prune should remove
+func collectPruneCandidates(worktrees []git.Worktree, state *pruneState) []pruneCandidate {
+	var current *git.Worktree
+	if state.currentPath != "" {
+		current = findCurrentWorktree(state.currentPath, worktrees)
+	}
+
+	var candidates []pruneCandidate
+	for i := range worktrees {
+		wt := &worktrees[i]
+		if wt.IsMain || wt.IsBare {
+			continue
+		}
+
+		var reason pruneReason
+		switch {
+		case wt.Prunable:
+			reason = pruneReasonStale
+		case wt.Branch == "":
+			continue
+		case state.gone[wt.Branch] && wt.Commit != state.baseCommit:
+			// A branch still at the default branch's commit has nothing to lose, but it
+			// also has nothing that was merged or pushed: it is new, not done
+			reason = pruneReasonGone
+		case state.merged[wt.Branch] && wt.Commit != state.baseCommit:
+			reason = pruneReasonMerged
+		default:
+			continue
+		}
+
+		if wt.Locked {
+			fmt.Printf("ℹ Skipping locked worktree: %s\n", wt.Path)
+			continue
+		}
+		if current != nil && current.Path == wt.Path {
+			fmt.Printf("ℹ Skipping current worktree: %s\n", wt.Path)
+			continue
+		}
+		if !wt.Prunable {
+			dirty, err := state.isDirty(wt.Path)
+			if err != nil {
+				fmt.Printf("⚠ Skipping %s: %v\n", wt.Path, err)
+				continue
+			}
+			if dirty {
+				fmt.Printf("ℹ Skipping worktree with uncommitted changes: %s\n", wt.Path)
+				continue
+			}
+		}
+
+		candidates = append(candidates, pruneCandidate{worktree: wt, reason: reason})
+	}
+
+	return candidates
+}
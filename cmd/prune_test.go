@@ -0,0 +1,111 @@
+package cmd
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/t98o84/gw/internal/git"
+)
+
+func TestPruneCmd(t *testing.T) {
+	if pruneCmd == nil {
+		t.Fatal("pruneCmd should not be nil")
+	}
+
+	if pruneCmd.Use != "prune [flags]" {
+		t.Errorf("pruneCmd.Use = %q, want %q", pruneCmd.Use, "prune [flags]")
+	}
+}
+
+func TestPruneCmd_Flags(t *testing.T) {
+	tests := []struct {
+		name      string
+		shorthand string
+	}{
+		{name: "yes", shorthand: "y"},
+		{name: "branch", shorthand: "b"},
+		{name: "force", shorthand: "f"},
+		{name: "dry-run", shorthand: "n"},
+		{name: "fetch"},
+		{name: "no-yes"},
+		{name: "no-branch"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			flag := pruneCmd.Flags().Lookup(tt.name)
+			if flag == nil {
+				t.Fatalf("Expected %q flag to be defined", tt.name)
+			}
+			if flag.Shorthand != tt.shorthand {
+				t.Errorf("%s flag shorthand = %q, want %q", tt.name, flag.Shorthand, tt.shorthand)
+			}
+		})
+	}
+}
+
+func TestCollectPruneCandidates(t *testing.T) {
+	worktrees := []git.Worktree{
+		{Path: "/repo", Branch: "main", Commit: "base", IsMain: true},
+		{Path: "/repo-stale", Branch: "stale", Commit: "111", Prunable: true},
+		{Path: "/repo-merged", Branch: "merged", Commit: "222"},
+		{Path: "/repo-gone", Branch: "gone", Commit: "333"},
+		{Path: "/repo-new", Branch: "new", Commit: "base"},
+		{Path: "/repo-active", Branch: "active", Commit: "444"},
+		{Path: "/repo-locked", Branch: "locked", Commit: "555", Locked: true},
+		{Path: "/repo-dirty", Branch: "dirty", Commit: "666"},
+		{Path: "/repo-current", Branch: "current", Commit: "777"},
+		{Path: "/repo-detached", Commit: "888", IsDetached: true},
+		{Path: "/repo-unpushed", Branch: "unpushed", Commit: "base"},
+	}
+	state := &pruneState{
+		merged: map[string]bool{
+			"main": true, "merged": true, "new": true, "locked": true, "dirty": true, "current": true,
+		},
+		gone:        map[string]bool{"gone": true, "unpushed": true},
+		baseCommit:  "base",
+		currentPath: "/repo-current/sub/dir",
+		isDirty: func(path string) (bool, error) {
+			return path == "/repo-dirty", nil
+		},
+	}
+
+	got := collectPruneCandidates(worktrees, state)
+
+	want := map[string]pruneReason{
+		"/repo-stale":  pruneReasonStale,
+		"/repo-merged": pruneReasonMerged,
+		"/repo-gone":   pruneReasonGone,
+	}
+	if len(got) != len(want) {
+		t.Fatalf("collectPruneCandidates() returned %d candidates, want %d: %+v", len(got), len(want), got)
+	}
+	for _, c := range got {
+		reason, ok := want[c.worktree.Path]
+		if !ok {
+			t.Errorf("unexpected candidate %s", c.worktree.Path)
+			continue
+		}
+		if c.reason != reason {
+			t.Errorf("candidate %s reason = %q, want %q", c.worktree.Path, c.reason, reason)
+		}
+	}
+}
+
+func TestCollectPruneCandidates_DirtyCheckFails(t *testing.T) {
+	worktrees := []git.Worktree{
+		{Path: "/repo", Branch: "main", IsMain: true},
+		{Path: "/repo-merged", Branch: "merged", Commit: "222"},
+	}
+	state := &pruneState{
+		merged: map[string]bool{"merged": true},
+		gone:   map[string]bool{},
+		isDirty: func(path string) (bool, error) {
+			return false, fmt.Errorf("git status failed")
+		},
+	}
+
+	if got := collectPruneCandidates(worktrees, state); len(got) != 0 {
+		t.Errorf("collectPruneCandidates() = %+v, want no candidates when dirty check fails", got)
+	}
+}
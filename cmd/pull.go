@@ -0,0 +1,201 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/t98o84/gw/internal/errors"
+	"github.com/t98o84/gw/internal/git"
+)
+
+var pullConfig = struct {
+	Jobs    int
+	NoFetch bool
+}{}
+
+var pullCmd = &cobra.Command{
+	Use:     "pull [flags] [name...]",
+	Aliases: []string{"update"},
+	Short:   "Fetch once and fast-forward every worktree",
+	Long: `Fetch all remotes once and fast-forward the branch of every worktree to its
+upstream branch.
+
+Worktrees are updated concurrently (use --jobs to limit the number at a time).
+A worktree is skipped when:
+  - It has uncommitted changes
+  - Its branch has no upstream, or the upstream branch was deleted
+  - Its branch has diverged from the upstream (rebase or merge it yourself)
+  - It is detached, its directory is missing, or it is the bare repository
+
+Each worktree is reported on its own line. Give names to update only some
+worktrees.
+
+Examples:
+  gw pull                       # Fetch and fast-forward all worktrees
+  gw pull main feature/hoge     # Only update these worktrees
+  gw pull --no-fetch            # Fast-forward to the already fetched branches`,
+	RunE: runPull,
+}
+
+func init() {
+	pullCmd.Flags().IntVarP(&pullConfig.Jobs, "jobs", "j", defaultJobs(), "Number of worktrees to update concurrently")
+	pullCmd.Flags().BoolVar(&pullConfig.NoFetch, "no-fetch", false, "Don't fetch before fast-forwarding")
+	rootCmd.AddCommand(pullCmd)
+}
+
+// pullOutcome is the result of updating a worktree
+type pullOutcome int
+
+const (
+	pullUpdated pullOutcome = iota
+	pullUpToDate
+	pullSkipped
+	pullFailed
+)
+
+// pullResult is the result of updating a worktree with gw pull
+type pullResult struct {
+	worktree *git.Worktree
+	outcome  pullOutcome
+	message  string
+}
+
+// pullState holds the git operations used by gw pull
+type pullState struct {
+	status      func(path string) (*git.WorktreeStatus, error)
+	fastForward func(path string) error
+}
+
+func runPull(cmd *cobra.Command, args []string) error {
+	if pullConfig.Jobs < 1 {
+		return fmt.Errorf("--jobs must be at least 1")
+	}
+
+	var worktrees []git.Worktree
+	var err error
+	if len(args) > 0 {
+		worktrees, err = selectPullTargets(args)
+		if err != nil {
+			return err
+		}
+	} else {
+		worktrees, err = git.List()
+		if err != nil {
+			return fmt.Errorf("failed to list worktrees: %w", err)
+		}
+	}
+
+	if !pullConfig.NoFetch {
+		fmt.Println("Fetching remotes...")
+		if err := git.FetchAll(false); err != nil {
+			return fmt.Errorf("failed to fetch: %w", err)
+		}
+	}
+
+	state := &pullState{
+		status:      git.Status,
+		fastForward: git.FastForward,
+	}
+	results := collectConcurrently(worktrees, pullConfig.Jobs, func(wt *git.Worktree) pullResult {
+		return pullWorktree(wt, state)
+	})
+
+	counts := make(map[pullOutcome]int)
+	for _, r := range results {
+		counts[r.outcome]++
+		name := r.worktree.Branch
+		if name == "" {
+			name = r.worktree.Path
+		}
+		switch r.outcome {
+		case pullUpdated:
+			fmt.Printf("✓ %s: %s\n", name, r.message)
+		case pullUpToDate:
+			fmt.Printf("ℹ %s: %s\n", name, r.message)
+		case pullSkipped:
+			fmt.Printf("⚠ %s: skipped, %s\n", name, r.message)
+		case pullFailed:
+			fmt.Printf("⚠ %s: failed, %s\n", name, r.message)
+		}
+	}
+	fmt.Printf("Updated %d, up to date %d, skipped %d, failed %d\n",
+		counts[pullUpdated], counts[pullUpToDate], counts[pullSkipped], counts[pullFailed])
+
+	if counts[pullFailed] > 0 {
+		return fmt.Errorf("failed to update %d worktree(s)", counts[pullFailed])
+	}
+	return nil
+}
+
+// selectPullTargets finds the worktrees given on the command line
+func selectPullTargets(names []string) ([]git.Worktree, error) {
+	var worktrees []git.Worktree
+	for _, name := range names {
+		wt, err := git.FindWorktree(name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to find worktree: %w", err)
+		}
+		if wt == nil {
+			return nil, errors.NewWorktreeNotFoundError(name, nil)
+		}
+		worktrees = append(worktrees, *wt)
+	}
+	return worktrees, nil
+}
+
+// pullWorktree fast-forwards a single worktree to its upstream branch if it is safe to do so
+func pullWorktree(wt *git.Worktree, state *pullState) pullResult {
+	result := pullResult{worktree: wt, outcome: pullSkipped}
+	switch {
+	case wt.IsBare:
+		result.message = "bare repository"
+		return result
+	case wt.Prunable:
+		result.message = "directory is missing"
+		return result
+	case wt.Branch == "":
+		result.message = "detached HEAD"
+		return result
+	}
+
+	st, err := state.status(wt.Path)
+	if err != nil {
+		result.outcome = pullFailed
+		result.message = err.Error()
+		return result
+	}
+
+	switch {
+	case st.Staged > 0 || st.Modified > 0 || st.Conflicts > 0:
+		result.message = "uncommitted changes"
+	case st.Upstream == "":
+		result.message = "no upstream branch"
+	case st.UpstreamGone:
+		result.message = fmt.Sprintf("upstream %s was deleted", st.Upstream)
+	case st.Behind == 0 && st.Ahead > 0:
+		result.outcome = pullUpToDate
+		result.message = fmt.Sprintf("up to date (%s ahead of %s)", countCommits(st.Ahead), st.Upstream)
+	case st.Behind == 0:
+		result.outcome = pullUpToDate
+		result.message = "up to date"
+	case st.Ahead > 0:
+		result.message = fmt.Sprintf("diverged from %s (↑%d ↓%d)", st.Upstream, st.Ahead, st.Behind)
+	default:
+		if err := state.fastForward(wt.Path); err != nil {
+			result.outcome = pullFailed
+			result.message = err.Error()
+			return result
+		}
+		result.outcome = pullUpdated
+		result.message = fmt.Sprintf("fast-forwarded %s from %s", countCommits(st.Behind), st.Upstream)
+	}
+	return result
+}
+
+// countCommits formats a number of commits such as "1 commit" or "3 commits"
+func countCommits(n int) string {
+	if n == 1 {
+		return "1 commit"
+	}
+	return fmt.Sprintf("%d commits", n)
+}
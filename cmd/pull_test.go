@@ -0,0 +1,194 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/t98o84/gw/internal/git"
+)
+
+func TestPullCmd_Flags(t *testing.T) {
+	for _, name := range []string{"jobs", "no-fetch"} {
+		if pullCmd.Flags().Lookup(name) == nil {
+			t.Errorf("Expected '%s' flag to be defined", name)
+		}
+	}
+	if len(pullCmd.Aliases) != 1 || pullCmd.Aliases[0] != "update" {
+		t.Errorf("pullCmd.Aliases = %v, want [update]", pullCmd.Aliases)
+	}
+}
+
+func TestPullWorktree(t *testing.T) {
+	tests := []struct {
+		name        string
+		wt          git.Worktree
+		status      *git.WorktreeStatus
+		statusErr   error
+		ffErr       error
+		wantOutcome pullOutcome
+		wantFF      bool
+	}{
+		{
+			name:        "behind upstream",
+			wt:          git.Worktree{Path: "/path/to/repo", Branch: "main"},
+			status:      &git.WorktreeStatus{Upstream: "origin/main", Behind: 3},
+			wantOutcome: pullUpdated,
+			wantFF:      true,
+		},
+		{
+			name:        "untracked files don't block the update",
+			wt:          git.Worktree{Path: "/path/to/repo", Branch: "main"},
+			status:      &git.WorktreeStatus{Untracked: 2, Upstream: "origin/main", Behind: 1},
+			wantOutcome: pullUpdated,
+			wantFF:      true,
+		},
+		{
+			name:        "up to date",
+			wt:          git.Worktree{Path: "/path/to/repo", Branch: "main"},
+			status:      &git.WorktreeStatus{Upstream: "origin/main"},
+			wantOutcome: pullUpToDate,
+		},
+		{
+			name:        "ahead of upstream",
+			wt:          git.Worktree{Path: "/path/to/repo", Branch: "main"},
+			status:      &git.WorktreeStatus{Upstream: "origin/main", Ahead: 2},
+			wantOutcome: pullUpToDate,
+		},
+		{
+			name:        "diverged",
+			wt:          git.Worktree{Path: "/path/to/repo", Branch: "main"},
+			status:      &git.WorktreeStatus{Upstream: "origin/main", Ahead: 1, Behind: 1},
+			wantOutcome: pullSkipped,
+		},
+		{
+			name:        "uncommitted changes",
+			wt:          git.Worktree{Path: "/path/to/repo", Branch: "main"},
+			status:      &git.WorktreeStatus{Modified: 1, Upstream: "origin/main", Behind: 1},
+			wantOutcome: pullSkipped,
+		},
+		{
+			name:        "no upstream",
+			wt:          git.Worktree{Path: "/path/to/repo", Branch: "local"},
+			status:      &git.WorktreeStatus{},
+			wantOutcome: pullSkipped,
+		},
+		{
+			name:        "upstream deleted",
+			wt:          git.Worktree{Path: "/path/to/repo", Branch: "feature/a"},
+			status:      &git.WorktreeStatus{Upstream: "origin/feature/a", UpstreamGone: true},
+			wantOutcome: pullSkipped,
+		},
+		{
+			name:        "detached",
+			wt:          git.Worktree{Path: "/path/to/repo-v1", IsDetached: true},
+			wantOutcome: pullSkipped,
+		},
+		{
+			name:        "prunable",
+			wt:          git.Worktree{Path: "/path/to/repo-gone", Branch: "gone", Prunable: true},
+			wantOutcome: pullSkipped,
+		},
+		{
+			name:        "status fails",
+			wt:          git.Worktree{Path: "/path/to/repo", Branch: "main"},
+			statusErr:   fmt.Errorf("git error"),
+			wantOutcome: pullFailed,
+		},
+		{
+			name:        "fast-forward fails",
+			wt:          git.Worktree{Path: "/path/to/repo", Branch: "main"},
+			status:      &git.WorktreeStatus{Upstream: "origin/main", Behind: 1},
+			ffErr:       fmt.Errorf("untracked file would be overwritten"),
+			wantOutcome: pullFailed,
+			wantFF:      true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ffCalled := false
+			state := &pullState{
+				status: func(path string) (*git.WorktreeStatus, error) {
+					return tt.status, tt.statusErr
+				},
+				fastForward: func(path string) error {
+					ffCalled = true
+					return tt.ffErr
+				},
+			}
+
+			result := pullWorktree(&tt.wt, state)
+			if result.outcome != tt.wantOutcome {
+				t.Errorf("pullWorktree() outcome = %v (%s), want %v", result.outcome, result.message, tt.wantOutcome)
+			}
+			if ffCalled != tt.wantFF {
+				t.Errorf("pullWorktree() fast-forward called = %v, want %v", ffCalled, tt.wantFF)
+			}
+		})
+	}
+}
+
+func TestRunPull(t *testing.T) {
+	requireGit(t)
+
+	base, err := filepath.EvalSymlinks(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	origin := filepath.Join(base, "origin.git")
+	seed := filepath.Join(base, "seed")
+	repo := filepath.Join(base, "repo")
+
+	gitOutput(t, base, "init", "-q", "--bare", "-b", "main", origin)
+	gitOutput(t, base, "clone", "-q", origin, seed)
+	gitOutput(t, seed, "commit", "-q", "--allow-empty", "-m", "initial commit")
+	for _, branch := range []string{"main", "feature/a", "feature/b", "feature/c"} {
+		gitOutput(t, seed, "push", "-q", "origin", "HEAD:refs/heads/"+branch)
+	}
+
+	gitOutput(t, base, "clone", "-q", origin, repo)
+	for _, branch := range []string{"feature/a", "feature/b", "feature/c"} {
+		path := filepath.Join(base, "repo-"+filepath.Base(branch))
+		gitOutput(t, repo, "worktree", "add", "-q", "--track", "-b", branch, path, "origin/"+branch)
+	}
+	gitOutput(t, repo, "worktree", "add", "-q", "-b", "local", filepath.Join(base, "repo-local"))
+
+	// feature/b has uncommitted changes and feature/c a local commit
+	if err := os.WriteFile(filepath.Join(base, "repo-b", "b.txt"), []byte("b\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	gitOutput(t, filepath.Join(base, "repo-b"), "add", "b.txt")
+	gitOutput(t, filepath.Join(base, "repo-c"), "commit", "-q", "--allow-empty", "-m", "local change")
+
+	// Publish new commits for every branch
+	for _, branch := range []string{"main", "feature/a", "feature/b", "feature/c"} {
+		gitOutput(t, seed, "commit", "-q", "--allow-empty", "-m", "update "+branch)
+		gitOutput(t, seed, "push", "-q", "origin", "HEAD:refs/heads/"+branch)
+	}
+	before := map[string]string{
+		"repo-b": gitOutput(t, filepath.Join(base, "repo-b"), "rev-parse", "HEAD"),
+		"repo-c": gitOutput(t, filepath.Join(base, "repo-c"), "rev-parse", "HEAD"),
+	}
+
+	chdirForTest(t, repo)
+	pullConfig.Jobs = 2
+	pullConfig.NoFetch = false
+	if err := runPull(pullCmd, nil); err != nil {
+		t.Fatalf("runPull() error = %v", err)
+	}
+
+	for dir, branch := range map[string]string{"repo": "main", "repo-a": "feature/a"} {
+		got := gitOutput(t, filepath.Join(base, dir), "rev-parse", "HEAD")
+		want := gitOutput(t, origin, "rev-parse", branch)
+		if got != want {
+			t.Errorf("%s HEAD = %s, want %s (fast-forwarded to origin/%s)", dir, got, want, branch)
+		}
+	}
+	for dir, want := range before {
+		if got := gitOutput(t, filepath.Join(base, dir), "rev-parse", "HEAD"); got != want {
+			t.Errorf("%s HEAD = %s, want it unchanged (%s)", dir, got, want)
+		}
+	}
+}
@@ -0,0 +1,244 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/t98o84/gw/internal/errors"
+	"github.com/t98o84/gw/internal/git"
+)
+
+var rebaseConfig = struct {
+	All   bool
+	Onto  string
+	Fetch bool
+}{}
+
+var rebaseCmd = &cobra.Command{
+	Use:   "rebase [flags] [name...]",
+	Short: "Rebase worktree branches onto their base branch",
+	Long: `Rebase the branches of the given worktrees onto their base branch.
+
+The base branch is the branch the worktree's branch was created from, as
+recorded by 'gw add -b' (see 'gw info'), or else the default branch of the
+repository (e.g. origin/main). --onto overrides it for all worktrees. Each
+rebase runs in the worktree's directory, one worktree after another.
+
+A worktree is skipped when it has uncommitted changes, a rebase is already in
+progress, or it is detached. When a rebase stops on conflicts, that worktree
+is left mid-rebase so the conflicts can be resolved there with
+'git rebase --continue' (or undone with 'git rebase --abort'), and gw moves on
+to the next worktree. A summary is printed at the end.
+
+Without names or --all, worktrees are selected interactively with fzf (Tab to
+multi-select). --all selects every worktree except the main worktree.
+
+Examples:
+  gw rebase feature/hoge feature/fuga   # Rebase onto their base branches
+  gw rebase --all --fetch               # Fetch, then rebase all worktrees
+  gw rebase --all --onto origin/develop # Rebase onto another branch
+  gw rebase                             # Select worktrees with fzf`,
+	RunE: runRebase,
+}
+
+func init() {
+	rebaseCmd.Flags().BoolVarP(&rebaseConfig.All, "all", "a", false, "Rebase all worktrees except the main worktree")
+	rebaseCmd.Flags().StringVar(&rebaseConfig.Onto, "onto", "", "Rebase onto this ref instead of the base branch")
+	rebaseCmd.Flags().BoolVar(&rebaseConfig.Fetch, "fetch", false, "Run 'git fetch --all' before rebasing")
+	rootCmd.AddCommand(rebaseCmd)
+}
+
+// rebaseOutcome is the result of rebasing a worktree
+type rebaseOutcome int
+
+const (
+	rebaseRebased rebaseOutcome = iota
+	rebaseUpToDate
+	rebaseConflicted
+	rebaseSkipped
+	rebaseFailed
+)
+
+// rebaseResult is the result of rebasing a worktree with gw rebase
+type rebaseResult struct {
+	outcome rebaseOutcome
+	message string
+}
+
+// rebaseState holds the git operations used by gw rebase
+type rebaseState struct {
+	// base returns the ref a worktree is rebased onto
+	base             func(wt *git.Worktree) (string, error)
+	status           func(path string) (*git.WorktreeStatus, error)
+	rebaseInProgress func(path string) (bool, error)
+	aheadBehind      func(path, base string) (int, int, error)
+	rebase           func(path, onto string) error
+}
+
+func runRebase(cmd *cobra.Command, args []string) error {
+	if rebaseConfig.All && len(args) > 0 {
+		return fmt.Errorf("cannot use --all with worktree names")
+	}
+
+	worktrees, err := selectRebaseTargets(args)
+	if err != nil {
+		return err
+	}
+	if len(worktrees) == 0 {
+		return nil // User cancelled or nothing to rebase
+	}
+
+	if rebaseConfig.Fetch {
+		fmt.Println("Fetching remotes...")
+		if err := git.FetchAll(false); err != nil {
+			return fmt.Errorf("failed to fetch: %w", err)
+		}
+	}
+
+	state := &rebaseState{
+		status:           git.Status,
+		rebaseInProgress: git.RebaseInProgress,
+		aheadBehind:      git.AheadBehind,
+		rebase:           git.Rebase,
+	}
+	if rebaseConfig.Onto != "" {
+		onto := rebaseConfig.Onto
+		state.base = func(wt *git.Worktree) (string, error) { return onto, nil }
+	} else {
+		recorded := loadMetadata()
+		defaultBranch, defaultErr := git.DefaultBranch()
+		state.base = func(wt *git.Worktree) (string, error) {
+			if base := recordedBase(recorded, wt, ""); base != "" {
+				return base, nil
+			}
+			if defaultErr != nil {
+				return "", fmt.Errorf("failed to determine the base branch (use --onto): %w", defaultErr)
+			}
+			return defaultBranch, nil
+		}
+	}
+
+	counts := make(map[rebaseOutcome]int)
+	for _, wt := range worktrees {
+		name := wt.Branch
+		if name == "" {
+			name = wt.Path
+		}
+		result := rebaseWorktree(wt, state)
+		counts[result.outcome]++
+		switch result.outcome {
+		case rebaseRebased:
+			fmt.Printf("✓ %s: %s\n", name, result.message)
+		case rebaseUpToDate:
+			fmt.Printf("ℹ %s: %s\n", name, result.message)
+		case rebaseConflicted:
+			fmt.Printf("⚠ %s: %s\n", name, result.message)
+			fmt.Printf("  Resolve them in %s and run 'git rebase --continue', or 'git rebase --abort'\n", wt.Path)
+		case rebaseSkipped:
+			fmt.Printf("⚠ %s: skipped, %s\n", name, result.message)
+		case rebaseFailed:
+			fmt.Printf("⚠ %s: failed, %s\n", name, result.message)
+		}
+	}
+	fmt.Printf("Rebased %d, up to date %d, conflicted %d, skipped %d, failed %d\n",
+		counts[rebaseRebased], counts[rebaseUpToDate], counts[rebaseConflicted], counts[rebaseSkipped], counts[rebaseFailed])
+
+	if n := counts[rebaseConflicted] + counts[rebaseFailed]; n > 0 {
+		return fmt.Errorf("failed to rebase %d worktree(s)", n)
+	}
+	return nil
+}
+
+// selectRebaseTargets determines the worktrees to rebase from the arguments, --all or fzf
+func selectRebaseTargets(args []string) ([]*git.Worktree, error) {
+	if len(args) == 0 && !rebaseConfig.All {
+		// Interactive selection with fzf (exclude main worktree, multi-select enabled)
+		return selectWorktreesWithFzf(true, true, false)
+	}
+
+	if rebaseConfig.All {
+		worktrees, err := git.List()
+		if err != nil {
+			return nil, fmt.Errorf("failed to list worktrees: %w", err)
+		}
+		var targets []*git.Worktree
+		for i := range worktrees {
+			wt := &worktrees[i]
+			if wt.IsMain || wt.IsBare || wt.Prunable || wt.Branch == "" {
+				continue
+			}
+			targets = append(targets, wt)
+		}
+		return targets, nil
+	}
+
+	var targets []*git.Worktree
+	seen := make(map[string]bool)
+	for _, identifier := range args {
+		wt, err := git.FindWorktree(identifier)
+		if err != nil {
+			return nil, fmt.Errorf("failed to find worktree: %w", err)
+		}
+		if wt == nil {
+			return nil, errors.NewWorktreeNotFoundError(identifier, nil)
+		}
+		if seen[wt.Path] {
+			continue
+		}
+		seen[wt.Path] = true
+		targets = append(targets, wt)
+	}
+	return targets, nil
+}
+
+// rebaseWorktree rebases a single worktree onto its base if it is safe to do so
+func rebaseWorktree(wt *git.Worktree, state *rebaseState) rebaseResult {
+	switch {
+	case wt.IsBare:
+		return rebaseResult{outcome: rebaseSkipped, message: "bare repository"}
+	case wt.Prunable:
+		return rebaseResult{outcome: rebaseSkipped, message: "directory is missing"}
+	case wt.Branch == "":
+		return rebaseResult{outcome: rebaseSkipped, message: "detached HEAD"}
+	}
+
+	inProgress, err := state.rebaseInProgress(wt.Path)
+	if err != nil {
+		return rebaseResult{outcome: rebaseFailed, message: err.Error()}
+	}
+	if inProgress {
+		return rebaseResult{outcome: rebaseSkipped, message: "a rebase is already in progress"}
+	}
+
+	st, err := state.status(wt.Path)
+	if err != nil {
+		return rebaseResult{outcome: rebaseFailed, message: err.Error()}
+	}
+	if st.Staged > 0 || st.Modified > 0 || st.Conflicts > 0 {
+		return rebaseResult{outcome: rebaseSkipped, message: "uncommitted changes"}
+	}
+
+	base, err := state.base(wt)
+	if err != nil {
+		return rebaseResult{outcome: rebaseFailed, message: err.Error()}
+	}
+	if base == wt.Branch {
+		return rebaseResult{outcome: rebaseSkipped, message: "it is the base branch"}
+	}
+
+	ahead, behind, err := state.aheadBehind(wt.Path, base)
+	if err != nil {
+		return rebaseResult{outcome: rebaseFailed, message: err.Error()}
+	}
+	if behind == 0 {
+		return rebaseResult{outcome: rebaseUpToDate, message: "up to date with " + base}
+	}
+
+	if err := state.rebase(wt.Path, base); err != nil {
+		if inProgress, _ := state.rebaseInProgress(wt.Path); inProgress {
+			return rebaseResult{outcome: rebaseConflicted, message: "conflicts while rebasing onto " + base}
+		}
+		return rebaseResult{outcome: rebaseFailed, message: err.Error()}
+	}
+	return rebaseResult{outcome: rebaseRebased, message: fmt.Sprintf("rebased %s onto %s", countCommits(ahead), base)}
+}
@@ -0,0 +1,183 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/t98o84/gw/internal/git"
+)
+
+func TestRebaseCmd_Flags(t *testing.T) {
+	for _, name := range []string{"all", "onto", "fetch"} {
+		if rebaseCmd.Flags().Lookup(name) == nil {
+			t.Errorf("Expected '%s' flag to be defined", name)
+		}
+	}
+}
+
+func TestRebaseWorktree(t *testing.T) {
+	tests := []struct {
+		name        string
+		wt          git.Worktree
+		inProgress  bool
+		status      *git.WorktreeStatus
+		behind      int
+		rebaseErr   error
+		conflicted  bool
+		wantOutcome rebaseOutcome
+		wantRebase  bool
+	}{
+		{
+			name:        "behind base",
+			wt:          git.Worktree{Path: "/path/to/repo-a", Branch: "feature/a"},
+			status:      &git.WorktreeStatus{},
+			behind:      2,
+			wantOutcome: rebaseRebased,
+			wantRebase:  true,
+		},
+		{
+			name:        "up to date",
+			wt:          git.Worktree{Path: "/path/to/repo-a", Branch: "feature/a"},
+			status:      &git.WorktreeStatus{},
+			wantOutcome: rebaseUpToDate,
+		},
+		{
+			name:        "conflicts",
+			wt:          git.Worktree{Path: "/path/to/repo-a", Branch: "feature/a"},
+			status:      &git.WorktreeStatus{},
+			behind:      1,
+			rebaseErr:   fmt.Errorf("could not apply"),
+			conflicted:  true,
+			wantOutcome: rebaseConflicted,
+			wantRebase:  true,
+		},
+		{
+			name:        "rebase fails without conflicts",
+			wt:          git.Worktree{Path: "/path/to/repo-a", Branch: "feature/a"},
+			status:      &git.WorktreeStatus{},
+			behind:      1,
+			rebaseErr:   fmt.Errorf("invalid upstream"),
+			wantOutcome: rebaseFailed,
+			wantRebase:  true,
+		},
+		{
+			name:        "uncommitted changes",
+			wt:          git.Worktree{Path: "/path/to/repo-a", Branch: "feature/a"},
+			status:      &git.WorktreeStatus{Staged: 1},
+			behind:      1,
+			wantOutcome: rebaseSkipped,
+		},
+		{
+			name:        "untracked files don't block the rebase",
+			wt:          git.Worktree{Path: "/path/to/repo-a", Branch: "feature/a"},
+			status:      &git.WorktreeStatus{Untracked: 1},
+			behind:      1,
+			wantOutcome: rebaseRebased,
+			wantRebase:  true,
+		},
+		{
+			name:        "rebase already in progress",
+			wt:          git.Worktree{Path: "/path/to/repo-a", Branch: "feature/a"},
+			inProgress:  true,
+			wantOutcome: rebaseSkipped,
+		},
+		{
+			name:        "base branch itself",
+			wt:          git.Worktree{Path: "/path/to/repo", Branch: "origin/main"},
+			status:      &git.WorktreeStatus{},
+			wantOutcome: rebaseSkipped,
+		},
+		{
+			name:        "detached",
+			wt:          git.Worktree{Path: "/path/to/repo-v1", IsDetached: true},
+			wantOutcome: rebaseSkipped,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rebased := false
+			state := &rebaseState{
+				base: func(wt *git.Worktree) (string, error) { return "origin/main", nil },
+				status: func(path string) (*git.WorktreeStatus, error) {
+					return tt.status, nil
+				},
+				rebaseInProgress: func(path string) (bool, error) {
+					return tt.inProgress || (rebased && tt.conflicted), nil
+				},
+				aheadBehind: func(path, base string) (int, int, error) {
+					return 1, tt.behind, nil
+				},
+				rebase: func(path, onto string) error {
+					rebased = true
+					return tt.rebaseErr
+				},
+			}
+
+			result := rebaseWorktree(&tt.wt, state)
+			if result.outcome != tt.wantOutcome {
+				t.Errorf("rebaseWorktree() outcome = %v (%s), want %v", result.outcome, result.message, tt.wantOutcome)
+			}
+			if rebased != tt.wantRebase {
+				t.Errorf("rebaseWorktree() rebase called = %v, want %v", rebased, tt.wantRebase)
+			}
+		})
+	}
+}
+
+func TestRunRebase(t *testing.T) {
+	requireGit(t)
+
+	base, err := filepath.EvalSymlinks(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	repo := filepath.Join(base, "repo")
+	writeAndCommit := func(dir, file, content string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(dir, file), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		gitOutput(t, dir, "add", file)
+		gitOutput(t, dir, "commit", "-q", "-m", "change "+file)
+	}
+
+	gitOutput(t, base, "init", "-q", "-b", "main", repo)
+	writeAndCommit(repo, "shared.txt", "base\n")
+	for _, branch := range []string{"clean", "conflict", "dirty"} {
+		gitOutput(t, repo, "worktree", "add", "-q", "-b", branch, filepath.Join(base, "repo-"+branch))
+	}
+	writeAndCommit(filepath.Join(base, "repo-clean"), "clean.txt", "clean\n")
+	writeAndCommit(filepath.Join(base, "repo-conflict"), "shared.txt", "conflict\n")
+	writeAndCommit(filepath.Join(base, "repo-dirty"), "dirty.txt", "dirty\n")
+	if err := os.WriteFile(filepath.Join(base, "repo-dirty", "dirty.txt"), []byte("changed\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	writeAndCommit(repo, "shared.txt", "main\n")
+
+	chdirForTest(t, repo)
+	rebaseConfig.All = true
+	rebaseConfig.Onto = "main"
+	rebaseConfig.Fetch = false
+	t.Cleanup(func() {
+		rebaseConfig.All = false
+		rebaseConfig.Onto = ""
+	})
+
+	if err := runRebase(rebaseCmd, nil); err == nil {
+		t.Error("runRebase() expected an error for the conflicted worktree")
+	}
+
+	mainHead := gitOutput(t, repo, "rev-parse", "main")
+	if got := gitOutput(t, filepath.Join(base, "repo-clean"), "rev-parse", "HEAD~1"); got != mainHead {
+		t.Errorf("clean was not rebased onto main: parent = %s, want %s", got, mainHead)
+	}
+	if inProgress, err := git.RebaseInProgress(filepath.Join(base, "repo-conflict")); err != nil || !inProgress {
+		t.Errorf("RebaseInProgress(repo-conflict) = %v, %v, want the worktree left mid-rebase", inProgress, err)
+	}
+	if got := gitOutput(t, filepath.Join(base, "repo-dirty"), "rev-parse", "HEAD~1"); got == mainHead {
+		t.Error("dirty worktree was rebased, want it skipped")
+	}
+}
@@ -0,0 +1,213 @@
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	"github.com/t98o84/gw/internal/git"
+)
+
+var repairConfig = struct {
+	DryRun bool
+}{}
+
+var repairCmd = &cobra.Command{
+	Use:   "repair [flags] [path...]",
+	Short: "Reconnect worktrees after the repository or worktrees were moved",
+	Long: `Reconnect worktrees whose links to the repository broke because the
+repository or worktree directories were moved.
+
+For every worktree whose directory is gone, gw looks for it at the locations
+gw would create it at (the worktree.path template, if set, and the default
+naming convention) and runs 'git worktree repair' with the worktrees it finds.
+Worktrees whose .git file still points to the old repository location are
+repaired as well. Additional locations to check can be given as arguments.
+
+Worktrees that can't be found are reported as missing. Remove them with
+'gw prune', or run 'gw repair <path>' with their new location.
+
+Run this command from the main worktree (or the repository directory in a bare
+layout) at its new location.
+
+Examples:
+  gw repair                     # Find and reconnect moved worktrees
+  gw repair -n                  # Only show what would be reconnected
+  gw repair ~/work/ex-repo-foo  # Also check a custom location`,
+	RunE: runRepair,
+}
+
+func init() {
+	repairCmd.Flags().BoolVarP(&repairConfig.DryRun, "dry-run", "n", false, "Only show the worktrees that would be reconnected")
+	rootCmd.AddCommand(repairCmd)
+}
+
+// repairTarget is a worktree to reconnect and its current location
+type repairTarget struct {
+	worktree *git.Worktree
+	path     string
+}
+
+// repairState holds the lookups used to find moved worktrees
+type repairState struct {
+	repoName string
+	// extraPaths are additional locations given on the command line
+	extraPaths []string
+	// expectedPaths returns the locations where a worktree for branch is expected
+	expectedPaths func(repoName, branch string) ([]string, error)
+	// adminDir returns the administrative directory of the worktree registered at path
+	adminDir func(path string) (string, error)
+	// linkedGitDir returns the git directory that a worktree's .git file points to
+	linkedGitDir func(path string) (string, error)
+}
+
+func runRepair(cmd *cobra.Command, args []string) error {
+	repoName, err := git.GetRepoName()
+	if err != nil {
+		return fmt.Errorf("failed to get repository name: %w", err)
+	}
+
+	state := &repairState{
+		repoName:      repoName,
+		expectedPaths: git.ExpectedWorktreePaths,
+		adminDir:      git.AdminDir,
+		linkedGitDir:  git.LinkedGitDir,
+	}
+	for _, arg := range args {
+		path, err := filepath.Abs(arg)
+		if err != nil {
+			return fmt.Errorf("failed to resolve path %s: %w", arg, err)
+		}
+		state.extraPaths = append(state.extraPaths, path)
+	}
+
+	worktrees, err := git.List()
+	if err != nil {
+		return fmt.Errorf("failed to list worktrees: %w", err)
+	}
+
+	targets, missing := collectRepairTargets(worktrees, state)
+	if len(targets) == 0 && len(missing) == 0 {
+		fmt.Println("All worktrees are connected")
+		return nil
+	}
+
+	if len(targets) > 0 {
+		fmt.Println("Worktrees to reconnect:")
+		for _, t := range targets {
+			if t.path == t.worktree.Path {
+				fmt.Printf("  %s\t%s\n", t.path, t.worktree.Branch)
+			} else {
+				fmt.Printf("  %s -> %s\t%s\n", t.worktree.Path, t.path, t.worktree.Branch)
+			}
+		}
+	}
+
+	if !repairConfig.DryRun && len(targets) > 0 {
+		paths := make([]string, 0, len(targets))
+		for _, t := range targets {
+			paths = append(paths, t.path)
+		}
+		if err := git.Repair(paths...); err != nil {
+			return fmt.Errorf("failed to repair worktrees: %w", err)
+		}
+
+		repaired, err := git.List()
+		if err != nil {
+			return fmt.Errorf("failed to list worktrees: %w", err)
+		}
+		for _, t := range targets {
+			if isConnectedWorktree(t.path, repaired) {
+				fmt.Printf("✓ Reconnected: %s\n", t.path)
+			} else {
+				fmt.Printf("⚠ Failed to reconnect: %s\n", t.path)
+			}
+		}
+	}
+
+	if len(missing) > 0 {
+		fmt.Println("Missing worktrees:")
+		for _, wt := range missing {
+			fmt.Printf("  %s\t%s\n", wt.Path, wt.Branch)
+		}
+		fmt.Println("ℹ Remove them with 'gw prune', or run 'gw repair <path>' with their new location")
+	}
+
+	return nil
+}
+
+// collectRepairTargets finds the worktrees that gw repair should reconnect
+// and the worktrees that could not be found
+func collectRepairTargets(worktrees []git.Worktree, state *repairState) ([]repairTarget, []*git.Worktree) {
+	var targets []repairTarget
+	var missing []*git.Worktree
+	for i := range worktrees {
+		wt := &worktrees[i]
+		// The first entry is the main worktree or the bare repository itself
+		if i == 0 || wt.IsBare {
+			continue
+		}
+
+		adminDir, err := state.adminDir(wt.Path)
+		if err != nil {
+			fmt.Printf("⚠ Skipping %s: %v\n", wt.Path, err)
+			continue
+		}
+
+		if wt.Prunable {
+			if path := findMovedWorktree(wt, adminDir, state); path != "" {
+				targets = append(targets, repairTarget{worktree: wt, path: path})
+			} else {
+				missing = append(missing, wt)
+			}
+			continue
+		}
+
+		// The worktree is in place, but its .git file may still point to the
+		// repository's old location
+		gitDir, err := state.linkedGitDir(wt.Path)
+		if err != nil || filepath.Clean(gitDir) != filepath.Clean(adminDir) {
+			targets = append(targets, repairTarget{worktree: wt, path: wt.Path})
+		}
+	}
+	return targets, missing
+}
+
+// findMovedWorktree looks for the new location of a worktree whose directory is gone.
+// A location matches when its .git file points to an administrative directory with
+// the same name as the worktree's. Returns "" if the worktree was not found.
+func findMovedWorktree(wt *git.Worktree, adminDir string, state *repairState) string {
+	var candidates []string
+	if wt.Branch != "" {
+		expected, err := state.expectedPaths(state.repoName, wt.Branch)
+		if err != nil {
+			fmt.Printf("⚠ Failed to determine the location of %s: %v\n", wt.Branch, err)
+		}
+		candidates = append(candidates, expected...)
+	}
+	candidates = append(candidates, state.extraPaths...)
+
+	for _, path := range candidates {
+		if filepath.Clean(path) == filepath.Clean(wt.Path) {
+			continue
+		}
+		gitDir, err := state.linkedGitDir(path)
+		if err != nil {
+			continue
+		}
+		if filepath.Base(gitDir) == filepath.Base(adminDir) {
+			return path
+		}
+	}
+	return ""
+}
+
+// isConnectedWorktree reports whether a usable worktree is registered at path
+func isConnectedWorktree(path string, worktrees []git.Worktree) bool {
+	for _, wt := range worktrees {
+		if filepath.Clean(wt.Path) == filepath.Clean(path) {
+			return !wt.Prunable
+		}
+	}
+	return false
+}
@@ -0,0 +1,126 @@
+package cmd
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/t98o84/gw/internal/git"
+)
+
+func TestRepairCmd(t *testing.T) {
+	if repairCmd.Use != "repair [flags] [path...]" {
+		t.Errorf("repairCmd.Use = %q, want %q", repairCmd.Use, "repair [flags] [path...]")
+	}
+
+	flag := repairCmd.Flags().Lookup("dry-run")
+	if flag == nil {
+		t.Fatal("Expected 'dry-run' flag to be defined")
+	}
+	if flag.Shorthand != "n" {
+		t.Errorf("dry-run flag shorthand = %q, want %q", flag.Shorthand, "n")
+	}
+}
+
+func TestCollectRepairTargets(t *testing.T) {
+	worktrees := []git.Worktree{
+		{Path: "/new/repo", Branch: "main", IsMain: true},
+		{Path: "/old/repo-moved", Branch: "moved", Prunable: true},
+		{Path: "/old/repo-custom", Branch: "custom", Prunable: true},
+		{Path: "/old/repo-lost", Branch: "lost", Prunable: true},
+		{Path: "/old/repo-detached", IsDetached: true, Prunable: true},
+		{Path: "/stay/repo-stale-link", Branch: "stale-link"},
+		{Path: "/stay/repo-ok", Branch: "ok"},
+		{Path: "/stay/repo-unknown", Branch: "unknown"},
+	}
+
+	adminDirs := map[string]string{
+		"/old/repo-moved":       "/new/repo/.git/worktrees/repo-moved",
+		"/old/repo-custom":      "/new/repo/.git/worktrees/repo-custom",
+		"/old/repo-lost":        "/new/repo/.git/worktrees/repo-lost",
+		"/old/repo-detached":    "/new/repo/.git/worktrees/repo-detached",
+		"/stay/repo-stale-link": "/new/repo/.git/worktrees/repo-stale-link",
+		"/stay/repo-ok":         "/new/repo/.git/worktrees/repo-ok",
+	}
+	gitDirs := map[string]string{
+		"/new/repo-moved":       "/old/repo/.git/worktrees/repo-moved",
+		"/elsewhere/custom":     "/old/repo/.git/worktrees/repo-custom",
+		"/new/repo-lost":        "/old/repo/.git/worktrees/another",
+		"/stay/repo-stale-link": "/old/repo/.git/worktrees/repo-stale-link",
+		"/stay/repo-ok":         "/new/repo/.git/worktrees/repo-ok",
+	}
+	state := &repairState{
+		repoName:   "repo",
+		extraPaths: []string{"/elsewhere/custom"},
+		expectedPaths: func(repoName, branch string) ([]string, error) {
+			return []string{"/new/" + git.WorktreeDirName(repoName, branch)}, nil
+		},
+		adminDir: func(path string) (string, error) {
+			if dir, ok := adminDirs[path]; ok {
+				return dir, nil
+			}
+			return "", fmt.Errorf("not registered")
+		},
+		linkedGitDir: func(path string) (string, error) {
+			if dir, ok := gitDirs[path]; ok {
+				return dir, nil
+			}
+			return "", fmt.Errorf("no .git file")
+		},
+	}
+
+	targets, missing := collectRepairTargets(worktrees, state)
+
+	wantTargets := map[string]string{
+		"/old/repo-moved":       "/new/repo-moved",
+		"/old/repo-custom":      "/elsewhere/custom",
+		"/stay/repo-stale-link": "/stay/repo-stale-link",
+	}
+	if len(targets) != len(wantTargets) {
+		t.Fatalf("collectRepairTargets() returned %d targets, want %d: %+v", len(targets), len(wantTargets), targets)
+	}
+	for _, target := range targets {
+		want, ok := wantTargets[target.worktree.Path]
+		if !ok {
+			t.Errorf("unexpected target %s", target.worktree.Path)
+			continue
+		}
+		if target.path != want {
+			t.Errorf("target %s path = %q, want %q", target.worktree.Path, target.path, want)
+		}
+	}
+
+	wantMissing := []string{"/old/repo-lost", "/old/repo-detached"}
+	if len(missing) != len(wantMissing) {
+		t.Fatalf("collectRepairTargets() returned %d missing worktrees, want %d", len(missing), len(wantMissing))
+	}
+	for i, wt := range missing {
+		if wt.Path != wantMissing[i] {
+			t.Errorf("missing[%d] = %s, want %s", i, wt.Path, wantMissing[i])
+		}
+	}
+}
+
+func TestIsConnectedWorktree(t *testing.T) {
+	worktrees := []git.Worktree{
+		{Path: "/repo"},
+		{Path: "/repo-ok"},
+		{Path: "/repo-stale", Prunable: true},
+	}
+
+	tests := []struct {
+		path string
+		want bool
+	}{
+		{path: "/repo-ok", want: true},
+		{path: "/repo-ok/", want: true},
+		{path: "/repo-stale", want: false},
+		{path: "/repo-unknown", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			if got := isConnectedWorktree(tt.path, worktrees); got != tt.want {
+				t.Errorf("isConnectedWorktree(%q) = %v, want %v", tt.path, got, tt.want)
+			}
+		})
+	}
+}
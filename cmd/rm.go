@@ -51,19 +51,23 @@ Hooks:
         - name: "Clean up artifacts"
           command: echo "Cleaned up worktree for $GW_BRANCH"
 
+Locked worktrees (see 'gw lock') are refused and hidden from the interactive
+selector unless --force is given.
+
 Examples:
   gw rm feature/hoge
   gw rm feature/hoge feature/fuga   # Remove multiple worktrees
   gw rm feature-hoge
   gw rm ex-repo-feature-hoge
   gw rm -b feature/hoge             # Also delete the branch
+  gw rm -f feature/hoge             # Remove even if dirty or locked
   gw rm
     Interactive worktree selection with fzf (Tab to multi-select)`,
 	RunE: runRm,
 }
 
 func init() {
-	rmCmd.Flags().BoolVarP(&rmConfig.Force, "force", "f", false, "Force removal even if worktree is dirty")
+	rmCmd.Flags().BoolVarP(&rmConfig.Force, "force", "f", false, "Force removal even if worktree is dirty or locked")
 	rmCmd.Flags().BoolVarP(&rmConfig.Yes, "yes", "y", false, "Skip confirmation prompt (alias for --force)")
 	rmCmd.Flags().BoolVarP(&rmConfig.Branch, "branch", "b", false, "Also delete the associated git branch")
 	// Negation flags
@@ -105,19 +109,22 @@ func runRm(cmd *cobra.Command, args []string) error {
 		nil,
 		nil,
 		nil,
+		nil,
 		false,
 		false,
 		false,
 		false,
 		noYesValue,
 		rmConfig.NoBranch,
+		false,
 	)
 
 	var worktrees []*git.Worktree
 
 	if len(args) == 0 {
-		// Interactive selection with fzf (exclude main worktree, multi-select enabled)
-		selected, err := selectWorktreesWithFzf(true, true)
+		// Interactive selection with fzf (exclude main worktree, multi-select enabled,
+		// locked worktrees are only offered with --force)
+		selected, err := selectWorktreesWithFzf(true, true, !mergedConfig.Rm.Force)
 		if err != nil {
 			return err
 		}
@@ -138,88 +145,182 @@ func runRm(cmd *cobra.Command, args []string) error {
 			if wt.IsMain {
 				return errors.NewInvalidInputError(identifier, "cannot remove the main worktree", nil)
 			}
+			if wt.IsBare {
+				return errors.NewInvalidInputError(identifier, "cannot remove the bare repository", nil)
+			}
 			worktrees = append(worktrees, wt)
 		}
 	}
 
+	// Refuse to touch locked worktrees unless --force is given
+	for _, wt := range worktrees {
+		if wt.Locked && !mergedConfig.Rm.Force {
+			return errors.NewWorktreeLockedError(wt.Path, wt.LockReason, nil)
+		}
+	}
+
+	opts, err := newRemoveOptions(mergedConfig.Rm.Force, mergedConfig.Rm.Branch)
+	if err != nil {
+		return err
+	}
+
+	// Remove all selected worktrees
+	for _, wt := range worktrees {
+		if err := removeWorktree(wt, opts); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// removeOptions holds the state shared by every removal in a single run
+type removeOptions struct {
+	// force removes locked and dirty worktrees
+	force bool
+	// forceBranch deletes branches even if they aren't merged
+	forceBranch      bool
+	deleteBranch     bool
+	currentBranch    string
+	mainWorktreePath string
+	repoRoot         string
+	projectConfig    *config.ProjectConfig
+}
+
+// newRemoveOptions collects the repository state needed to remove worktrees
+func newRemoveOptions(force, deleteBranch bool) (*removeOptions, error) {
+	opts := &removeOptions{force: force, forceBranch: force, deleteBranch: deleteBranch}
+
 	// Get current branch and main worktree path if we need to delete branches
-	var currentBranch string
-	var mainWorktreePath string
-	if mergedConfig.Rm.Branch {
+	if deleteBranch {
 		var err error
-		currentBranch, err = git.GetCurrentBranch()
+		opts.currentBranch, err = git.GetCurrentBranch()
 		if err != nil {
-			return fmt.Errorf("failed to get current branch: %w", err)
+			return nil, fmt.Errorf("failed to get current branch: %w", err)
 		}
 
 		// Get main worktree path to avoid cwd issues
 		allWorktrees, err := git.List()
 		if err != nil {
-			return fmt.Errorf("failed to list worktrees: %w", err)
+			return nil, fmt.Errorf("failed to list worktrees: %w", err)
 		}
 		for _, wt := range allWorktrees {
 			if wt.IsMain {
-				mainWorktreePath = wt.Path
+				opts.mainWorktreePath = wt.Path
 				break
 			}
 		}
+		// A bare repository without a primary worktree can delete branches itself
+		if opts.mainWorktreePath == "" {
+			for _, wt := range allWorktrees {
+				if wt.IsBare {
+					opts.mainWorktreePath = wt.Path
+					break
+				}
+			}
+		}
 		// Ensure we found the main worktree path when branch deletion is enabled
-		if mainWorktreePath == "" {
-			return fmt.Errorf("failed to determine main worktree path: aborting branch deletion")
+		if opts.mainWorktreePath == "" {
+			return nil, fmt.Errorf("failed to determine main worktree path: aborting branch deletion")
 		}
 	}
 
 	// Load project config for hooks
-	repoRoot, err := git.GetRepoRoot()
+	repoRoot, err := getProjectRoot()
 	if err != nil {
-		return fmt.Errorf("failed to get repository root: %w", err)
+		return nil, fmt.Errorf("failed to get repository root: %w", err)
 	}
 	projectConfig, err := config.FindProjectConfig(repoRoot)
 	if err != nil {
-		return fmt.Errorf("failed to load project config: %w", err)
+		return nil, fmt.Errorf("failed to load project config: %w", err)
 	}
+	opts.repoRoot = repoRoot
+	opts.projectConfig = projectConfig
 
-	// Remove all selected worktrees
-	for _, wt := range worktrees {
-		if wt.IsMain {
-			fmt.Printf("⚠ Skipping main worktree: %s\n", wt.Path)
-			continue
-		}
+	return opts, nil
+}
 
-		// Execute pre-remove hooks
-		if projectConfig != nil && len(projectConfig.Hooks.PreRemove) > 0 {
-			if err := config.ExecuteHooks(projectConfig, config.HookPreRemove, wt.Path, wt.Branch, repoRoot); err != nil {
-				return fmt.Errorf("pre-remove hook failed: %w", err)
-			}
+// removeWorktree removes a single worktree, running the remove hooks and
+// deleting its branch when requested
+func removeWorktree(wt *git.Worktree, opts *removeOptions) error {
+	if wt.IsMain || wt.IsBare {
+		fmt.Printf("⚠ Skipping main worktree: %s\n", wt.Path)
+		return nil
+	}
+
+	// Execute pre-remove hooks
+	if opts.projectConfig != nil && len(opts.projectConfig.Hooks.PreRemove) > 0 {
+		if err := config.ExecuteHooks(opts.projectConfig, config.HookPreRemove, wt.Path, wt.Branch, opts.repoRoot); err != nil {
+			return fmt.Errorf("pre-remove hook failed: %w", err)
 		}
+	}
 
-		fmt.Printf("Removing worktree: %s\n", wt.Path)
-		if err := git.Remove(wt.Path, mergedConfig.Rm.Force); err != nil {
-			return fmt.Errorf("failed to remove %s: %w", wt.Path, err)
+	if wt.Locked {
+		if !opts.force {
+			return errors.NewWorktreeLockedError(wt.Path, wt.LockReason, nil)
 		}
-		fmt.Printf("✓ Worktree removed: %s\n", wt.Path)
+		// git refuses to remove locked worktrees, so unlock first
+		if err := git.Unlock(wt.Path); err != nil {
+			return fmt.Errorf("failed to unlock %s: %w", wt.Path, err)
+		}
+	}
 
-		// Execute post-remove hooks
-		if projectConfig != nil && len(projectConfig.Hooks.PostRemove) > 0 {
-			if err := config.ExecuteHooks(projectConfig, config.HookPostRemove, wt.Path, wt.Branch, repoRoot); err != nil {
-				// Don't fail if post-remove hooks fail, just warn
-				fmt.Printf("⚠ Post-remove hook failed: %v\n", err)
+	if err := removeWorktreeEntry(wt, opts.force); err != nil {
+		if wt.Locked {
+			// Restore the lock so a failed removal doesn't leave the worktree unprotected
+			if lockErr := git.Lock(wt.Path, wt.LockReason); lockErr != nil {
+				fmt.Printf("⚠ Failed to lock %s again: %v\n", wt.Path, lockErr)
 			}
 		}
+		return err
+	}
+	forgetMetadata(wt.Path)
 
-		// Delete branch if requested
-		if mergedConfig.Rm.Branch && wt.Branch != "" {
-			deleted, err := deleteBranchSafely(wt.Branch, currentBranch, mainWorktreePath, mergedConfig.Rm.Force)
-			if err != nil {
-				fmt.Printf("⚠ Failed to delete branch %s: %v\n", wt.Branch, err)
-			} else if !deleted {
-				fmt.Printf("ℹ Branch %s not found, skipping\n", wt.Branch)
-			} else {
-				fmt.Printf("✓ Branch deleted: %s\n", wt.Branch)
-			}
+	// Execute post-remove hooks
+	if opts.projectConfig != nil && len(opts.projectConfig.Hooks.PostRemove) > 0 {
+		if err := config.ExecuteHooks(opts.projectConfig, config.HookPostRemove, wt.Path, wt.Branch, opts.repoRoot); err != nil {
+			// Don't fail if post-remove hooks fail, just warn
+			fmt.Printf("⚠ Post-remove hook failed: %v\n", err)
 		}
 	}
 
+	// Delete branch if requested
+	if opts.deleteBranch && wt.Branch != "" {
+		deleted, err := deleteBranchSafely(wt.Branch, opts.currentBranch, opts.mainWorktreePath, opts.forceBranch)
+		if err != nil {
+			fmt.Printf("⚠ Failed to delete branch %s: %v\n", wt.Branch, err)
+		} else if !deleted {
+			fmt.Printf("ℹ Branch %s not found, skipping\n", wt.Branch)
+		} else {
+			fmt.Printf("✓ Branch deleted: %s\n", wt.Branch)
+		}
+	}
+
+	return nil
+}
+
+// removeWorktreeEntry removes the worktree's directory and administrative data
+func removeWorktreeEntry(wt *git.Worktree, force bool) error {
+	if wt.Prunable {
+		// The working tree is gone, so only the administrative data is left to clean up.
+		// Unlike 'git worktree prune', this leaves the other stale entries alone, e.g.
+		// worktrees on removable media that is only unmounted for now
+		fmt.Printf("Pruning stale worktree: %s\n", wt.Path)
+		adminDir, err := git.AdminDir(wt.Path)
+		if err != nil {
+			return fmt.Errorf("failed to prune %s: %w", wt.Path, err)
+		}
+		if err := os.RemoveAll(adminDir); err != nil {
+			return fmt.Errorf("failed to prune %s: %w", wt.Path, err)
+		}
+		fmt.Printf("✓ Worktree pruned: %s\n", wt.Path)
+	} else {
+		fmt.Printf("Removing worktree: %s\n", wt.Path)
+		if err := git.Remove(wt.Path, force); err != nil {
+			return fmt.Errorf("failed to remove %s: %w", wt.Path, err)
+		}
+		fmt.Printf("✓ Worktree removed: %s\n", wt.Path)
+	}
 	return nil
 }
 
@@ -1,8 +1,12 @@
 package cmd
 
 import (
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
+
+	"github.com/t98o84/gw/internal/git"
 )
 
 func TestRmCmd(t *testing.T) {
@@ -149,3 +153,83 @@ func TestDeleteBranchSafely(t *testing.T) {
 		})
 	}
 }
+
+func TestRemoveWorktree_Prunable(t *testing.T) {
+	requireGit(t)
+	dir := t.TempDir()
+	mainPath := filepath.Join(dir, "repo")
+	if err := os.Mkdir(mainPath, 0755); err != nil {
+		t.Fatal(err)
+	}
+	gitOutput(t, mainPath, "init", "-q", "-b", "main")
+	gitOutput(t, mainPath, "commit", "-q", "--allow-empty", "-m", "init")
+	for _, name := range []string{"stale", "unmounted", "no-git-file"} {
+		gitOutput(t, mainPath, "worktree", "add", "-q", "-b", name, filepath.Join(dir, name))
+	}
+	for _, name := range []string{"stale", "unmounted"} {
+		if err := os.RemoveAll(filepath.Join(dir, name)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// A directory without its .git file is prunable too, but git worktree remove refuses it
+	if err := os.Remove(filepath.Join(dir, "no-git-file", ".git")); err != nil {
+		t.Fatal(err)
+	}
+	chdirForTest(t, mainPath)
+
+	for _, name := range []string{"stale", "no-git-file"} {
+		wt, err := git.FindWorktree(name)
+		if err != nil || wt == nil || !wt.Prunable {
+			t.Fatalf("FindWorktree(%s) = %+v, %v, want a prunable worktree", name, wt, err)
+		}
+		if err := removeWorktree(wt, &removeOptions{}); err != nil {
+			t.Fatalf("removeWorktree(%s) error = %v", name, err)
+		}
+	}
+
+	// Only the selected entries are removed; the other stale worktree may just be unmounted
+	list := gitOutput(t, mainPath, "worktree", "list", "--porcelain")
+	branches := make(map[string]bool)
+	for _, line := range strings.Split(list, "\n") {
+		if branch, ok := strings.CutPrefix(line, "branch refs/heads/"); ok {
+			branches[branch] = true
+		}
+	}
+	if branches["stale"] || branches["no-git-file"] {
+		t.Errorf("worktree list still contains a removed worktree:\n%s", list)
+	}
+	if !branches["unmounted"] {
+		t.Errorf("worktree list lost the other stale worktree:\n%s", list)
+	}
+}
+
+func TestRemoveWorktree_RelocksOnFailure(t *testing.T) {
+	requireGit(t)
+	dir := t.TempDir()
+	mainPath := filepath.Join(dir, "repo")
+	wtPath := filepath.Join(dir, "locked")
+	if err := os.Mkdir(mainPath, 0755); err != nil {
+		t.Fatal(err)
+	}
+	gitOutput(t, mainPath, "init", "-q", "-b", "main")
+	gitOutput(t, mainPath, "commit", "-q", "--allow-empty", "-m", "init")
+	gitOutput(t, mainPath, "worktree", "add", "-q", "-b", "locked", wtPath)
+	gitOutput(t, mainPath, "worktree", "lock", "--reason", "on a USB drive", wtPath)
+	// git worktree remove fails, even with --force, without the .git file
+	if err := os.Remove(filepath.Join(wtPath, ".git")); err != nil {
+		t.Fatal(err)
+	}
+	chdirForTest(t, mainPath)
+
+	wt, err := git.FindWorktree("locked")
+	if err != nil || wt == nil || !wt.Locked {
+		t.Fatalf("FindWorktree(locked) = %+v, %v, want a locked worktree", wt, err)
+	}
+	if err := removeWorktree(wt, &removeOptions{force: true}); err == nil {
+		t.Fatal("removeWorktree() error = nil, want error")
+	}
+
+	if list := gitOutput(t, mainPath, "worktree", "list", "--porcelain"); !strings.Contains(list, "locked on a USB drive") {
+		t.Errorf("worktree list = \n%s\nwant the worktree locked again with its reason", list)
+	}
+}
@@ -5,7 +5,9 @@ import (
 	"os"
 
 	"github.com/spf13/cobra"
+	"github.com/t98o84/gw/internal/config"
 	"github.com/t98o84/gw/internal/errors"
+	"github.com/t98o84/gw/internal/git"
 )
 
 var version = "dev"
@@ -17,9 +19,10 @@ var rootCmd = &cobra.Command{
 
 It provides easy commands to create, list, remove, and switch between worktrees
 with intuitive naming conventions and fzf integration.`,
-	Version:       version,
-	SilenceErrors: true,
-	SilenceUsage:  true,
+	Version:           version,
+	SilenceErrors:     true,
+	SilenceUsage:      true,
+	PersistentPreRunE: applyRepositoryConfig,
 }
 
 // Execute runs the root command and handles any errors.
@@ -31,6 +34,52 @@ func Execute() {
 	}
 }
 
+// applyRepositoryConfig applies the settings that depend on the current repository
+func applyRepositoryConfig(cmd *cobra.Command, args []string) error {
+	if err := applyWorktreeLayout(); err != nil {
+		return err
+	}
+	applyDefaultRemote()
+	return nil
+}
+
+// applyWorktreeLayout configures where worktrees are placed.
+// worktree.path in the project's gw.yaml takes precedence over the user config.
+func applyWorktreeLayout() error {
+	tmpl := ""
+	if globalConfig != nil {
+		tmpl = globalConfig.Worktree.Path
+	}
+
+	// Outside a repository there is no project config; commands report that themselves
+	if repoRoot, err := getProjectRoot(); err == nil {
+		projectConfig, err := config.FindProjectConfig(repoRoot)
+		if err != nil {
+			return fmt.Errorf("failed to load project config: %w", err)
+		}
+		if projectConfig != nil && projectConfig.Worktree.Path != "" {
+			tmpl = projectConfig.Worktree.Path
+		}
+	}
+
+	git.SetPathTemplate(tmpl)
+	return nil
+}
+
+// applyDefaultRemote configures the remote used for remote branches.
+// `git config gw.remote` takes precedence over remote in the user config,
+// so fork-based repositories can use "upstream" without affecting others.
+func applyDefaultRemote() {
+	remote := ""
+	if globalConfig != nil {
+		remote = globalConfig.Remote
+	}
+	if value, err := git.GetConfig("gw.remote"); err == nil && value != "" {
+		remote = value
+	}
+	git.SetDefaultRemote(remote)
+}
+
 // handleError provides user-friendly error messages based on the error type.
 // It prints the error and helpful hints to stderr.
 func handleError(err error) {
@@ -46,6 +95,10 @@ func handleError(err error) {
 		return
 	case errors.IsWorktreeExistsError(err):
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		if suggestion := errors.WorktreeExistsSuggestion(err); suggestion != "" {
+			fmt.Fprintf(os.Stderr, "Hint: Use --path %s to create the worktree in a different directory\n", suggestion)
+			return
+		}
 		fmt.Fprintf(os.Stderr, "Hint: Use 'gw ls' to list existing worktrees\n")
 		return
 	case errors.IsNotAGitRepoError(err):
@@ -64,6 +117,10 @@ func handleError(err error) {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		fmt.Fprintf(os.Stderr, "Hint: The current directory is not within a git worktree\n")
 		return
+	case errors.IsWorktreeLockedError(err):
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		fmt.Fprintf(os.Stderr, "Hint: Unlock it with 'gw unlock <name>' or use --force\n")
+		return
 	case errors.IsGitHubAPIError(err):
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		fmt.Fprintf(os.Stderr, "Hint: Check your GitHub token or PR identifier\n")
@@ -0,0 +1,343 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"runtime"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/t98o84/gw/internal/git"
+	"github.com/t98o84/gw/internal/metadata"
+)
+
+// statusJSONVersion is the version of the gw status --json output format
+const statusJSONVersion = 1
+
+var statusConfig = struct {
+	JSON bool
+	Jobs int
+}{}
+
+var statusCmd = &cobra.Command{
+	Use:     "status [flags]",
+	Aliases: []string{"st"},
+	Short:   "Show the status of all worktrees",
+	Long: `Show a dashboard of all worktrees of the current repository.
+
+For each worktree, gw status shows:
+  - The number of staged, modified, untracked and conflicted files
+  - Commits ahead (↑) and behind (↓) its upstream branch
+  - Commits ahead and behind its base branch: the branch it was created from
+    when gw recorded one (see 'gw info'), otherwise the default branch
+    (e.g. origin/main)
+  - The age and subject of the last commit
+  - State markers: (main), (bare), (locked) and (prunable)
+
+The worktrees are inspected concurrently; use --jobs to limit the number of
+git processes run at a time. With --json, the status is printed as JSON for
+scripts. The output contains a "version" field that is incremented when the
+format changes incompatibly.
+
+Examples:
+  gw status             # Show the status table
+  gw status --json      # Print the status as JSON
+  gw status -j 2        # Inspect at most 2 worktrees at a time`,
+	Args: cobra.NoArgs,
+	RunE: runStatus,
+}
+
+func init() {
+	statusCmd.Flags().BoolVar(&statusConfig.JSON, "json", false, "Print the status as JSON")
+	statusCmd.Flags().IntVarP(&statusConfig.Jobs, "jobs", "j", defaultJobs(), "Number of worktrees to inspect concurrently")
+	rootCmd.AddCommand(statusCmd)
+}
+
+// defaultJobs returns the default number of worktrees processed concurrently
+func defaultJobs() int {
+	return min(runtime.NumCPU(), 8)
+}
+
+// worktreeStatus is the status of a worktree shown by gw status
+type worktreeStatus struct {
+	worktreeJSON
+	Staged     int               `json:"staged"`
+	Modified   int               `json:"modified"`
+	Untracked  int               `json:"untracked"`
+	Conflicts  int               `json:"conflicts"`
+	Upstream   *statusComparison `json:"upstream,omitempty"`
+	Base       *statusComparison `json:"base,omitempty"`
+	LastCommit *statusCommit     `json:"last_commit,omitempty"`
+	Error      string            `json:"error,omitempty"`
+
+	// label is the branch shown in the table
+	label   string
+	markers []string
+}
+
+// statusComparison counts the commits a worktree is ahead of and behind another branch
+type statusComparison struct {
+	Name string `json:"name"`
+	// Gone is true when the upstream branch no longer exists
+	Gone   bool `json:"gone,omitempty"`
+	Ahead  int  `json:"ahead"`
+	Behind int  `json:"behind"`
+}
+
+// statusCommit is the last commit of a worktree
+type statusCommit struct {
+	Time    time.Time `json:"time"`
+	Subject string    `json:"subject"`
+}
+
+// statusOutput is the document printed by gw status --json
+type statusOutput struct {
+	Version   int              `json:"version"`
+	Base      string           `json:"base,omitempty"`
+	Worktrees []worktreeStatus `json:"worktrees"`
+}
+
+// statusState holds the lookups used to collect the status of worktrees
+type statusState struct {
+	// base is the branch worktrees are compared with unless they have a recorded
+	// base (empty to skip the comparison)
+	base string
+	// recorded is the worktree metadata keyed by metadata.Key
+	recorded    map[string]metadata.Worktree
+	status      func(path string) (*git.WorktreeStatus, error)
+	aheadBehind func(path, base string) (int, int, error)
+	lastCommit  func(path string) (time.Time, string, error)
+	tagsAt      func(commit string) ([]string, error)
+}
+
+func runStatus(cmd *cobra.Command, args []string) error {
+	if statusConfig.Jobs < 1 {
+		return fmt.Errorf("--jobs must be at least 1")
+	}
+
+	worktrees, err := git.List()
+	if err != nil {
+		return fmt.Errorf("failed to list worktrees: %w", err)
+	}
+
+	state := &statusState{
+		status:      git.Status,
+		aheadBehind: git.AheadBehind,
+		lastCommit:  git.LastCommit,
+		tagsAt:      git.TagsAt,
+		recorded:    loadMetadata(),
+	}
+	// Without a default branch, only the upstream comparison is shown
+	if base, err := git.DefaultBranch(); err == nil {
+		state.base = base
+	}
+
+	statuses := collectConcurrently(worktrees, statusConfig.Jobs, func(wt *git.Worktree) worktreeStatus {
+		return collectStatus(wt, state)
+	})
+
+	if statusConfig.JSON {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(statusOutput{Version: statusJSONVersion, Base: state.base, Worktrees: statuses})
+	}
+
+	if len(statuses) == 0 {
+		fmt.Println("No worktrees found")
+		return nil
+	}
+	printStatusTable(statuses, state.base, time.Now())
+	return nil
+}
+
+// collectConcurrently runs collect for every worktree with at most jobs running at a time.
+// The results are returned in the order of worktrees.
+func collectConcurrently[T any](worktrees []git.Worktree, jobs int, collect func(wt *git.Worktree) T) []T {
+	results := make([]T, len(worktrees))
+	indexes := make(chan int)
+
+	var wg sync.WaitGroup
+	for range min(jobs, len(worktrees)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				results[i] = collect(&worktrees[i])
+			}
+		}()
+	}
+	for i := range worktrees {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+
+	return results
+}
+
+// collectStatus collects the status of a single worktree.
+// Failures are recorded in the Error field so the other worktrees are still shown.
+func collectStatus(wt *git.Worktree, state *statusState) worktreeStatus {
+	s := worktreeStatus{
+		worktreeJSON: newWorktreeJSON(wt, lookupMetadata(state.recorded, wt), detachedTags(wt, state.tagsAt)),
+		label:        branchLabel(wt, state.tagsAt),
+		markers:      wt.Markers(),
+	}
+	// There is no working tree to inspect
+	if wt.IsBare || wt.Prunable {
+		return s
+	}
+
+	st, err := state.status(wt.Path)
+	if err != nil {
+		s.Error = err.Error()
+		return s
+	}
+	s.Staged = st.Staged
+	s.Modified = st.Modified
+	s.Untracked = st.Untracked
+	s.Conflicts = st.Conflicts
+	if st.Upstream != "" {
+		s.Upstream = &statusComparison{Name: st.Upstream, Gone: st.UpstreamGone, Ahead: st.Ahead, Behind: st.Behind}
+	}
+
+	if base := recordedBase(state.recorded, wt, state.base); base != "" {
+		// The comparison fails for unborn branches and unrelated histories; leave it out
+		if ahead, behind, err := state.aheadBehind(wt.Path, base); err == nil {
+			s.Base = &statusComparison{Name: base, Ahead: ahead, Behind: behind}
+		}
+	}
+
+	if when, subject, err := state.lastCommit(wt.Path); err == nil {
+		s.LastCommit = &statusCommit{Time: when, Subject: subject}
+	}
+
+	return s
+}
+
+// printStatusTable prints the statuses as a table
+func printStatusTable(statuses []worktreeStatus, base string, now time.Time) {
+	baseHeader := "BASE"
+	if base != "" {
+		baseHeader = fmt.Sprintf("BASE (%s)", base)
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(w, "NAME\tBRANCH\tCHANGES\tUPSTREAM\t%s\tLAST COMMIT\n", baseHeader)
+	for _, s := range statuses {
+		name := s.Name
+		if len(s.markers) > 0 {
+			name += " " + strings.Join(s.markers, " ")
+		}
+		branch := s.label
+		if branch == "" {
+			branch = "-"
+		}
+		lastCommit := "-"
+		if s.LastCommit != nil {
+			lastCommit = formatAge(now.Sub(s.LastCommit.Time)) + "  " + truncate(s.LastCommit.Subject, 50)
+		}
+		baseColumn := formatComparison(s.Base)
+		if s.Base != nil && s.Base.Name != base {
+			// The worktree has a recorded base other than the default branch
+			baseColumn += " (" + s.Base.Name + ")"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n", name, branch, formatChanges(&s), formatUpstream(s.Upstream), baseColumn, lastCommit)
+	}
+	w.Flush()
+
+	for _, s := range statuses {
+		if s.Error != "" {
+			fmt.Fprintf(os.Stderr, "⚠ Failed to get the status of %s: %s\n", s.Path, s.Error)
+		}
+	}
+}
+
+// formatChanges formats the file counts of a worktree
+func formatChanges(s *worktreeStatus) string {
+	switch {
+	case s.Error != "":
+		return "error"
+	case s.Bare || s.Prunable:
+		return "-"
+	}
+
+	var parts []string
+	for _, c := range []struct {
+		count int
+		label string
+	}{
+		{s.Staged, "staged"},
+		{s.Modified, "modified"},
+		{s.Untracked, "untracked"},
+		{s.Conflicts, "conflicted"},
+	} {
+		if c.count > 0 {
+			parts = append(parts, fmt.Sprintf("%d %s", c.count, c.label))
+		}
+	}
+	if len(parts) == 0 {
+		return "clean"
+	}
+	return strings.Join(parts, ", ")
+}
+
+// formatUpstream formats the comparison with the upstream branch
+func formatUpstream(c *statusComparison) string {
+	if c != nil && c.Gone {
+		return "gone"
+	}
+	return formatComparison(c)
+}
+
+// formatComparison formats the commits ahead of and behind another branch
+func formatComparison(c *statusComparison) string {
+	switch {
+	case c == nil:
+		return "-"
+	case c.Ahead == 0 && c.Behind == 0:
+		return "up to date"
+	case c.Behind == 0:
+		return fmt.Sprintf("↑%d", c.Ahead)
+	case c.Ahead == 0:
+		return fmt.Sprintf("↓%d", c.Behind)
+	}
+	return fmt.Sprintf("↑%d ↓%d", c.Ahead, c.Behind)
+}
+
+// formatAge formats a duration as a relative time such as "3 days ago"
+func formatAge(d time.Duration) string {
+	plural := func(n int, unit string) string {
+		if n == 1 {
+			return fmt.Sprintf("1 %s ago", unit)
+		}
+		return fmt.Sprintf("%d %ss ago", n, unit)
+	}
+
+	switch {
+	case d < time.Minute:
+		return "just now"
+	case d < time.Hour:
+		return plural(int(d/time.Minute), "minute")
+	case d < 24*time.Hour:
+		return plural(int(d/time.Hour), "hour")
+	case d < 30*24*time.Hour:
+		return plural(int(d/(24*time.Hour)), "day")
+	case d < 365*24*time.Hour:
+		return plural(int(d/(30*24*time.Hour)), "month")
+	}
+	return plural(int(d/(365*24*time.Hour)), "year")
+}
+
+// truncate shortens s to at most n runes, ending with "…" when it was cut
+func truncate(s string, n int) string {
+	runes := []rune(s)
+	if len(runes) <= n {
+		return s
+	}
+	return string(runes[:n-1]) + "…"
+}
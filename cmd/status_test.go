@@ -0,0 +1,231 @@
+package cmd
+
+import (
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/t98o84/gw/internal/git"
+	"github.com/t98o84/gw/internal/metadata"
+)
+
+func TestStatusCmd_Flags(t *testing.T) {
+	if statusCmd.Flags().Lookup("json") == nil {
+		t.Error("Expected 'json' flag to be defined")
+	}
+
+	flag := statusCmd.Flags().Lookup("jobs")
+	if flag == nil {
+		t.Fatal("Expected 'jobs' flag to be defined")
+	}
+	if flag.Shorthand != "j" {
+		t.Errorf("jobs flag shorthand = %q, want %q", flag.Shorthand, "j")
+	}
+}
+
+func TestCollectConcurrently(t *testing.T) {
+	worktrees := make([]git.Worktree, 20)
+	for i := range worktrees {
+		worktrees[i] = git.Worktree{Path: fmt.Sprintf("/path/to/repo-%d", i)}
+	}
+
+	var running, maxRunning atomic.Int32
+	statuses := collectConcurrently(worktrees, 3, func(wt *git.Worktree) worktreeStatus {
+		n := running.Add(1)
+		for {
+			m := maxRunning.Load()
+			if n <= m || maxRunning.CompareAndSwap(m, n) {
+				break
+			}
+		}
+		time.Sleep(time.Millisecond)
+		running.Add(-1)
+		return worktreeStatus{worktreeJSON: worktreeJSON{Path: wt.Path}}
+	})
+
+	if len(statuses) != len(worktrees) {
+		t.Fatalf("collectConcurrently() returned %d statuses, want %d", len(statuses), len(worktrees))
+	}
+	for i, s := range statuses {
+		if s.Path != worktrees[i].Path {
+			t.Errorf("statuses[%d].Path = %q, want %q", i, s.Path, worktrees[i].Path)
+		}
+	}
+	if maxRunning.Load() > 3 {
+		t.Errorf("collectConcurrently() ran %d jobs at a time, want at most 3", maxRunning.Load())
+	}
+}
+
+func TestCollectStatus(t *testing.T) {
+	lastCommit := time.Unix(1700000000, 0)
+	state := &statusState{
+		base: "origin/main",
+		status: func(path string) (*git.WorktreeStatus, error) {
+			switch path {
+			case "/path/to/repo-feature":
+				return &git.WorktreeStatus{Staged: 1, Modified: 2, Untracked: 3, Upstream: "origin/feature", Ahead: 1}, nil
+			case "/path/to/repo-broken":
+				return nil, fmt.Errorf("git error")
+			}
+			return &git.WorktreeStatus{}, nil
+		},
+		aheadBehind: func(path, base string) (int, int, error) {
+			return 2, 5, nil
+		},
+		lastCommit: func(path string) (time.Time, string, error) {
+			return lastCommit, "Add feature", nil
+		},
+		tagsAt: func(commit string) ([]string, error) {
+			return nil, nil
+		},
+		recorded: map[string]metadata.Worktree{
+			"/path/to/repo-fix": {Branch: "fix", Base: "origin/release"},
+		},
+	}
+
+	tests := []struct {
+		name string
+		wt   git.Worktree
+		want func(t *testing.T, s worktreeStatus)
+	}{
+		{
+			name: "worktree with changes",
+			wt:   git.Worktree{Path: "/path/to/repo-feature", Branch: "feature", Commit: "abc123", Locked: true, LockReason: "release"},
+			want: func(t *testing.T, s worktreeStatus) {
+				if s.Name != "repo-feature" || s.Branch != "feature" || !s.Locked || s.LockReason != "release" {
+					t.Errorf("collectStatus() identity = %+v", s)
+				}
+				if s.Staged != 1 || s.Modified != 2 || s.Untracked != 3 {
+					t.Errorf("collectStatus() counts = %d/%d/%d, want 1/2/3", s.Staged, s.Modified, s.Untracked)
+				}
+				if s.Upstream == nil || s.Upstream.Name != "origin/feature" || s.Upstream.Ahead != 1 {
+					t.Errorf("collectStatus() upstream = %+v", s.Upstream)
+				}
+				if s.Base == nil || s.Base.Name != "origin/main" || s.Base.Ahead != 2 || s.Base.Behind != 5 {
+					t.Errorf("collectStatus() base = %+v", s.Base)
+				}
+				if s.LastCommit == nil || !s.LastCommit.Time.Equal(lastCommit) || s.LastCommit.Subject != "Add feature" {
+					t.Errorf("collectStatus() last commit = %+v", s.LastCommit)
+				}
+			},
+		},
+		{
+			name: "worktree with a recorded base",
+			wt:   git.Worktree{Path: "/path/to/repo-fix", Branch: "fix"},
+			want: func(t *testing.T, s worktreeStatus) {
+				if s.Base == nil || s.Base.Name != "origin/release" {
+					t.Errorf("collectStatus() base = %+v, want the recorded origin/release", s.Base)
+				}
+				if s.Metadata == nil || s.Metadata.Base != "origin/release" {
+					t.Errorf("collectStatus() metadata = %+v", s.Metadata)
+				}
+			},
+		},
+		{
+			name: "prunable worktree is not inspected",
+			wt:   git.Worktree{Path: "/path/to/repo-gone", Branch: "gone", Prunable: true},
+			want: func(t *testing.T, s worktreeStatus) {
+				if !s.Prunable || s.Base != nil || s.LastCommit != nil || s.Error != "" {
+					t.Errorf("collectStatus() = %+v, want only the worktree identity", s)
+				}
+			},
+		},
+		{
+			name: "status fails",
+			wt:   git.Worktree{Path: "/path/to/repo-broken", Branch: "broken"},
+			want: func(t *testing.T, s worktreeStatus) {
+				if s.Error == "" {
+					t.Error("collectStatus() expected the error to be recorded")
+				}
+				if formatChanges(&s) != "error" {
+					t.Errorf("formatChanges() = %q, want %q", formatChanges(&s), "error")
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.want(t, collectStatus(&tt.wt, state))
+		})
+	}
+}
+
+func TestFormatChanges(t *testing.T) {
+	tests := []struct {
+		name string
+		s    worktreeStatus
+		want string
+	}{
+		{name: "clean", s: worktreeStatus{}, want: "clean"},
+		{name: "changes", s: worktreeStatus{Staged: 1, Untracked: 2}, want: "1 staged, 2 untracked"},
+		{name: "conflicts", s: worktreeStatus{Modified: 1, Conflicts: 3}, want: "1 modified, 3 conflicted"},
+		{name: "bare", s: worktreeStatus{worktreeJSON: worktreeJSON{Bare: true}}, want: "-"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := formatChanges(&tt.s); got != tt.want {
+				t.Errorf("formatChanges() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatComparison(t *testing.T) {
+	tests := []struct {
+		name string
+		c    *statusComparison
+		want string
+	}{
+		{name: "none", c: nil, want: "-"},
+		{name: "up to date", c: &statusComparison{}, want: "up to date"},
+		{name: "ahead", c: &statusComparison{Ahead: 2}, want: "↑2"},
+		{name: "behind", c: &statusComparison{Behind: 3}, want: "↓3"},
+		{name: "diverged", c: &statusComparison{Ahead: 2, Behind: 3}, want: "↑2 ↓3"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := formatComparison(tt.c); got != tt.want {
+				t.Errorf("formatComparison() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	if got := formatUpstream(&statusComparison{Gone: true}); got != "gone" {
+		t.Errorf("formatUpstream() = %q, want %q", got, "gone")
+	}
+}
+
+func TestFormatAge(t *testing.T) {
+	tests := []struct {
+		d    time.Duration
+		want string
+	}{
+		{d: 10 * time.Second, want: "just now"},
+		{d: time.Minute, want: "1 minute ago"},
+		{d: 5 * time.Hour, want: "5 hours ago"},
+		{d: 3 * 24 * time.Hour, want: "3 days ago"},
+		{d: 65 * 24 * time.Hour, want: "2 months ago"},
+		{d: 800 * 24 * time.Hour, want: "2 years ago"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			if got := formatAge(tt.d); got != tt.want {
+				t.Errorf("formatAge(%v) = %q, want %q", tt.d, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTruncate(t *testing.T) {
+	if got := truncate("short", 10); got != "short" {
+		t.Errorf("truncate() = %q, want %q", got, "short")
+	}
+	if got := truncate("a long commit subject", 10); got != "a long co…" {
+		t.Errorf("truncate() = %q, want %q", got, "a long co…")
+	}
+}
@@ -18,12 +18,19 @@ var swCmd = &cobra.Command{
 	Long: `Switch to a worktree directory.
 
 If no name is specified and fzf is available, an interactive selector will be shown.
+The selector lists the most recently used worktrees first and leaves out the
+current worktree.
+
+'gw sw -' switches back to the previously used worktree, like 'cd -'. Worktrees
+count as used when they are entered or left with 'gw sw', or entered with
+'gw exec' (see 'gw info').
 
 Note: This command requires shell integration. Run 'gw init <shell>' to set up.
 
 Examples:
   gw sw feature/hoge
   gw sw feature-hoge
+  gw sw -            # Switch back to the previous worktree
   gw sw              # Interactive selection with fzf`,
 	Args: cobra.MaximumNArgs(1),
 	RunE: runSw,
@@ -40,13 +47,18 @@ func runSw(cmd *cobra.Command, args []string) error {
 
 	if len(args) == 0 {
 		// Interactive selection with fzf
-		wt, err = selectWorktreeWithFzf(false)
+		wt, err = selectWorktreeWithFzf(false, true)
 		if err != nil {
 			return err
 		}
 		if wt == nil {
 			return nil // User cancelled
 		}
+	} else if args[0] == "-" {
+		wt, err = findPreviousWorktree()
+		if err != nil {
+			return err
+		}
 	} else {
 		identifier := args[0]
 
@@ -60,6 +72,10 @@ func runSw(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	// Record the worktree being left first, so 'gw sw -' switches back to it like 'cd -'
+	touchCurrentWorktree(wt)
+	touchWorktree(wt)
+
 	if swConfig.SwPrintPath {
 		// Just print the path for shell wrapper to use
 		fmt.Println(wt.Path)
@@ -77,3 +93,34 @@ func runSw(cmd *cobra.Command, args []string) error {
 
 	return nil
 }
+
+// findPreviousWorktree finds the worktree 'gw sw -' switches back to
+func findPreviousWorktree() (*git.Worktree, error) {
+	worktrees, err := git.List()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list worktrees: %w", err)
+	}
+	currentPath, _ := os.Getwd()
+	wt := previousWorktree(worktrees, loadMetadata(), currentPath)
+	if wt == nil {
+		return nil, errors.NewInvalidInputError("-", "no previously used worktree (switch with 'gw sw <name>' first)", nil)
+	}
+	return wt, nil
+}
+
+// touchCurrentWorktree records the worktree gw is running in as used, unless it is target
+func touchCurrentWorktree(target *git.Worktree) {
+	worktrees, err := git.List()
+	if err != nil {
+		return
+	}
+	currentPath, err := os.Getwd()
+	if err != nil {
+		return
+	}
+	current := findCurrentWorktree(currentPath, worktrees)
+	if current == nil || current.IsBare || current.Path == target.Path {
+		return
+	}
+	touchWorktree(current)
+}
@@ -1,6 +1,8 @@
 package cmd
 
 import (
+	"os"
+	"path/filepath"
 	"testing"
 )
 
@@ -36,3 +38,38 @@ func TestSwCmd_PrintPathFlag(t *testing.T) {
 		t.Fatal("Expected 'print-path' flag to be defined")
 	}
 }
+
+func TestRunSw_Previous(t *testing.T) {
+	requireGit(t)
+	base, err := filepath.EvalSymlinks(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	repo := filepath.Join(base, "ex-repo")
+	feature := filepath.Join(base, "ex-repo-feature")
+	gitOutput(t, base, "init", "-q", "-b", "main", repo)
+	if err := os.WriteFile(filepath.Join(repo, "a.txt"), []byte("a\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	gitOutput(t, repo, "add", "a.txt")
+	gitOutput(t, repo, "commit", "-q", "-m", "initial commit")
+	gitOutput(t, repo, "worktree", "add", "-q", "-b", "feature", feature)
+
+	swConfig.SwPrintPath = true
+	defer func() { swConfig.SwPrintPath = false }()
+
+	// Switching from the main worktree records it as the worktree that was left
+	chdirForTest(t, repo)
+	if err := runSw(swCmd, []string{"feature"}); err != nil {
+		t.Fatalf("runSw() error = %v", err)
+	}
+
+	chdirForTest(t, feature)
+	wt, err := findPreviousWorktree()
+	if err != nil {
+		t.Fatalf("findPreviousWorktree() error = %v", err)
+	}
+	if wt.Path != repo {
+		t.Errorf("findPreviousWorktree() = %s, want %s", wt.Path, repo)
+	}
+}
@@ -0,0 +1,136 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/t98o84/gw/internal/filesync"
+)
+
+// syncProgressInterval is how often the progress line is redrawn
+const syncProgressInterval = 100 * time.Millisecond
+
+// syncListLimit is the number of skipped or failed files listed in the summary
+const syncListLimit = 10
+
+// runSync copies the files allowed by filter from the main worktree to the new
+// worktree and prints a summary. kind describes the files, e.g. "gitignored".
+// The files are copied concurrently, with a progress line when stdout is a terminal.
+func runSync(mainWtPath, newWtPath string, files []string, filter *filesync.Filter, kind string) {
+	plan := filesync.NewPlan(mainWtPath, newWtPath, files, filter)
+
+	var onProgress func(filesync.Progress)
+	clearProgress := func() {}
+	if isTerminal(os.Stdout) && len(plan.Jobs) > 0 {
+		onProgress, clearProgress = newSyncProgress(os.Stdout, time.Now)
+	}
+	result := filesync.Run(plan.Jobs, defaultJobs(), onProgress)
+	clearProgress()
+
+	printSyncSummary(os.Stdout, kind, plan, result)
+}
+
+// isTerminal reports whether f is a terminal
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// newSyncProgress returns a progress callback that redraws a status line on w,
+// and a function that clears the line when the sync is done
+func newSyncProgress(w io.Writer, now func() time.Time) (func(filesync.Progress), func()) {
+	start := now()
+	var last time.Time
+	drawn := false
+
+	update := func(p filesync.Progress) {
+		t := now()
+		if drawn && t.Sub(last) < syncProgressInterval && p.Files < p.TotalFiles {
+			return
+		}
+		last = t
+		drawn = true
+		fmt.Fprintf(w, "\r\033[K  %s", formatSyncProgress(p, t.Sub(start)))
+	}
+	clearLine := func() {
+		if drawn {
+			fmt.Fprint(w, "\r\033[K")
+		}
+	}
+	return update, clearLine
+}
+
+// formatSyncProgress formats the progress line, e.g.
+// "120/5000 files, 35.2 MB/1.2 GB, ETA 1m30s"
+func formatSyncProgress(p filesync.Progress, elapsed time.Duration) string {
+	line := fmt.Sprintf("%d/%d files, %s/%s", p.Files, p.TotalFiles, formatBytes(p.Bytes), formatBytes(p.TotalBytes))
+
+	// Estimate from the bytes copied so far, or from the files for empty files
+	var done float64
+	switch {
+	case p.TotalBytes > 0 && p.Bytes > 0:
+		done = float64(p.Bytes) / float64(p.TotalBytes)
+	case p.TotalBytes == 0 && p.Files > 0:
+		done = float64(p.Files) / float64(p.TotalFiles)
+	}
+	if done > 0 && done < 1 {
+		eta := time.Duration(float64(elapsed) * (1 - done) / done)
+		line += ", ETA " + eta.Round(time.Second).String()
+	}
+	return line
+}
+
+// formatBytes formats a size in bytes for humans, e.g. "35.2 MB"
+func formatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit && exp < 3; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %cB", float64(n)/float64(div), "KMGT"[exp])
+}
+
+// printSyncSummary prints what was synced, skipped and failed
+func printSyncSummary(w io.Writer, kind string, plan filesync.Plan, result filesync.Result) {
+	fmt.Fprintf(w, "✓ Synced %d %s files (%s)\n", result.Files, kind, formatBytes(result.Bytes))
+
+	if plan.Excluded > 0 {
+		fmt.Fprintf(w, "ℹ Skipped %d files excluded by sync settings\n", plan.Excluded)
+	}
+	if len(plan.TooLarge) > 0 {
+		fmt.Fprintf(w, "ℹ Skipped %d files larger than sync.max_size:\n", len(plan.TooLarge))
+		printLimited(w, len(plan.TooLarge), func(i int) string {
+			return fmt.Sprintf("%s (%s)", plan.TooLarge[i].Path, formatBytes(plan.TooLarge[i].Size))
+		})
+	}
+	if result.Fallbacks > 0 {
+		fmt.Fprintf(w, "ℹ %d files were copied because their sync strategy isn't supported here\n", result.Fallbacks)
+	}
+
+	failed := append(append([]filesync.Failure{}, plan.Failed...), result.Failed...)
+	if len(failed) > 0 {
+		fmt.Fprintf(w, "⚠ Failed to sync %d files:\n", len(failed))
+		printLimited(w, len(failed), func(i int) string {
+			return fmt.Sprintf("%s: %v", failed[i].Path, failed[i].Err)
+		})
+	}
+}
+
+// printLimited prints the first syncListLimit of n lines, indented
+func printLimited(w io.Writer, n int, line func(i int) string) {
+	var b strings.Builder
+	for i := range min(n, syncListLimit) {
+		fmt.Fprintf(&b, "    %s\n", line(i))
+	}
+	if n > syncListLimit {
+		fmt.Fprintf(&b, "    ... and %d more\n", n-syncListLimit)
+	}
+	fmt.Fprint(w, b.String())
+}
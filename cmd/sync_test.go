@@ -0,0 +1,218 @@
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/t98o84/gw/internal/filesync"
+)
+
+func TestFormatBytes(t *testing.T) {
+	tests := []struct {
+		input int64
+		want  string
+	}{
+		{input: 0, want: "0 B"},
+		{input: 1023, want: "1023 B"},
+		{input: 1024, want: "1.0 KB"},
+		{input: 1536, want: "1.5 KB"},
+		{input: 35 << 20, want: "35.0 MB"},
+		{input: 3 << 29, want: "1.5 GB"},
+		{input: 2 << 40, want: "2.0 TB"},
+	}
+
+	for _, tt := range tests {
+		if got := formatBytes(tt.input); got != tt.want {
+			t.Errorf("formatBytes(%d) = %q, want %q", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestFormatSyncProgress(t *testing.T) {
+	tests := []struct {
+		name     string
+		progress filesync.Progress
+		elapsed  time.Duration
+		want     string
+	}{
+		{
+			name:     "estimate from bytes",
+			progress: filesync.Progress{Files: 10, TotalFiles: 40, Bytes: 25 << 20, TotalBytes: 100 << 20},
+			elapsed:  30 * time.Second,
+			want:     "10/40 files, 25.0 MB/100.0 MB, ETA 1m30s",
+		},
+		{
+			name:     "estimate from files when all files are empty",
+			progress: filesync.Progress{Files: 1, TotalFiles: 2},
+			elapsed:  2 * time.Second,
+			want:     "1/2 files, 0 B/0 B, ETA 2s",
+		},
+		{
+			name:     "no estimate before the first bytes",
+			progress: filesync.Progress{Files: 1, TotalFiles: 2, TotalBytes: 2048},
+			elapsed:  time.Second,
+			want:     "1/2 files, 0 B/2.0 KB",
+		},
+		{
+			name:     "done",
+			progress: filesync.Progress{Files: 2, TotalFiles: 2, Bytes: 2048, TotalBytes: 2048},
+			elapsed:  time.Second,
+			want:     "2/2 files, 2.0 KB/2.0 KB",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := formatSyncProgress(tt.progress, tt.elapsed); got != tt.want {
+				t.Errorf("formatSyncProgress() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewSyncProgress(t *testing.T) {
+	var buf bytes.Buffer
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	update, clearLine := newSyncProgress(&buf, func() time.Time { return now })
+
+	update(filesync.Progress{Files: 1, TotalFiles: 3})
+	// Updates within the interval are dropped, except the last one
+	update(filesync.Progress{Files: 2, TotalFiles: 3})
+	update(filesync.Progress{Files: 3, TotalFiles: 3})
+	clearLine()
+
+	got := buf.String()
+	if strings.Count(got, "files") != 2 {
+		t.Errorf("progress output = %q, want 2 redraws", got)
+	}
+	if !strings.Contains(got, "1/3 files") || !strings.Contains(got, "3/3 files") || strings.Contains(got, "2/3 files") {
+		t.Errorf("progress output = %q, want the first and last update", got)
+	}
+	if !strings.HasSuffix(got, "\r\033[K") {
+		t.Errorf("progress output = %q, want the line cleared at the end", got)
+	}
+}
+
+func TestPrintSyncSummary(t *testing.T) {
+	var tooLarge []filesync.Job
+	for _, name := range []string{"a.bin", "b.bin", "c.bin", "d.bin", "e.bin", "f.bin", "g.bin", "h.bin", "i.bin", "j.bin", "k.bin", "l.bin"} {
+		tooLarge = append(tooLarge, filesync.Job{Path: name, Size: 20 << 20})
+	}
+	plan := filesync.Plan{
+		Excluded: 1534,
+		TooLarge: tooLarge,
+		Failed:   []filesync.Failure{{Path: "unreadable", Err: errors.New("permission denied")}},
+	}
+	result := filesync.Result{
+		Files:     3,
+		Bytes:     1536,
+		Fallbacks: 2,
+		Failed:    []filesync.Failure{{Path: "locked", Err: errors.New("text file busy")}},
+	}
+
+	var buf bytes.Buffer
+	printSyncSummary(&buf, "gitignored", plan, result)
+	got := buf.String()
+
+	for _, want := range []string{
+		"✓ Synced 3 gitignored files (1.5 KB)\n",
+		"ℹ Skipped 1534 files excluded by sync settings\n",
+		"ℹ Skipped 12 files larger than sync.max_size:\n    a.bin (20.0 MB)\n",
+		"    j.bin (20.0 MB)\n    ... and 2 more\n",
+		"ℹ 2 files were copied because their sync strategy isn't supported here\n",
+		"⚠ Failed to sync 2 files:\n    unreadable: permission denied\n    locked: text file busy\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("printSyncSummary() = %q, want it to contain %q", got, want)
+		}
+	}
+
+	buf.Reset()
+	printSyncSummary(&buf, "changed", filesync.Plan{}, filesync.Result{Files: 1, Bytes: 10})
+	if got, want := buf.String(), "✓ Synced 1 changed files (10 B)\n"; got != want {
+		t.Errorf("printSyncSummary() = %q, want %q", got, want)
+	}
+}
+
+func TestSyncAllDiffs(t *testing.T) {
+	requireGit(t)
+	dir := t.TempDir()
+	mainPath := filepath.Join(dir, "repo")
+	wtPath := filepath.Join(dir, "repo-feature")
+
+	write := func(name, content string) {
+		t.Helper()
+		if err := os.MkdirAll(filepath.Dir(filepath.Join(mainPath, name)), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(mainPath, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := os.Mkdir(mainPath, 0755); err != nil {
+		t.Fatal(err)
+	}
+	gitOutput(t, mainPath, "init", "-q", "-b", "main")
+	for _, name := range []string{"both.txt", "deleted.txt", "removed.txt", "renamed.txt", "secret.txt"} {
+		write(name, "line 1\nline 2\nline 3\n")
+	}
+	if err := os.Symlink("both.txt", filepath.Join(mainPath, "link")); err != nil {
+		t.Fatal(err)
+	}
+	gitOutput(t, mainPath, "add", ".")
+	gitOutput(t, mainPath, "commit", "-q", "-m", "init")
+
+	// Staged and unstaged changes to the same file, a staged deletion, an unstaged
+	// deletion, a rename, a retargeted symlink and an untracked file
+	write("both.txt", "line 1\nline 2\nline 3\nstaged\n")
+	gitOutput(t, mainPath, "add", "both.txt")
+	write("both.txt", "line 1\nline 2\nline 3\nstaged\nunstaged\n")
+	gitOutput(t, mainPath, "rm", "-q", "deleted.txt")
+	if err := os.Remove(filepath.Join(mainPath, "removed.txt")); err != nil {
+		t.Fatal(err)
+	}
+	gitOutput(t, mainPath, "mv", "renamed.txt", "moved.txt")
+	if err := os.Remove(filepath.Join(mainPath, "link")); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink("moved.txt", filepath.Join(mainPath, "link")); err != nil {
+		t.Fatal(err)
+	}
+	write("secret.txt", "changed\n")
+	write("new/untracked.txt", "new\n")
+
+	gitOutput(t, mainPath, "worktree", "add", "-q", "-b", "feature", wtPath)
+
+	filter, err := filesync.NewFilter(nil, []string{"secret.txt"}, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := syncAllDiffs(mainPath, wtPath, filter); err != nil {
+		t.Fatalf("syncAllDiffs() error = %v", err)
+	}
+
+	var wantLines []string
+	for _, line := range strings.Split(gitOutput(t, mainPath, "status", "--porcelain"), "\n") {
+		if line != " M secret.txt" {
+			wantLines = append(wantLines, line)
+		}
+	}
+	wantStatus := strings.Join(wantLines, "\n")
+	if got := gitOutput(t, wtPath, "status", "--porcelain"); got != wantStatus {
+		t.Errorf("status of the new worktree =\n%s\nwant\n%s", got, wantStatus)
+	}
+	for _, args := range [][]string{{"diff", "--cached"}, {"diff", "--", ".", ":!secret.txt"}} {
+		if got, want := gitOutput(t, wtPath, args...), gitOutput(t, mainPath, args...); got != want {
+			t.Errorf("git %v in the new worktree =\n%s\nwant\n%s", args, got, want)
+		}
+	}
+	if target, err := os.Readlink(filepath.Join(wtPath, "link")); err != nil || target != "moved.txt" {
+		t.Errorf("link = %q, %v, want a symlink to moved.txt", target, err)
+	}
+}
@@ -1,19 +1,32 @@
 package config
 
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/t98o84/gw/internal/filesync"
+)
+
 // Config represents the application configuration.
 type Config struct {
-	Add    AddConfig   `yaml:"add"`
-	Close  CloseConfig `yaml:"close"`
-	Rm     RmConfig    `yaml:"rm"`
-	Editor string      `yaml:"editor,omitempty"`
+	Add      AddConfig      `yaml:"add"`
+	Close    CloseConfig    `yaml:"close"`
+	Rm       RmConfig       `yaml:"rm"`
+	Worktree WorktreeConfig `yaml:"worktree,omitempty"`
+	Sync     SyncConfig     `yaml:"sync,omitempty"`
+	Editor   string         `yaml:"editor,omitempty"`
+	Remote   string         `yaml:"remote,omitempty"`
 }
 
 // AddConfig represents the configuration for the add command.
 type AddConfig struct {
-	Open        bool   `yaml:"open"`
-	Sync        bool   `yaml:"sync"`
-	SyncIgnored bool   `yaml:"sync_ignored"`
-	From        string `yaml:"from,omitempty"`
+	Open         bool   `yaml:"open"`
+	Sync         bool   `yaml:"sync"`
+	SyncIgnored  bool   `yaml:"sync_ignored"`
+	From         string `yaml:"from,omitempty"`
+	PushUpstream bool   `yaml:"push_upstream"`
 }
 
 // CloseConfig represents the configuration for the close command.
@@ -27,14 +40,43 @@ type RmConfig struct {
 	Branch bool `yaml:"branch"`
 }
 
+// WorktreeConfig represents the configuration for worktree locations.
+type WorktreeConfig struct {
+	// Path is a text/template for the worktree directory.
+	// Empty means the default "<repo parent>/<repo>-<suffix>" layout.
+	Path string `yaml:"path,omitempty"`
+}
+
+// SyncConfig represents the configuration for the files synced by add --sync and --sync-ignored.
+type SyncConfig struct {
+	// Include limits syncing to files matching one of these glob patterns.
+	// Empty means all files.
+	Include []string `yaml:"include,omitempty"`
+	// Exclude skips files matching any of these glob patterns.
+	Exclude []string `yaml:"exclude,omitempty"`
+	// MaxSize skips files larger than this size (e.g. "10MB"). Empty means no limit.
+	MaxSize string `yaml:"max_size,omitempty"`
+	// Strategy is how files are synced: copy, reflink, hardlink or symlink. Empty means copy.
+	Strategy string `yaml:"strategy,omitempty"`
+	// Rules choose the strategy for files matching a pattern. The first matching rule wins.
+	Rules []SyncRule `yaml:"rules,omitempty"`
+}
+
+// SyncRule selects the sync strategy for files matching a glob pattern.
+type SyncRule struct {
+	Pattern  string `yaml:"pattern"`
+	Strategy string `yaml:"strategy"`
+}
+
 // NewConfig returns a new Config with default values.
 func NewConfig() *Config {
 	return &Config{
 		Add: AddConfig{
-			Open:        false,
-			Sync:        false,
-			SyncIgnored: false,
-			From:        "",
+			Open:         false,
+			Sync:         false,
+			SyncIgnored:  false,
+			From:         "",
+			PushUpstream: false,
 		},
 		Close: CloseConfig{
 			Force: false,
@@ -49,10 +91,108 @@ func NewConfig() *Config {
 
 // Validate checks if the configuration is valid.
 func (c *Config) Validate() error {
-	// Currently no validation rules, but this method is here for future use
+	if err := ValidatePathTemplate(c.Worktree.Path); err != nil {
+		return err
+	}
+	if err := c.Sync.Validate(); err != nil {
+		return err
+	}
 	return nil
 }
 
+// ValidatePathTemplate checks that a worktree.path template can be parsed.
+func ValidatePathTemplate(tmpl string) error {
+	if tmpl == "" {
+		return nil
+	}
+	if _, err := template.New("worktree.path").Parse(tmpl); err != nil {
+		return fmt.Errorf("invalid worktree.path template: %w", err)
+	}
+	return nil
+}
+
+// Validate checks that the sync patterns, size limit and strategies can be parsed.
+func (s SyncConfig) Validate() error {
+	for _, p := range append(append([]string{}, s.Include...), s.Exclude...) {
+		if err := filesync.ValidatePattern(p); err != nil {
+			return fmt.Errorf("invalid sync pattern: %w", err)
+		}
+	}
+	if _, err := s.MaxSizeBytes(); err != nil {
+		return err
+	}
+	if _, err := filesync.ParseStrategy(s.Strategy); err != nil {
+		return fmt.Errorf("invalid sync.strategy: %w", err)
+	}
+	for _, r := range s.Rules {
+		if err := filesync.ValidatePattern(r.Pattern); err != nil {
+			return fmt.Errorf("invalid sync rule: %w", err)
+		}
+		if _, err := filesync.ParseStrategy(r.Strategy); err != nil {
+			return fmt.Errorf("invalid sync rule for %q: %w", r.Pattern, err)
+		}
+	}
+	return nil
+}
+
+// MaxSizeBytes returns the size limit in bytes, or 0 if there is no limit.
+func (s SyncConfig) MaxSizeBytes() (int64, error) {
+	if s.MaxSize == "" {
+		return 0, nil
+	}
+	size, err := ParseSize(s.MaxSize)
+	if err != nil {
+		return 0, fmt.Errorf("invalid sync.max_size: %w", err)
+	}
+	return size, nil
+}
+
+// Merge combines the sync settings with those of a project config.
+// The patterns and rules of both are used, with the project's rules checked first;
+// the project's max_size and strategy take precedence.
+func (s SyncConfig) Merge(project SyncConfig) SyncConfig {
+	merged := SyncConfig{
+		Include:  append(append([]string{}, s.Include...), project.Include...),
+		Exclude:  append(append([]string{}, s.Exclude...), project.Exclude...),
+		MaxSize:  s.MaxSize,
+		Strategy: s.Strategy,
+		Rules:    append(append([]SyncRule{}, project.Rules...), s.Rules...),
+	}
+	if project.MaxSize != "" {
+		merged.MaxSize = project.MaxSize
+	}
+	if project.Strategy != "" {
+		merged.Strategy = project.Strategy
+	}
+	return merged
+}
+
+// ParseSize parses a size such as "512KB", "10MB", "1GB" or a plain number of bytes.
+// Units are powers of 1024.
+func ParseSize(s string) (int64, error) {
+	value := strings.ToUpper(strings.TrimSpace(s))
+	multiplier := int64(1)
+	for _, unit := range []struct {
+		suffix string
+		size   int64
+	}{
+		{"GB", 1 << 30}, {"MB", 1 << 20}, {"KB", 1 << 10},
+		{"G", 1 << 30}, {"M", 1 << 20}, {"K", 1 << 10}, {"B", 1},
+	} {
+		if strings.HasSuffix(value, unit.suffix) {
+			value = strings.TrimSpace(strings.TrimSuffix(value, unit.suffix))
+			multiplier = unit.size
+			break
+		}
+	}
+
+	n, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("invalid size %q (use e.g. 512KB, 10MB or 1GB)", s)
+	}
+	return n * multiplier, nil
+}
+
 // MergeWithFlags merges the configuration with command-line flags.
 // Flags take precedence over config file values.
 // The --no-* flags have the highest priority and will force the value to false.
@@ -66,18 +206,23 @@ func (c *Config) MergeWithFlags(
 	syncFlag *bool,
 	syncIgnoredFlag *bool,
 	fromFlag *string,
+	trackFlag *bool,
 	noOpenFlag bool,
 	noSyncFlag bool,
 	noSyncIgnoredFlag bool,
 	closeNoYesFlag bool,
 	rmNoYesFlag bool,
 	rmNoBranchFlag bool,
+	noTrackFlag bool,
 ) *Config {
 	merged := &Config{
-		Add:    c.Add,
-		Close:  c.Close,
-		Rm:     c.Rm,
-		Editor: c.Editor,
+		Add:      c.Add,
+		Close:    c.Close,
+		Rm:       c.Rm,
+		Worktree: c.Worktree,
+		Sync:     c.Sync,
+		Editor:   c.Editor,
+		Remote:   c.Remote,
 	}
 
 	// Apply normal flags
@@ -97,6 +242,10 @@ func (c *Config) MergeWithFlags(
 		merged.Add.From = *fromFlag
 	}
 
+	if trackFlag != nil {
+		merged.Add.PushUpstream = *trackFlag
+	}
+
 	if editorFlag != nil && *editorFlag != "" {
 		merged.Editor = *editorFlag
 	}
@@ -138,6 +287,10 @@ func (c *Config) MergeWithFlags(
 		merged.Rm.Branch = false
 	}
 
+	if noTrackFlag {
+		merged.Add.PushUpstream = false
+	}
+
 	return merged
 }
 
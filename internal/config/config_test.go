@@ -40,6 +40,129 @@ func TestConfig_Validate(t *testing.T) {
 	}
 }
 
+func TestConfig_Validate_WorktreePath(t *testing.T) {
+	tests := []struct {
+		name    string
+		path    string
+		wantErr bool
+	}{
+		{name: "empty", path: "", wantErr: false},
+		{name: "valid template", path: "{{.RepoParent}}/{{.Repo}}-worktrees/{{.Suffix}}", wantErr: false},
+		{name: "plain path", path: "~/worktrees", wantErr: false},
+		{name: "syntax error", path: "{{.Repo", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := NewConfig()
+			cfg.Worktree.Path = tt.path
+			if err := cfg.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestConfig_Validate_Sync(t *testing.T) {
+	tests := []struct {
+		name    string
+		sync    SyncConfig
+		wantErr bool
+	}{
+		{name: "empty", sync: SyncConfig{}, wantErr: false},
+		{name: "valid", sync: SyncConfig{Include: []string{".env", "config/*.yml"}, Exclude: []string{"**/node_modules"}, MaxSize: "10MB"}, wantErr: false},
+		{name: "malformed include", sync: SyncConfig{Include: []string{"[a-"}}, wantErr: true},
+		{name: "empty exclude", sync: SyncConfig{Exclude: []string{""}}, wantErr: true},
+		{name: "invalid max size", sync: SyncConfig{MaxSize: "10XB"}, wantErr: true},
+		{name: "valid strategies", sync: SyncConfig{Strategy: "reflink", Rules: []SyncRule{{Pattern: "node_modules", Strategy: "hardlink"}}}, wantErr: false},
+		{name: "invalid strategy", sync: SyncConfig{Strategy: "move"}, wantErr: true},
+		{name: "rule without strategy copies", sync: SyncConfig{Rules: []SyncRule{{Pattern: "node_modules"}}}, wantErr: false},
+		{name: "invalid rule pattern", sync: SyncConfig{Rules: []SyncRule{{Pattern: "[a-", Strategy: "copy"}}}, wantErr: true},
+		{name: "unknown rule strategy", sync: SyncConfig{Rules: []SyncRule{{Pattern: "node_modules", Strategy: "link"}}}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := NewConfig()
+			cfg.Sync = tt.sync
+			if err := cfg.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestSyncConfig_Merge(t *testing.T) {
+	user := SyncConfig{
+		Exclude:  []string{"node_modules"},
+		MaxSize:  "1MB",
+		Strategy: "reflink",
+		Rules:    []SyncRule{{Pattern: "node_modules", Strategy: "hardlink"}},
+	}
+	project := SyncConfig{
+		Include:  []string{".env"},
+		Exclude:  []string{"target"},
+		MaxSize:  "10MB",
+		Strategy: "copy",
+		Rules:    []SyncRule{{Pattern: "node_modules", Strategy: "symlink"}},
+	}
+
+	merged := user.Merge(project)
+	if len(merged.Include) != 1 || merged.Include[0] != ".env" {
+		t.Errorf("Merge() Include = %v, want [.env]", merged.Include)
+	}
+	if len(merged.Exclude) != 2 || merged.Exclude[0] != "node_modules" || merged.Exclude[1] != "target" {
+		t.Errorf("Merge() Exclude = %v, want [node_modules target]", merged.Exclude)
+	}
+	if merged.MaxSize != "10MB" {
+		t.Errorf("Merge() MaxSize = %q, want %q", merged.MaxSize, "10MB")
+	}
+
+	if merged.Strategy != "copy" {
+		t.Errorf("Merge() Strategy = %q, want %q", merged.Strategy, "copy")
+	}
+	if len(merged.Rules) != 2 || merged.Rules[0].Strategy != "symlink" || merged.Rules[1].Strategy != "hardlink" {
+		t.Errorf("Merge() Rules = %v, want the project rule first", merged.Rules)
+	}
+
+	if got := user.Merge(SyncConfig{}).MaxSize; got != "1MB" {
+		t.Errorf("Merge() MaxSize = %q, want the user value %q", got, "1MB")
+	}
+}
+
+func TestParseSize(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    int64
+		wantErr bool
+	}{
+		{input: "1024", want: 1024},
+		{input: "100B", want: 100},
+		{input: "512KB", want: 512 << 10},
+		{input: "10MB", want: 10 << 20},
+		{input: "10m", want: 10 << 20},
+		{input: "1GB", want: 1 << 30},
+		{input: "2 MB", want: 2 << 20},
+		{input: "", wantErr: true},
+		{input: "MB", wantErr: true},
+		{input: "1.5MB", wantErr: true},
+		{input: "-1KB", wantErr: true},
+		{input: "10TB", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseSize(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseSize(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseSize(%q) = %d, want %d", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
 func TestConfig_MergeWithFlags(t *testing.T) {
 	tests := []struct {
 		name              string
@@ -450,12 +573,14 @@ func TestConfig_MergeWithFlags(t *testing.T) {
 				nil,
 				nil,
 				nil,
+				nil,
 				tt.noOpenFlag,
 				tt.noSyncFlag,
 				tt.noSyncIgnoredFlag,
 				tt.closeNoYesFlag,
 				tt.rmNoYesFlag,
 				tt.rmNoBranchFlag,
+				false,
 			)
 			if merged.Add.Open != tt.wantOpen {
 				t.Errorf("MergeWithFlags() Add.Open = %v, want %v", merged.Add.Open, tt.wantOpen)
@@ -542,6 +667,8 @@ func TestConfig_MergeWithFlags_From(t *testing.T) {
 				nil,
 				nil,
 				tt.fromFlag,
+				nil,
+				false,
 				false,
 				false,
 				false,
@@ -556,6 +683,60 @@ func TestConfig_MergeWithFlags_From(t *testing.T) {
 	}
 }
 
+func TestConfig_MergeWithFlags_Track(t *testing.T) {
+	tests := []struct {
+		name        string
+		config      *Config
+		trackFlag   *bool
+		noTrackFlag bool
+		want        bool
+	}{
+		{
+			name:   "no flag, use config",
+			config: &Config{Add: AddConfig{PushUpstream: true}},
+			want:   true,
+		},
+		{
+			name:      "track flag overrides config",
+			config:    &Config{Add: AddConfig{PushUpstream: false}},
+			trackFlag: boolPtr(true),
+			want:      true,
+		},
+		{
+			name:        "no-track flag overrides config",
+			config:      &Config{Add: AddConfig{PushUpstream: true}},
+			noTrackFlag: true,
+			want:        false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			merged := tt.config.MergeWithFlags(
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				tt.trackFlag,
+				false,
+				false,
+				false,
+				false,
+				false,
+				false,
+				tt.noTrackFlag,
+			)
+			if merged.Add.PushUpstream != tt.want {
+				t.Errorf("MergeWithFlags() Add.PushUpstream = %v, want %v", merged.Add.PushUpstream, tt.want)
+			}
+		})
+	}
+}
+
 func TestConfig_GetEditor(t *testing.T) {
 	tests := []struct {
 		name   string
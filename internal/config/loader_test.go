@@ -43,6 +43,7 @@ func TestLoad(t *testing.T) {
 				content := `add:
   open: true
 editor: code
+remote: upstream
 `
 				if err := os.WriteFile(configPath, []byte(content), 0644); err != nil {
 					t.Fatalf("Failed to write config file: %v", err)
@@ -54,6 +55,7 @@ editor: code
 					Open: true,
 				},
 				Editor: "code",
+				Remote: "upstream",
 			},
 		},
 		{
@@ -90,6 +92,9 @@ editor: code
 				if cfg.Editor != tt.wantConfig.Editor {
 					t.Errorf("Load() Editor = %v, want %v", cfg.Editor, tt.wantConfig.Editor)
 				}
+				if cfg.Remote != tt.wantConfig.Remote {
+					t.Errorf("Load() Remote = %v, want %v", cfg.Remote, tt.wantConfig.Remote)
+				}
 			}
 
 			// Clean up for next test
@@ -10,7 +10,9 @@ import (
 
 // ProjectConfig represents the project-specific configuration from gw.yaml
 type ProjectConfig struct {
-	Hooks HooksConfig `yaml:"hooks"`
+	Worktree WorktreeConfig `yaml:"worktree,omitempty"`
+	Sync     SyncConfig     `yaml:"sync,omitempty"`
+	Hooks    HooksConfig    `yaml:"hooks"`
 }
 
 // HooksConfig represents the hooks configuration
@@ -43,6 +45,12 @@ func FindProjectConfig(repoRoot string) (*ProjectConfig, error) {
 	if err := yaml.Unmarshal(data, &cfg); err != nil {
 		return nil, fmt.Errorf("failed to parse project config: %w", err)
 	}
+	if err := ValidatePathTemplate(cfg.Worktree.Path); err != nil {
+		return nil, fmt.Errorf("failed to parse project config: %w", err)
+	}
+	if err := cfg.Sync.Validate(); err != nil {
+		return nil, fmt.Errorf("failed to parse project config: %w", err)
+	}
 
 	return &cfg, nil
 }
@@ -58,6 +58,72 @@ func TestFindProjectConfig(t *testing.T) {
 			expectedNil: false,
 			expectError: false,
 		},
+		{
+			name: "valid config with worktree path",
+			setupFunc: func() string {
+				dir := t.TempDir()
+				content := `worktree:
+  path: "{{.RepoRoot}}/.worktrees/{{.Suffix}}"
+`
+				err := os.WriteFile(filepath.Join(dir, "gw.yaml"), []byte(content), 0644)
+				if err != nil {
+					t.Fatal(err)
+				}
+				return dir
+			},
+			expectedNil: false,
+			expectError: false,
+		},
+		{
+			name: "invalid worktree path template",
+			setupFunc: func() string {
+				dir := t.TempDir()
+				content := `worktree:
+  path: "{{.RepoRoot"
+`
+				err := os.WriteFile(filepath.Join(dir, "gw.yaml"), []byte(content), 0644)
+				if err != nil {
+					t.Fatal(err)
+				}
+				return dir
+			},
+			expectedNil: false,
+			expectError: true,
+		},
+		{
+			name: "valid config with sync patterns",
+			setupFunc: func() string {
+				dir := t.TempDir()
+				content := `sync:
+  include: [".env", "config/local.yml"]
+  exclude: ["node_modules"]
+  max_size: 10MB
+`
+				err := os.WriteFile(filepath.Join(dir, "gw.yaml"), []byte(content), 0644)
+				if err != nil {
+					t.Fatal(err)
+				}
+				return dir
+			},
+			expectedNil: false,
+			expectError: false,
+		},
+		{
+			name: "invalid sync max size",
+			setupFunc: func() string {
+				dir := t.TempDir()
+				content := `sync:
+  max_size: big
+`
+				err := os.WriteFile(filepath.Join(dir, "gw.yaml"), []byte(content), 0644)
+				if err != nil {
+					t.Fatal(err)
+				}
+				return dir
+			},
+			expectedNil: false,
+			expectError: true,
+		},
 		{
 			name: "invalid yaml",
 			setupFunc: func() string {
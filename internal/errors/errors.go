@@ -37,7 +37,9 @@ func NewBranchNotFoundError(branch string, err error) *BranchNotFoundError {
 type WorktreeExistsError struct {
 	Path   string
 	Branch string
-	Err    error
+	// Suggestion is an alternative path that is free to use (optional)
+	Suggestion string
+	Err        error
 }
 
 func (e *WorktreeExistsError) Error() string {
@@ -61,6 +63,11 @@ func NewWorktreeExistsError(path, branch string, err error) *WorktreeExistsError
 	return &WorktreeExistsError{Path: path, Branch: branch, Err: err}
 }
 
+// NewWorktreeExistsErrorWithSuggestion creates a new WorktreeExistsError with an alternative path
+func NewWorktreeExistsErrorWithSuggestion(path, branch, suggestion string, err error) *WorktreeExistsError {
+	return &WorktreeExistsError{Path: path, Branch: branch, Suggestion: suggestion, Err: err}
+}
+
 // GitHubAPIError represents an error from the GitHub API
 type GitHubAPIError struct {
 	Operation string
@@ -269,6 +276,38 @@ func NewNotInWorktreeError(path string, err error) *NotInWorktreeError {
 	return &NotInWorktreeError{Path: path, Err: err}
 }
 
+// WorktreeLockedError represents an error when a worktree is locked
+type WorktreeLockedError struct {
+	Path   string
+	Reason string
+	Err    error
+}
+
+func (e *WorktreeLockedError) Error() string {
+	msg := fmt.Sprintf("worktree is locked: %s", e.Path)
+	if e.Reason != "" {
+		msg += fmt.Sprintf(" (reason: %s)", e.Reason)
+	}
+	if e.Err != nil {
+		msg += fmt.Sprintf(": %v", e.Err)
+	}
+	return msg
+}
+
+func (e *WorktreeLockedError) Unwrap() error {
+	return e.Err
+}
+
+func (e *WorktreeLockedError) Is(target error) bool {
+	_, ok := target.(*WorktreeLockedError)
+	return ok
+}
+
+// NewWorktreeLockedError creates a new WorktreeLockedError
+func NewWorktreeLockedError(path, reason string, err error) *WorktreeLockedError {
+	return &WorktreeLockedError{Path: path, Reason: reason, Err: err}
+}
+
 // Helper functions to check error types
 
 // IsBranchNotFoundError checks if an error is a BranchNotFoundError
@@ -281,6 +320,16 @@ func IsWorktreeExistsError(err error) bool {
 	return errors.Is(err, &WorktreeExistsError{})
 }
 
+// WorktreeExistsSuggestion returns the suggested alternative path of a WorktreeExistsError.
+// Returns an empty string if err is not a WorktreeExistsError or has no suggestion.
+func WorktreeExistsSuggestion(err error) string {
+	var existsErr *WorktreeExistsError
+	if errors.As(err, &existsErr) {
+		return existsErr.Suggestion
+	}
+	return ""
+}
+
 // IsGitHubAPIError checks if an error is a GitHubAPIError
 func IsGitHubAPIError(err error) bool {
 	return errors.Is(err, &GitHubAPIError{})
@@ -315,3 +364,8 @@ func IsFzfNotInstalledError(err error) bool {
 func IsNotInWorktreeError(err error) bool {
 	return errors.Is(err, &NotInWorktreeError{})
 }
+
+// IsWorktreeLockedError checks if an error is a WorktreeLockedError
+func IsWorktreeLockedError(err error) bool {
+	return errors.Is(err, &WorktreeLockedError{})
+}
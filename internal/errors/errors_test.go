@@ -2,6 +2,7 @@ package errors
 
 import (
 	"errors"
+	"fmt"
 	"os/exec"
 	"testing"
 )
@@ -96,6 +97,19 @@ func TestWorktreeExistsError(t *testing.T) {
 			t.Error("IsWorktreeExistsError() should return true")
 		}
 	})
+
+	t.Run("suggestion is available through wrapped errors", func(t *testing.T) {
+		err := fmt.Errorf("add failed: %w", NewWorktreeExistsErrorWithSuggestion("/path", "a/b", "/path-1234abcd", nil))
+		if got := WorktreeExistsSuggestion(err); got != "/path-1234abcd" {
+			t.Errorf("WorktreeExistsSuggestion() = %q, want %q", got, "/path-1234abcd")
+		}
+		if got := WorktreeExistsSuggestion(NewWorktreeExistsError("/path", "main", nil)); got != "" {
+			t.Errorf("WorktreeExistsSuggestion() = %q, want empty", got)
+		}
+		if got := WorktreeExistsSuggestion(errors.New("other")); got != "" {
+			t.Errorf("WorktreeExistsSuggestion() = %q, want empty", got)
+		}
+	})
 }
 
 func TestGitHubAPIError(t *testing.T) {
@@ -507,3 +521,36 @@ func TestFzfNotInstalledError(t *testing.T) {
 		}
 	})
 }
+
+func TestWorktreeLockedError(t *testing.T) {
+	t.Run("error message without reason", func(t *testing.T) {
+		err := NewWorktreeLockedError("/path/to/wt", "", nil)
+		expected := "worktree is locked: /path/to/wt"
+		if err.Error() != expected {
+			t.Errorf("Expected %q, got %q", expected, err.Error())
+		}
+	})
+
+	t.Run("error message with reason", func(t *testing.T) {
+		err := NewWorktreeLockedError("/path/to/wt", "on removable disk", nil)
+		expected := "worktree is locked: /path/to/wt (reason: on removable disk)"
+		if err.Error() != expected {
+			t.Errorf("Expected %q, got %q", expected, err.Error())
+		}
+	})
+
+	t.Run("unwrap returns wrapped error", func(t *testing.T) {
+		wrappedErr := errors.New("wrapped")
+		err := NewWorktreeLockedError("/path", "", wrappedErr)
+		if err.Unwrap() != wrappedErr {
+			t.Error("Unwrap() should return the wrapped error")
+		}
+	})
+
+	t.Run("errors.Is() works with helper function", func(t *testing.T) {
+		err := fmt.Errorf("wrapped: %w", NewWorktreeLockedError("/path", "", nil))
+		if !IsWorktreeLockedError(err) {
+			t.Error("IsWorktreeLockedError() should return true")
+		}
+	})
+}
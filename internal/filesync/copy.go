@@ -0,0 +1,92 @@
+package filesync
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// Copy syncs the file src to dst with the given strategy, creating directories
+// as needed. A strategy the filesystem doesn't support, e.g. reflink on ext4 or
+// hardlink across devices, falls back to a full copy. If src is a symlink, the
+// link itself is copied.
+// It returns the strategy that was used.
+func Copy(src, dst string, strategy Strategy) (Strategy, error) {
+	// Create destination directory if it doesn't exist
+	if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+		return "", fmt.Errorf("failed to create directory: %w", err)
+	}
+
+	// Symlinks are recreated as they are, whatever the strategy
+	if info, err := os.Lstat(src); err == nil && info.Mode()&os.ModeSymlink != 0 {
+		target, err := os.Readlink(src)
+		if err != nil {
+			return "", fmt.Errorf("failed to read symlink: %w", err)
+		}
+		if err := replaceWith(dst, func() error { return os.Symlink(target, dst) }); err != nil {
+			return "", fmt.Errorf("failed to create symlink: %w", err)
+		}
+		return strategy, nil
+	}
+
+	switch strategy {
+	case StrategyHardlink:
+		if err := replaceWith(dst, func() error { return os.Link(src, dst) }); err == nil {
+			return StrategyHardlink, nil
+		}
+	case StrategySymlink:
+		if absSrc, err := filepath.Abs(src); err == nil {
+			if err := replaceWith(dst, func() error { return os.Symlink(absSrc, dst) }); err == nil {
+				return StrategySymlink, nil
+			}
+		}
+	}
+
+	return copyContents(src, dst, strategy == StrategyReflink)
+}
+
+// replaceWith removes dst and creates it again with link
+func replaceWith(dst string, link func() error) error {
+	if err := os.Remove(dst); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return link()
+}
+
+// copyContents copies the contents and permissions of src to dst,
+// trying a copy-on-write clone first when reflink is set
+func copyContents(src, dst string, reflink bool) (Strategy, error) {
+	// Open source file
+	srcFile, err := os.Open(src)
+	if err != nil {
+		return "", fmt.Errorf("failed to open source file: %w", err)
+	}
+	defer srcFile.Close()
+
+	srcInfo, err := srcFile.Stat()
+	if err != nil {
+		return "", fmt.Errorf("failed to get source file info: %w", err)
+	}
+
+	// Create destination file
+	dstFile, err := os.OpenFile(dst, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0666)
+	if err != nil {
+		return "", fmt.Errorf("failed to create destination file: %w", err)
+	}
+	defer dstFile.Close()
+
+	used := StrategyCopy
+	if reflink && cloneFile(dstFile, srcFile) == nil {
+		used = StrategyReflink
+	} else if _, err := io.Copy(dstFile, srcFile); err != nil {
+		return "", fmt.Errorf("failed to copy file contents: %w", err)
+	}
+
+	// Copy permissions
+	if err := os.Chmod(dst, srcInfo.Mode()); err != nil {
+		return "", fmt.Errorf("failed to set file permissions: %w", err)
+	}
+
+	return used, nil
+}
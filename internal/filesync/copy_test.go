@@ -0,0 +1,112 @@
+package filesync
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestCopy(t *testing.T) {
+	tests := []struct {
+		strategy Strategy
+		// want lists the strategies that may be used; reflink depends on the filesystem
+		want []Strategy
+	}{
+		{strategy: StrategyCopy, want: []Strategy{StrategyCopy}},
+		{strategy: StrategyReflink, want: []Strategy{StrategyReflink, StrategyCopy}},
+		{strategy: StrategyHardlink, want: []Strategy{StrategyHardlink}},
+		{strategy: StrategySymlink, want: []Strategy{StrategySymlink}},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.strategy), func(t *testing.T) {
+			dir := t.TempDir()
+			src := filepath.Join(dir, "main", ".env")
+			dst := filepath.Join(dir, "feature", "config", ".env")
+			if err := os.MkdirAll(filepath.Dir(src), 0755); err != nil {
+				t.Fatal(err)
+			}
+			if err := os.WriteFile(src, []byte("SECRET=1\n"), 0600); err != nil {
+				t.Fatal(err)
+			}
+			// An existing destination is replaced
+			if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+				t.Fatal(err)
+			}
+			if err := os.WriteFile(dst, []byte("old\n"), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			used, err := Copy(src, dst, tt.strategy)
+			if err != nil {
+				t.Fatalf("Copy() error = %v", err)
+			}
+			if !containsStrategy(tt.want, used) {
+				t.Errorf("Copy() used %q, want one of %v", used, tt.want)
+			}
+
+			data, err := os.ReadFile(dst)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(data) != "SECRET=1\n" {
+				t.Errorf("destination contents = %q, want %q", data, "SECRET=1\n")
+			}
+
+			info, err := os.Lstat(dst)
+			if err != nil {
+				t.Fatal(err)
+			}
+			isLink := info.Mode()&os.ModeSymlink != 0
+			if isLink != (used == StrategySymlink) {
+				t.Errorf("destination is symlink = %v, want %v", isLink, used == StrategySymlink)
+			}
+			if !isLink && info.Mode().Perm() != 0600 {
+				t.Errorf("destination mode = %v, want 0600", info.Mode().Perm())
+			}
+		})
+	}
+}
+
+func TestCopy_Symlink(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "a.txt"), []byte("a"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	src := filepath.Join(dir, "link")
+	if err := os.Symlink("a.txt", src); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, strategy := range []Strategy{StrategyCopy, StrategyHardlink} {
+		dst := filepath.Join(dir, "out-"+string(strategy), "link")
+		if _, err := Copy(src, dst, strategy); err != nil {
+			t.Fatalf("Copy(%s) error = %v", strategy, err)
+		}
+		target, err := os.Readlink(dst)
+		if err != nil {
+			t.Fatalf("Copy(%s) didn't create a symlink: %v", strategy, err)
+		}
+		if target != "a.txt" {
+			t.Errorf("Copy(%s) symlink target = %q, want %q", strategy, target, "a.txt")
+		}
+	}
+}
+
+func TestCopy_MissingSource(t *testing.T) {
+	dir := t.TempDir()
+	for _, strategy := range []Strategy{StrategyCopy, StrategyReflink, StrategyHardlink} {
+		if _, err := Copy(filepath.Join(dir, "missing"), filepath.Join(dir, "dst"), strategy); err == nil {
+			t.Errorf("Copy(%s) error = nil, want error for a missing source", strategy)
+		}
+	}
+}
+
+func containsStrategy(strategies []Strategy, s Strategy) bool {
+	for _, v := range strategies {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
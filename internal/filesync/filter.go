@@ -0,0 +1,128 @@
+// Package filesync selects and copies the files synced into new worktrees.
+package filesync
+
+import (
+	"fmt"
+	"path"
+	"strings"
+)
+
+// Filter decides which files are synced from the main worktree.
+// A nil Filter allows every file.
+type Filter struct {
+	include []pattern
+	exclude []pattern
+	// maxSize is the largest file size in bytes that is synced, or 0 for no limit
+	maxSize int64
+	// defaultStrategy and rules choose how files are synced (see SetStrategies)
+	defaultStrategy Strategy
+	rules           []compiledRule
+}
+
+// pattern is a compiled glob pattern split into path segments
+type pattern []string
+
+// NewFilter creates a filter from include and exclude glob patterns.
+//
+// Patterns are matched against paths relative to the worktree root, using /
+// as the separator. "*", "?" and "[...]" match within a path segment, and
+// "**" matches any number of segments. A pattern without a "/" matches at
+// any depth, and a pattern matching a directory also matches everything in it.
+//
+// When include patterns are given, only files matching one of them are synced.
+// Files matching an exclude pattern are never synced.
+func NewFilter(include, exclude []string, maxSize int64) (*Filter, error) {
+	f := &Filter{maxSize: maxSize}
+	for _, p := range include {
+		compiled, err := compilePattern(p)
+		if err != nil {
+			return nil, err
+		}
+		f.include = append(f.include, compiled)
+	}
+	for _, p := range exclude {
+		compiled, err := compilePattern(p)
+		if err != nil {
+			return nil, err
+		}
+		f.exclude = append(f.exclude, compiled)
+	}
+	return f, nil
+}
+
+// ValidatePattern checks that a glob pattern is well-formed
+func ValidatePattern(p string) error {
+	_, err := compilePattern(p)
+	return err
+}
+
+func compilePattern(p string) (pattern, error) {
+	trimmed := strings.Trim(p, "/")
+	if trimmed == "" {
+		return nil, fmt.Errorf("invalid pattern %q: pattern is empty", p)
+	}
+	if _, err := path.Match(trimmed, ""); err != nil {
+		return nil, fmt.Errorf("invalid pattern %q: %w", p, err)
+	}
+
+	segments := strings.Split(trimmed, "/")
+	if !strings.Contains(trimmed, "/") && trimmed != "**" {
+		// Like .gitignore, a bare name matches at any depth
+		segments = append([]string{"**"}, segments...)
+	}
+	return segments, nil
+}
+
+// MatchPath reports whether the file at the relative path rel passes the
+// include and exclude patterns
+func (f *Filter) MatchPath(rel string) bool {
+	if f == nil {
+		return true
+	}
+	segments := splitPath(rel)
+	if len(f.include) > 0 && !matchAny(f.include, segments) {
+		return false
+	}
+	return !matchAny(f.exclude, segments)
+}
+
+// MatchSize reports whether a file of the given size is within the size limit
+func (f *Filter) MatchSize(size int64) bool {
+	return f == nil || f.maxSize <= 0 || size <= f.maxSize
+}
+
+// splitPath splits a relative path into the segments patterns are matched against
+func splitPath(rel string) []string {
+	return strings.Split(path.Clean(strings.ReplaceAll(rel, "\\", "/")), "/")
+}
+
+// matchAny reports whether any pattern matches the path or one of its parent directories
+func matchAny(patterns []pattern, segments []string) bool {
+	for _, p := range patterns {
+		for i := 1; i <= len(segments); i++ {
+			if matchSegments(p, segments[:i]) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func matchSegments(p pattern, segments []string) bool {
+	if len(p) == 0 {
+		return len(segments) == 0
+	}
+	if p[0] == "**" {
+		for i := 0; i <= len(segments); i++ {
+			if matchSegments(p[1:], segments[i:]) {
+				return true
+			}
+		}
+		return false
+	}
+	if len(segments) == 0 {
+		return false
+	}
+	ok, _ := path.Match(p[0], segments[0])
+	return ok && matchSegments(p[1:], segments[1:])
+}
@@ -0,0 +1,77 @@
+package filesync
+
+import "testing"
+
+func TestFilter_MatchPath(t *testing.T) {
+	tests := []struct {
+		name    string
+		include []string
+		exclude []string
+		path    string
+		want    bool
+	}{
+		{name: "no patterns", path: "node_modules/a/index.js", want: true},
+		{name: "bare name matches at any depth", include: []string{".env"}, path: "services/api/.env", want: true},
+		{name: "bare name matches at the root", include: []string{".env"}, path: ".env", want: true},
+		{name: "not included", include: []string{".env"}, path: ".env.local", want: false},
+		{name: "glob in bare name", include: []string{".env*"}, path: ".env.local", want: true},
+		{name: "anchored pattern", include: []string{"config/local.yml"}, path: "config/local.yml", want: true},
+		{name: "anchored pattern does not match deeper", include: []string{"config/local.yml"}, path: "app/config/local.yml", want: false},
+		{name: "double star", include: []string{"**/config/*.yml"}, path: "app/config/local.yml", want: true},
+		{name: "star stays within a segment", include: []string{"config/*"}, path: "config/dev/local.yml", want: true},
+		{name: "directory excludes its contents", exclude: []string{"node_modules"}, path: "web/node_modules/react/index.js", want: false},
+		{name: "trailing slash", exclude: []string{"target/"}, path: "target/debug/app", want: false},
+		{name: "exclude wins over include", include: []string{"**/*.yml"}, exclude: []string{"secrets.yml"}, path: "config/secrets.yml", want: false},
+		{name: "exclude does not match other files", exclude: []string{"node_modules"}, path: ".env", want: true},
+		{name: "leading dot slash is cleaned", include: []string{".env"}, path: "./.env", want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := NewFilter(tt.include, tt.exclude, 0)
+			if err != nil {
+				t.Fatalf("NewFilter() error = %v", err)
+			}
+			if got := f.MatchPath(tt.path); got != tt.want {
+				t.Errorf("MatchPath(%q) = %v, want %v", tt.path, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFilter_MatchSize(t *testing.T) {
+	f, err := NewFilter(nil, nil, 1024)
+	if err != nil {
+		t.Fatalf("NewFilter() error = %v", err)
+	}
+	if !f.MatchSize(1024) {
+		t.Error("MatchSize(1024) = false, want true at the limit")
+	}
+	if f.MatchSize(1025) {
+		t.Error("MatchSize(1025) = true, want false above the limit")
+	}
+
+	unlimited, err := NewFilter(nil, nil, 0)
+	if err != nil {
+		t.Fatalf("NewFilter() error = %v", err)
+	}
+	if !unlimited.MatchSize(1 << 40) {
+		t.Error("MatchSize() = false, want true without a limit")
+	}
+}
+
+func TestFilter_Nil(t *testing.T) {
+	var f *Filter
+	if !f.MatchPath("node_modules/a.js") || !f.MatchSize(1<<40) {
+		t.Error("nil Filter should allow every file")
+	}
+}
+
+func TestNewFilter_InvalidPattern(t *testing.T) {
+	if _, err := NewFilter([]string{"[a-"}, nil, 0); err == nil {
+		t.Error("NewFilter() error = nil, want error for a malformed pattern")
+	}
+	if _, err := NewFilter(nil, []string{"/"}, 0); err == nil {
+		t.Error("NewFilter() error = nil, want error for an empty pattern")
+	}
+}
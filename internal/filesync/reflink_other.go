@@ -0,0 +1,13 @@
+//go:build !linux
+
+package filesync
+
+import (
+	"errors"
+	"os"
+)
+
+// cloneFile is only supported on Linux; other platforms fall back to a full copy
+func cloneFile(dst, src *os.File) error {
+	return errors.New("reflink is not supported on this platform")
+}
@@ -0,0 +1,73 @@
+package filesync
+
+import "fmt"
+
+// Strategy is how a file is synced into the new worktree
+type Strategy string
+
+const (
+	// StrategyCopy copies the file contents
+	StrategyCopy Strategy = "copy"
+	// StrategyReflink clones the file with copy-on-write (btrfs, XFS)
+	StrategyReflink Strategy = "reflink"
+	// StrategyHardlink creates a hard link to the file, sharing its contents
+	StrategyHardlink Strategy = "hardlink"
+	// StrategySymlink creates a symbolic link to the file in the main worktree
+	StrategySymlink Strategy = "symlink"
+)
+
+// ParseStrategy parses a strategy name. An empty name is StrategyCopy.
+func ParseStrategy(s string) (Strategy, error) {
+	switch Strategy(s) {
+	case "", StrategyCopy:
+		return StrategyCopy, nil
+	case StrategyReflink, StrategyHardlink, StrategySymlink:
+		return Strategy(s), nil
+	default:
+		return "", fmt.Errorf("invalid strategy %q (use copy, reflink, hardlink or symlink)", s)
+	}
+}
+
+// Rule selects the strategy for files matching a glob pattern
+type Rule struct {
+	Pattern  string
+	Strategy Strategy
+}
+
+type compiledRule struct {
+	pattern  pattern
+	strategy Strategy
+}
+
+// SetStrategies sets the strategy used for files matching each rule, and the
+// strategy for files matching none of them. The first matching rule wins.
+// Rule patterns have the same syntax as the include and exclude patterns.
+func (f *Filter) SetStrategies(defaultStrategy Strategy, rules []Rule) error {
+	f.defaultStrategy = defaultStrategy
+	f.rules = nil
+	for _, r := range rules {
+		compiled, err := compilePattern(r.Pattern)
+		if err != nil {
+			return err
+		}
+		f.rules = append(f.rules, compiledRule{pattern: compiled, strategy: r.Strategy})
+	}
+	return nil
+}
+
+// Strategy returns the strategy for the file at the relative path rel
+func (f *Filter) Strategy(rel string) Strategy {
+	if f == nil {
+		return StrategyCopy
+	}
+	segments := splitPath(rel)
+	for _, r := range f.rules {
+		if matchAny([]pattern{r.pattern}, segments) {
+			return r.strategy
+		}
+	}
+	if f.defaultStrategy == "" {
+		return StrategyCopy
+	}
+	return f.defaultStrategy
+}
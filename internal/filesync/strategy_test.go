@@ -0,0 +1,67 @@
+package filesync
+
+import "testing"
+
+func TestParseStrategy(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    Strategy
+		wantErr bool
+	}{
+		{input: "", want: StrategyCopy},
+		{input: "copy", want: StrategyCopy},
+		{input: "reflink", want: StrategyReflink},
+		{input: "hardlink", want: StrategyHardlink},
+		{input: "symlink", want: StrategySymlink},
+		{input: "move", wantErr: true},
+		{input: "Copy", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseStrategy(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseStrategy(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseStrategy(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFilter_Strategy(t *testing.T) {
+	f, err := NewFilter(nil, nil, 0)
+	if err != nil {
+		t.Fatalf("NewFilter() error = %v", err)
+	}
+	err = f.SetStrategies(StrategyReflink, []Rule{
+		{Pattern: "node_modules/.cache", Strategy: StrategyCopy},
+		{Pattern: "node_modules", Strategy: StrategyHardlink},
+		{Pattern: "**/*.sqlite", Strategy: StrategySymlink},
+	})
+	if err != nil {
+		t.Fatalf("SetStrategies() error = %v", err)
+	}
+
+	tests := []struct {
+		path string
+		want Strategy
+	}{
+		{path: "node_modules/react/index.js", want: StrategyHardlink},
+		{path: "web/node_modules/react/index.js", want: StrategyHardlink},
+		{path: "node_modules/.cache/babel/x.json", want: StrategyCopy},
+		{path: "data/dev.sqlite", want: StrategySymlink},
+		{path: ".env", want: StrategyReflink},
+	}
+	for _, tt := range tests {
+		if got := f.Strategy(tt.path); got != tt.want {
+			t.Errorf("Strategy(%q) = %q, want %q", tt.path, got, tt.want)
+		}
+	}
+
+	var nilFilter *Filter
+	if got := nilFilter.Strategy(".env"); got != StrategyCopy {
+		t.Errorf("nil Filter Strategy() = %q, want %q", got, StrategyCopy)
+	}
+}
@@ -0,0 +1,146 @@
+package filesync
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+)
+
+// Job is a file to sync into the new worktree
+type Job struct {
+	// Path is the path relative to the worktree root
+	Path     string
+	Src      string
+	Dst      string
+	Size     int64
+	Strategy Strategy
+}
+
+// Failure is a file that couldn't be synced
+type Failure struct {
+	Path string
+	Err  error
+}
+
+// Progress is the state of a running sync
+type Progress struct {
+	Files      int
+	TotalFiles int
+	Bytes      int64
+	TotalBytes int64
+}
+
+// Result summarizes a finished sync
+type Result struct {
+	Files int
+	Bytes int64
+	// Fallbacks is the number of files copied because their strategy isn't supported
+	Fallbacks int
+	// Failed is sorted by path
+	Failed []Failure
+}
+
+// Run syncs the files of jobs with at most workers files copied at a time.
+// onProgress is called after each file, unless it is nil; calls don't overlap.
+func Run(jobs []Job, workers int, onProgress func(Progress)) Result {
+	progress := Progress{TotalFiles: len(jobs)}
+	for _, j := range jobs {
+		progress.TotalBytes += j.Size
+	}
+
+	var result Result
+	var mu sync.Mutex
+	indexes := make(chan int)
+
+	var wg sync.WaitGroup
+	for range max(1, min(workers, len(jobs))) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				job := jobs[i]
+				used, err := Copy(job.Src, job.Dst, job.Strategy)
+
+				mu.Lock()
+				progress.Files++
+				progress.Bytes += job.Size
+				if err != nil {
+					result.Failed = append(result.Failed, Failure{Path: job.Path, Err: err})
+				} else {
+					result.Files++
+					result.Bytes += job.Size
+					if used != job.Strategy {
+						result.Fallbacks++
+					}
+				}
+				if onProgress != nil {
+					onProgress(progress)
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+	for i := range jobs {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+
+	sort.Slice(result.Failed, func(i, j int) bool {
+		return result.Failed[i].Path < result.Failed[j].Path
+	})
+	return result
+}
+
+// Plan is the files selected for syncing and those left out
+type Plan struct {
+	Jobs []Job
+	// Excluded is the number of files left out by the include and exclude patterns
+	Excluded int
+	// TooLarge lists the files larger than the size limit
+	TooLarge []Job
+	// Failed lists the files that couldn't be inspected
+	Failed []Failure
+}
+
+// NewPlan selects the files to sync from srcRoot to dstRoot among the relative
+// paths in files. Files that no longer exist and directories are left out.
+func NewPlan(srcRoot, dstRoot string, files []string, filter *Filter) Plan {
+	var plan Plan
+	for _, rel := range files {
+		// Filter before stat'ing, so excluded trees like node_modules cost nothing
+		if !filter.MatchPath(rel) {
+			plan.Excluded++
+			continue
+		}
+
+		job := Job{
+			Path:     rel,
+			Src:      filepath.Join(srcRoot, rel),
+			Dst:      filepath.Join(dstRoot, rel),
+			Strategy: filter.Strategy(rel),
+		}
+		// Symlinks are synced as links, even when they point to a directory
+		info, err := os.Lstat(job.Src)
+		if os.IsNotExist(err) {
+			// Deleted files have nothing to copy
+			continue
+		}
+		if err != nil {
+			plan.Failed = append(plan.Failed, Failure{Path: rel, Err: err})
+			continue
+		}
+		if info.IsDir() {
+			continue
+		}
+
+		job.Size = info.Size()
+		if !filter.MatchSize(job.Size) {
+			plan.TooLarge = append(plan.TooLarge, job)
+			continue
+		}
+		plan.Jobs = append(plan.Jobs, job)
+	}
+	return plan
+}
@@ -0,0 +1,97 @@
+package filesync
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestNewPlan(t *testing.T) {
+	src := t.TempDir()
+	dst := t.TempDir()
+	writeFile(t, filepath.Join(src, ".env"), "E")
+	writeFile(t, filepath.Join(src, "big.bin"), "0123456789")
+	writeFile(t, filepath.Join(src, "node_modules", "x", "index.js"), "x")
+	if err := os.Mkdir(filepath.Join(src, "dir"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	filter, err := NewFilter(nil, []string{"node_modules"}, 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	files := []string{".env", "big.bin", "node_modules/x/index.js", "dir", "deleted.txt"}
+	plan := NewPlan(src, dst, files, filter)
+
+	if len(plan.Jobs) != 1 || plan.Jobs[0].Path != ".env" {
+		t.Fatalf("NewPlan() Jobs = %+v, want only .env", plan.Jobs)
+	}
+	job := plan.Jobs[0]
+	if job.Src != filepath.Join(src, ".env") || job.Dst != filepath.Join(dst, ".env") || job.Size != 1 || job.Strategy != StrategyCopy {
+		t.Errorf("NewPlan() Jobs[0] = %+v", job)
+	}
+	if plan.Excluded != 1 {
+		t.Errorf("NewPlan() Excluded = %d, want 1", plan.Excluded)
+	}
+	if len(plan.TooLarge) != 1 || plan.TooLarge[0].Path != "big.bin" || plan.TooLarge[0].Size != 10 {
+		t.Errorf("NewPlan() TooLarge = %+v, want big.bin", plan.TooLarge)
+	}
+	if len(plan.Failed) != 0 {
+		t.Errorf("NewPlan() Failed = %+v, want none", plan.Failed)
+	}
+}
+
+func TestRun(t *testing.T) {
+	src := t.TempDir()
+	dst := t.TempDir()
+
+	var jobs []Job
+	for _, name := range []string{"a", "b/c", "d/e/f", "g", "h"} {
+		writeFile(t, filepath.Join(src, name), name)
+		jobs = append(jobs, Job{Path: name, Src: filepath.Join(src, name), Dst: filepath.Join(dst, name), Size: int64(len(name)), Strategy: StrategyCopy})
+	}
+	jobs = append(jobs, Job{Path: "missing", Src: filepath.Join(src, "missing"), Dst: filepath.Join(dst, "missing"), Strategy: StrategyCopy})
+
+	var calls []Progress
+	result := Run(jobs, 3, func(p Progress) {
+		calls = append(calls, p)
+	})
+
+	if result.Files != 5 || result.Bytes != 11 {
+		t.Errorf("Run() Files = %d, Bytes = %d, want 5 and 11", result.Files, result.Bytes)
+	}
+	if len(result.Failed) != 1 || result.Failed[0].Path != "missing" {
+		t.Errorf("Run() Failed = %+v, want missing", result.Failed)
+	}
+	for _, name := range []string{"a", "b/c", "d/e/f", "g", "h"} {
+		data, err := os.ReadFile(filepath.Join(dst, name))
+		if err != nil || string(data) != name {
+			t.Errorf("%s = %q, %v, want %q", name, data, err, name)
+		}
+	}
+
+	if len(calls) != len(jobs) {
+		t.Fatalf("progress called %d times, want %d", len(calls), len(jobs))
+	}
+	last := calls[len(calls)-1]
+	if last.Files != 6 || last.TotalFiles != 6 || last.Bytes != 11 || last.TotalBytes != 11 {
+		t.Errorf("last progress = %+v, want all files and bytes done", last)
+	}
+}
+
+func TestRun_NoJobs(t *testing.T) {
+	result := Run(nil, 4, nil)
+	if result.Files != 0 || len(result.Failed) != 0 {
+		t.Errorf("Run(nil) = %+v, want empty result", result)
+	}
+}
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -21,7 +21,10 @@ type Selector interface {
 	SelectWorktree(worktrees []*git.Worktree, excludeMain bool) (*git.Worktree, error)
 
 	// SelectWorktrees shows worktree selector with multi-select support
-	SelectWorktrees(worktrees []*git.Worktree, excludeMain bool, multi bool) ([]*git.Worktree, error)
+	SelectWorktrees(worktrees []*git.Worktree, excludeMain bool, multi bool, excludeLocked bool) ([]*git.Worktree, error)
+
+	// SetCurrent marks the worktree at path as the current worktree in the worktree selectors
+	SetCurrent(path string)
 
 	// IsAvailable checks if fzf is installed
 	IsAvailable() bool
@@ -34,6 +37,8 @@ type fzfExecutor func(args []string, input string) (string, error)
 type FzfSelector struct {
 	executor    shell.Executor
 	fzfExecutor fzfExecutor
+	// currentPath is the path of the worktree marked as "(current)" (empty for none)
+	currentPath string
 }
 
 // NewSelector creates a new FzfSelector
@@ -43,6 +48,11 @@ func NewSelector(executor shell.Executor) Selector {
 	return s
 }
 
+// SetCurrent marks the worktree at path as the current worktree in the worktree selectors
+func (s *FzfSelector) SetCurrent(path string) {
+	s.currentPath = path
+}
+
 // IsAvailable checks if fzf is installed
 func (s *FzfSelector) IsAvailable() bool {
 	_, err := s.executor.LookPath("fzf")
@@ -74,7 +84,7 @@ func (s *FzfSelector) SelectBranch(branches []string) (string, error) {
 
 // SelectWorktree shows an interactive worktree selector using fzf
 func (s *FzfSelector) SelectWorktree(worktrees []*git.Worktree, excludeMain bool) (*git.Worktree, error) {
-	selected, err := s.SelectWorktrees(worktrees, excludeMain, false)
+	selected, err := s.SelectWorktrees(worktrees, excludeMain, false, false)
 	if err != nil {
 		return nil, err
 	}
@@ -85,7 +95,8 @@ func (s *FzfSelector) SelectWorktree(worktrees []*git.Worktree, excludeMain bool
 }
 
 // SelectWorktrees shows an interactive worktree selector with multi-select support
-func (s *FzfSelector) SelectWorktrees(worktrees []*git.Worktree, excludeMain bool, multi bool) ([]*git.Worktree, error) {
+// excludeLocked: if true, locked worktrees are left out so they can't be picked by accident
+func (s *FzfSelector) SelectWorktrees(worktrees []*git.Worktree, excludeMain bool, multi bool, excludeLocked bool) ([]*git.Worktree, error) {
 	if !s.IsAvailable() {
 		return nil, errors.NewFzfNotInstalledError(nil)
 	}
@@ -95,19 +106,30 @@ func (s *FzfSelector) SelectWorktrees(worktrees []*git.Worktree, excludeMain boo
 	}
 
 	// Build list for fzf
-	var items []string
-	wtMap := make(map[string]*git.Worktree)
+	var candidates []*git.Worktree
 	for _, wt := range worktrees {
-		if excludeMain && wt.IsMain {
+		if excludeMain && (wt.IsMain || wt.IsBare) {
+			continue
+		}
+		if excludeLocked && wt.Locked {
 			continue
 		}
-		dirName := filepath.Base(wt.Path)
-		label := dirName
-		if wt.IsMain {
-			label = dirName + " (main)"
+		candidates = append(candidates, wt)
+	}
+
+	var items []string
+	wtMap := make(map[string]*git.Worktree)
+	for i, name := range worktreeNames(candidates) {
+		label := name
+		markers := candidates[i].Markers()
+		if s.currentPath != "" && candidates[i].Path == s.currentPath {
+			markers = append(markers, "(current)")
+		}
+		if len(markers) > 0 {
+			label += " " + strings.Join(markers, " ")
 		}
 		items = append(items, label)
-		wtMap[label] = wt
+		wtMap[label] = candidates[i]
 	}
 
 	if len(items) == 0 {
@@ -148,6 +170,47 @@ func (s *FzfSelector) SelectWorktrees(worktrees []*git.Worktree, excludeMain boo
 	return selected, nil
 }
 
+// worktreeNames returns the name each worktree is listed under: its directory
+// name, or, when several worktrees share a directory name (e.g. feature/x and
+// bugfix/x under a {{.Branch}} path template), its path relative to the common
+// parent of all worktrees so that every name is unique
+func worktreeNames(worktrees []*git.Worktree) []string {
+	count := make(map[string]int)
+	for _, wt := range worktrees {
+		count[filepath.Base(wt.Path)]++
+	}
+
+	var parent string
+	for _, wt := range worktrees {
+		if parent == "" {
+			parent = filepath.Dir(wt.Path)
+			continue
+		}
+		for !isWithin(wt.Path, parent) && filepath.Dir(parent) != parent {
+			parent = filepath.Dir(parent)
+		}
+	}
+
+	names := make([]string, len(worktrees))
+	for i, wt := range worktrees {
+		names[i] = filepath.Base(wt.Path)
+		if count[names[i]] > 1 {
+			if rel, err := filepath.Rel(parent, wt.Path); err == nil {
+				names[i] = rel
+			} else {
+				names[i] = wt.Path
+			}
+		}
+	}
+	return names
+}
+
+// isWithin reports whether path is inside dir
+func isWithin(path, dir string) bool {
+	rel, err := filepath.Rel(dir, path)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
 // defaultFzfExecutor is the default implementation that uses exec.Command
 func (s *FzfSelector) defaultFzfExecutor(args []string, input string) (string, error) {
 	return executeFzf(args, input)
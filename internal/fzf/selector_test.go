@@ -299,7 +299,7 @@ func TestFzfSelector_SelectWorktrees_ExcludeMain(t *testing.T) {
 	}
 
 	// When excluding main and only main exists, should return error
-	_, err := selector.SelectWorktrees(worktrees, true, false)
+	_, err := selector.SelectWorktrees(worktrees, true, false, false)
 	if err == nil {
 		t.Error("SelectWorktrees() expected error when all worktrees are excluded")
 	}
@@ -308,6 +308,87 @@ func TestFzfSelector_SelectWorktrees_ExcludeMain(t *testing.T) {
 	}
 }
 
+// TestFzfSelector_SelectWorktrees_ExcludeLocked tests locked worktree exclusion
+func TestFzfSelector_SelectWorktrees_ExcludeLocked(t *testing.T) {
+	worktrees := []*git.Worktree{
+		{Path: "/repo-feature", Branch: "feature"},
+		{Path: "/repo-hotfix", Branch: "hotfix", Locked: true},
+	}
+
+	var input string
+	selector := newTestSelector(func(args []string, in string) (string, error) {
+		input = in
+		return "", nil
+	})
+
+	if _, err := selector.SelectWorktrees(worktrees, true, true, true); err != nil {
+		t.Fatalf("SelectWorktrees() unexpected error: %v", err)
+	}
+	if strings.Contains(input, "repo-hotfix") {
+		t.Errorf("Locked worktree should be excluded from input, got %q", input)
+	}
+
+	if _, err := selector.SelectWorktrees(worktrees, true, true, false); err != nil {
+		t.Fatalf("SelectWorktrees() unexpected error: %v", err)
+	}
+	if !strings.Contains(input, "repo-hotfix (locked)") {
+		t.Errorf("Locked worktree should be offered when not excluded, got %q", input)
+	}
+}
+
+// TestFzfSelector_SelectWorktrees_SameDirectoryName tests worktrees that share a directory name
+func TestFzfSelector_SelectWorktrees_SameDirectoryName(t *testing.T) {
+	worktrees := []*git.Worktree{
+		{Path: "/repo", Branch: "main", IsMain: true},
+		{Path: "/repo/.worktrees/feature/x", Branch: "feature/x"},
+		{Path: "/repo/.worktrees/bugfix/x", Branch: "bugfix/x"},
+		{Path: "/repo/.worktrees/hotfix", Branch: "hotfix"},
+	}
+
+	var input string
+	selector := newTestSelector(func(args []string, in string) (string, error) {
+		input = in
+		return "feature/x\nbugfix/x", nil
+	})
+
+	got, err := selector.SelectWorktrees(worktrees, true, true, false)
+	if err != nil {
+		t.Fatalf("SelectWorktrees() unexpected error: %v", err)
+	}
+	if want := "feature/x\nbugfix/x\nhotfix"; input != want {
+		t.Errorf("SelectWorktrees() input = %q, want %q", input, want)
+	}
+	if len(got) != 2 || got[0] != worktrees[1] || got[1] != worktrees[2] {
+		t.Errorf("SelectWorktrees() = %v, want the feature/x and bugfix/x worktrees", got)
+	}
+}
+
+// TestFzfSelector_SelectWorktrees_Current tests the current worktree marker
+func TestFzfSelector_SelectWorktrees_Current(t *testing.T) {
+	worktrees := []*git.Worktree{
+		{Path: "/repo-feature", Branch: "feature"},
+		{Path: "/repo", Branch: "main", IsMain: true},
+	}
+
+	var input string
+	selector := newTestSelector(func(args []string, in string) (string, error) {
+		input = in
+		return "repo (main) (current)", nil
+	})
+	selector.SetCurrent("/repo")
+
+	got, err := selector.SelectWorktrees(worktrees, false, false, false)
+	if err != nil {
+		t.Fatalf("SelectWorktrees() unexpected error: %v", err)
+	}
+	if want := "repo-feature\nrepo (main) (current)"; input != want {
+		t.Errorf("SelectWorktrees() input = %q, want %q", input, want)
+	}
+	if len(got) != 1 || got[0] != worktrees[1] {
+		t.Errorf("SelectWorktrees() = %v, want the current worktree", got)
+	}
+}
+
 // TestFzfSelector_SelectWorktrees tests multi worktree selection
 func TestFzfSelector_SelectWorktrees(t *testing.T) {
 	tests := []struct {
@@ -379,6 +460,27 @@ func TestFzfSelector_SelectWorktrees(t *testing.T) {
 			wantBranches: []string{"feature"},
 			wantErr:      false,
 		},
+		{
+			name: "state markers are shown in labels",
+			selector: newTestSelector(func(args []string, input string) (string, error) {
+				if !strings.Contains(input, "repo-hotfix (locked)") {
+					t.Errorf("Expected locked marker in input, got %q", input)
+				}
+				if !strings.Contains(input, "repo-gone (prunable)") {
+					t.Errorf("Expected prunable marker in input, got %q", input)
+				}
+				return "repo-gone (prunable)", nil
+			}),
+			worktrees: []*git.Worktree{
+				&git.Worktree{Path: "/repo-hotfix", Branch: "hotfix", Locked: true},
+				&git.Worktree{Path: "/repo-gone", Branch: "gone", Prunable: true},
+			},
+			excludeMain:  false,
+			multi:        false,
+			wantCount:    1,
+			wantBranches: []string{"gone"},
+			wantErr:      false,
+		},
 		{
 			name: "user cancelled returns nil",
 			selector: newTestSelector(func(args []string, input string) (string, error) {
@@ -396,7 +498,7 @@ func TestFzfSelector_SelectWorktrees(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got, err := tt.selector.SelectWorktrees(tt.worktrees, tt.excludeMain, tt.multi)
+			got, err := tt.selector.SelectWorktrees(tt.worktrees, tt.excludeMain, tt.multi, false)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("SelectWorktrees() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -509,7 +611,7 @@ func TestExecuteFzf_Integration(t *testing.T) {
 type MockSelector struct {
 	SelectBranchFunc    func(branches []string) (string, error)
 	SelectWorktreeFunc  func(worktrees []*git.Worktree, excludeMain bool) (*git.Worktree, error)
-	SelectWorktreesFunc func(worktrees []*git.Worktree, excludeMain bool, multi bool) ([]*git.Worktree, error)
+	SelectWorktreesFunc func(worktrees []*git.Worktree, excludeMain bool, multi bool, excludeLocked bool) ([]*git.Worktree, error)
 	IsAvailableFunc     func() bool
 }
 
@@ -535,9 +637,9 @@ func (m *MockSelector) SelectWorktree(worktrees []*git.Worktree, excludeMain boo
 	return nil, nil
 }
 
-func (m *MockSelector) SelectWorktrees(worktrees []*git.Worktree, excludeMain bool, multi bool) ([]*git.Worktree, error) {
+func (m *MockSelector) SelectWorktrees(worktrees []*git.Worktree, excludeMain bool, multi bool, excludeLocked bool) ([]*git.Worktree, error) {
 	if m.SelectWorktreesFunc != nil {
-		return m.SelectWorktreesFunc(worktrees, excludeMain, multi)
+		return m.SelectWorktreesFunc(worktrees, excludeMain, multi, excludeLocked)
 	}
 	var selected []*git.Worktree
 	for _, wt := range worktrees {
@@ -551,6 +653,8 @@ func (m *MockSelector) SelectWorktrees(worktrees []*git.Worktree, excludeMain bo
 	return selected, nil
 }
 
+func (m *MockSelector) SetCurrent(path string) {}
+
 func (m *MockSelector) IsAvailable() bool {
 	if m.IsAvailableFunc != nil {
 		return m.IsAvailableFunc()
@@ -591,7 +695,7 @@ func TestMockSelector(t *testing.T) {
 		}
 
 		// Test SelectWorktrees with exclusion
-		wts, err := mock.SelectWorktrees(worktrees, true, false)
+		wts, err := mock.SelectWorktrees(worktrees, true, false, false)
 		if err != nil {
 			t.Errorf("MockSelector.SelectWorktrees() unexpected error: %v", err)
 		}
@@ -640,7 +744,7 @@ func TestMockSelector(t *testing.T) {
 		mock := &MockSelector{}
 
 		// Test multi-select returns all
-		wts, err := mock.SelectWorktrees(worktrees, false, true)
+		wts, err := mock.SelectWorktrees(worktrees, false, true, false)
 		if err != nil {
 			t.Errorf("MockSelector.SelectWorktrees() unexpected error: %v", err)
 		}
@@ -649,7 +753,7 @@ func TestMockSelector(t *testing.T) {
 		}
 
 		// Test single-select returns one
-		wts, err = mock.SelectWorktrees(worktrees, false, false)
+		wts, err = mock.SelectWorktrees(worktrees, false, false, false)
 		if err != nil {
 			t.Errorf("MockSelector.SelectWorktrees() unexpected error: %v", err)
 		}
@@ -709,7 +813,7 @@ func TestFzfSelector_EdgeCases(t *testing.T) {
 			&git.Worktree{Path: "/repo/日本語", Branch: "feature", IsMain: false},
 		}
 
-		got, err := selector.SelectWorktrees(worktrees, false, false)
+		got, err := selector.SelectWorktrees(worktrees, false, false, false)
 		if err != nil {
 			t.Errorf("SelectWorktrees() unexpected error: %v", err)
 		}
@@ -723,7 +827,7 @@ func TestFzfSelector_EdgeCases(t *testing.T) {
 
 		// Test both empty list and list with only main
 		emptyWorktrees := []*git.Worktree{}
-		_, err := selector.SelectWorktrees(emptyWorktrees, true, false)
+		_, err := selector.SelectWorktrees(emptyWorktrees, true, false, false)
 		if err == nil {
 			t.Error("Expected error for empty worktree list")
 		}
@@ -731,7 +835,7 @@ func TestFzfSelector_EdgeCases(t *testing.T) {
 		onlyMain := []*git.Worktree{
 			&git.Worktree{Path: "/repo", Branch: "main", IsMain: true},
 		}
-		_, err = selector.SelectWorktrees(onlyMain, true, false)
+		_, err = selector.SelectWorktrees(onlyMain, true, false, false)
 		if err == nil {
 			t.Error("Expected error when all worktrees are excluded")
 		}
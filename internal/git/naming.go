@@ -1,39 +1,253 @@
 package git
 
 import (
+	"bytes"
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"os"
 	"path/filepath"
+	"slices"
 	"strings"
+	"text/template"
+	"unicode"
+
+	"github.com/t98o84/gw/internal/errors"
 )
 
+// MaxSuffixLength is the maximum length (in characters) of a directory suffix
+const MaxSuffixLength = 64
+
+// suffixHashLength is the length of the hash appended to truncated suffixes
+const suffixHashLength = 8
+
 // BranchToSuffix converts a branch name to a directory suffix
 // e.g., "feature/hoge" -> "feature-hoge"
+//
+// Characters other than letters, digits, '.', '_', '-', '+' and '@' are replaced
+// with '-', leading and trailing dots and dashes are removed, and suffixes longer
+// than MaxSuffixLength are truncated with a stable hash of the branch name appended.
 func BranchToSuffix(branch string) string {
-	// Replace / with -
-	suffix := strings.ReplaceAll(branch, "/", "-")
-	// Replace any other problematic characters
-	suffix = strings.ReplaceAll(suffix, "\\", "-")
-	suffix = strings.ReplaceAll(suffix, ":", "-")
-	return suffix
+	var b strings.Builder
+	for _, r := range branch {
+		if isSafeSuffixRune(r) {
+			b.WriteRune(r)
+		} else {
+			b.WriteRune('-')
+		}
+	}
+	suffix := strings.Trim(b.String(), ".-")
+
+	runes := []rune(suffix)
+	if len(runes) <= MaxSuffixLength {
+		return suffix
+	}
+	head := strings.TrimRight(string(runes[:MaxSuffixLength-suffixHashLength-1]), ".-")
+	return head + "-" + shortHash(branch)
+}
+
+// isSafeSuffixRune reports whether r can be used as is in a directory name
+func isSafeSuffixRune(r rune) bool {
+	switch r {
+	case '.', '_', '-', '+', '@':
+		return true
+	}
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// shortHash returns a short, stable hash of s
+func shortHash(s string) string {
+	sum := sha1.Sum([]byte(s))
+	return hex.EncodeToString(sum[:])[:suffixHashLength]
+}
+
+// AlternativeWorktreePath returns a path next to path that is unique to branch.
+// It is suggested when the regular path is already used by another branch.
+func AlternativeWorktreePath(path, branch string) string {
+	return filepath.Clean(path) + "-" + shortHash(branch)
+}
+
+// PathTemplateData is the data available to worktree path templates
+type PathTemplateData struct {
+	// Repo is the repository name
+	Repo string
+	// RepoRoot is the root directory of the main worktree.
+	// In a bare repository layout it is the primary worktree, or the bare
+	// repository's location if there is no worktree yet.
+	RepoRoot string
+	// RepoParent is the parent directory of RepoRoot
+	RepoParent string
+	// Branch is the branch name as is (slashes create nested directories)
+	Branch string
+	// Suffix is the branch name converted with BranchToSuffix
+	Suffix string
+	// Owner is the owner (user or organization) taken from the default remote's URL
+	Owner string
+}
+
+// SetPathTemplate sets the template used to generate worktree paths.
+// An empty template restores the default sibling layout.
+func (m *Manager) SetPathTemplate(tmpl string) {
+	m.pathTemplate = tmpl
+}
+
+// SetPathTemplate is a package-level wrapper for backward compatibility
+func SetPathTemplate(tmpl string) {
+	defaultManager.SetPathTemplate(tmpl)
 }
 
 // WorktreePath generates the worktree directory path
 // e.g., for repo "ex-repo" and branch "feature/hoge" -> "../ex-repo-feature-hoge"
+// When a path template is set, it is rendered with PathTemplateData instead.
+//
+// In a bare repository layout, worktrees are placed next to the bare repository
+// ("../repo.git" -> "../repo-feature-hoge"), or inside the container directory
+// when the bare repository is a ".bare" directory ("repo/.bare" -> "repo/feature-hoge").
 func (m *Manager) WorktreePath(repoName, branch string) (string, error) {
+	if m.pathTemplate != "" {
+		return m.renderPathTemplate(repoName, branch)
+	}
+	return m.defaultWorktreePath(repoName, branch)
+}
+
+// defaultWorktreePath returns the worktree path used when no path template is set
+func (m *Manager) defaultWorktreePath(repoName, branch string) (string, error) {
+	suffix := BranchToSuffix(branch)
+	if m.IsBareRepository() {
+		commonDir, err := m.GetCommonDir()
+		if err != nil {
+			return "", err
+		}
+		if isContainerLayout(commonDir) {
+			return filepath.Join(filepath.Dir(commonDir), suffix), nil
+		}
+		return filepath.Join(filepath.Dir(commonDir), repoName+"-"+suffix), nil
+	}
+
 	repoRoot, err := m.GetRepoRoot()
 	if err != nil {
 		return "", err
 	}
 
-	suffix := BranchToSuffix(branch)
 	dirName := repoName + "-" + suffix
 	return filepath.Join(filepath.Dir(repoRoot), dirName), nil
 }
 
+// isContainerLayout reports whether a bare repository lives in a ".bare" directory
+// inside the directory that holds its worktrees
+func isContainerLayout(commonDir string) bool {
+	return filepath.Base(commonDir) == ".bare"
+}
+
+// templateRepoRoot returns the RepoRoot used by path templates
+func (m *Manager) templateRepoRoot() (string, error) {
+	repoRoot, err := m.GetMainWorktreePath()
+	if err != nil {
+		if !m.IsBareRepository() {
+			return "", err
+		}
+		// No worktree to stand in for the main worktree yet; use the bare repository's location
+		commonDir, cdErr := m.GetCommonDir()
+		if cdErr != nil {
+			return "", cdErr
+		}
+		if isContainerLayout(commonDir) {
+			return filepath.Dir(commonDir), nil
+		}
+		return commonDir, nil
+	}
+	return filepath.Abs(repoRoot)
+}
+
+// renderPathTemplate renders the configured path template for the given branch
+func (m *Manager) renderPathTemplate(repoName, branch string) (string, error) {
+	tmpl, err := template.New("worktree.path").Option("missingkey=error").Parse(m.pathTemplate)
+	if err != nil {
+		return "", errors.NewInvalidInputError(m.pathTemplate, "invalid worktree path template", err)
+	}
+
+	repoRoot, err := m.templateRepoRoot()
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve repository root: %w", err)
+	}
+
+	data := PathTemplateData{
+		Repo:       repoName,
+		RepoRoot:   repoRoot,
+		RepoParent: filepath.Dir(repoRoot),
+		Branch:     branch,
+		Suffix:     BranchToSuffix(branch),
+	}
+	if strings.Contains(m.pathTemplate, ".Owner") {
+		url, err := m.GetRemoteURL(m.DefaultRemote())
+		if err != nil {
+			return "", err
+		}
+		data.Owner = RemoteOwner(url)
+		if data.Owner == "" {
+			return "", errors.NewInvalidInputError(url, "failed to determine owner from remote URL for worktree path template", nil)
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", errors.NewInvalidInputError(m.pathTemplate, "failed to render worktree path template", err)
+	}
+
+	path := expandHome(strings.TrimSpace(buf.String()))
+	if path == "" {
+		return "", errors.NewInvalidInputError(m.pathTemplate, "worktree path template rendered an empty path", nil)
+	}
+	if !filepath.IsAbs(path) {
+		// Relative paths are relative to the repository root
+		path = filepath.Join(repoRoot, path)
+	}
+	return filepath.Clean(path), nil
+}
+
+// expandHome replaces a leading ~ with the user's home directory
+func expandHome(path string) string {
+	if path != "~" && !strings.HasPrefix(path, "~/") {
+		return path
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return path
+	}
+	return filepath.Join(home, strings.TrimPrefix(path, "~"))
+}
+
 // WorktreePath is a package-level wrapper for backward compatibility
 func WorktreePath(repoName, branch string) (string, error) {
 	return defaultManager.WorktreePath(repoName, branch)
 }
 
+// ExpectedWorktreePaths returns the locations where a worktree for branch is
+// expected: the configured path template, if any, followed by the default layout.
+func (m *Manager) ExpectedWorktreePaths(repoName, branch string) ([]string, error) {
+	defaultPath, err := m.defaultWorktreePath(repoName, branch)
+	if err != nil {
+		return nil, err
+	}
+	if m.pathTemplate == "" {
+		return []string{defaultPath}, nil
+	}
+
+	templatePath, err := m.renderPathTemplate(repoName, branch)
+	if err != nil {
+		return nil, err
+	}
+	if templatePath == defaultPath {
+		return []string{defaultPath}, nil
+	}
+	return []string{templatePath, defaultPath}, nil
+}
+
+// ExpectedWorktreePaths is a package-level wrapper for backward compatibility
+func ExpectedWorktreePaths(repoName, branch string) ([]string, error) {
+	return defaultManager.ExpectedWorktreePaths(repoName, branch)
+}
+
 // WorktreeDirName generates just the directory name for a worktree
 func WorktreeDirName(repoName, branch string) string {
 	suffix := BranchToSuffix(branch)
@@ -90,21 +304,66 @@ func (m *Manager) FindWorktree(identifier string) (*Worktree, error) {
 		}
 	}
 
+	// An exact branch match wins over directory names, which may collide
+	for _, wt := range worktrees {
+		if wt.Branch == identifier {
+			return &wt, nil
+		}
+	}
+
 	// Normalize the identifier
 	targetDirName := ParseWorktreeIdentifier(identifier, repoName)
 
+	// Directory names are only unique under the default layout: a template such as
+	// {{.RepoRoot}}/.worktrees/{{.Branch}} puts feature/x and bugfix/x both in "x"
+	var matches []*Worktree
+	for i := range worktrees {
+		dirName := filepath.Base(worktrees[i].Path)
+		// Match by directory name, or by suffix (without repo name prefix)
+		if dirName == targetDirName || dirName == identifier {
+			matches = append(matches, &worktrees[i])
+		}
+	}
+	if len(matches) == 1 {
+		return matches[0], nil
+	}
+	if len(matches) > 1 {
+		paths := make([]string, len(matches))
+		for i, wt := range matches {
+			paths[i] = wt.Path
+		}
+		return nil, errors.NewInvalidInputError(identifier,
+			fmt.Sprintf("matches more than one worktree (%s); use the branch name or path", strings.Join(paths, ", ")), nil)
+	}
+
+	// Directory names don't follow <repo>-<suffix> under custom layouts,
+	// so also match by the branch suffix and by the path the layout would produce
+	suffix := strings.TrimPrefix(identifier, repoName+"-")
+	var expectedPath string
+	if m.pathTemplate != "" {
+		// Best effort: an identifier that is not a valid branch name may not render
+		if path, err := m.WorktreePath(repoName, identifier); err == nil {
+			expectedPath = path
+		}
+	}
 	for _, wt := range worktrees {
-		dirName := filepath.Base(wt.Path)
-		// Match by directory name
-		if dirName == targetDirName {
+		if wt.Branch != "" && (BranchToSuffix(wt.Branch) == identifier || BranchToSuffix(wt.Branch) == suffix) {
 			return &wt, nil
 		}
-		// Also try matching by branch name directly
-		if wt.Branch == identifier {
+		if expectedPath != "" && filepath.Clean(wt.Path) == expectedPath {
+			return &wt, nil
+		}
+	}
+
+	// Detached worktrees have no branch, so match them by a tag or commit hash at their HEAD
+	for _, wt := range worktrees {
+		if !wt.IsDetached || wt.Commit == "" {
+			continue
+		}
+		if isCommitPrefix(identifier, wt.Commit) {
 			return &wt, nil
 		}
-		// Match by suffix (without repo name prefix)
-		if dirName == identifier {
+		if tags, err := m.TagsAt(wt.Commit); err == nil && slices.Contains(tags, identifier) {
 			return &wt, nil
 		}
 	}
@@ -112,6 +371,19 @@ func (m *Manager) FindWorktree(identifier string) (*Worktree, error) {
 	return nil, nil
 }
 
+// isCommitPrefix reports whether s is an abbreviation of the commit hash
+func isCommitPrefix(s, commit string) bool {
+	if len(s) < 4 || len(s) > len(commit) {
+		return false
+	}
+	for _, r := range s {
+		if !unicode.Is(unicode.ASCII_Hex_Digit, r) {
+			return false
+		}
+	}
+	return strings.HasPrefix(commit, strings.ToLower(s))
+}
+
 // FindWorktree is a package-level wrapper for backward compatibility
 func FindWorktree(identifier string) (*Worktree, error) {
 	return defaultManager.FindWorktree(identifier)
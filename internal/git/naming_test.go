@@ -2,8 +2,13 @@ package git
 
 import (
 	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
 	"testing"
 
+	"github.com/t98o84/gw/internal/errors"
 	"github.com/t98o84/gw/internal/shell"
 )
 
@@ -48,6 +53,31 @@ func TestBranchToSuffix(t *testing.T) {
 			branch:   "",
 			expected: "",
 		},
+		{
+			name:     "branch with shell and filesystem special chars",
+			branch:   "fix/a*b?c d<e>f|g\"h",
+			expected: "fix-a-b-c-d-e-f-g-h",
+		},
+		{
+			name:     "branch with leading dot",
+			branch:   ".hidden/branch",
+			expected: "hidden-branch",
+		},
+		{
+			name:     "branch with unicode letters",
+			branch:   "feature/日本語",
+			expected: "feature-日本語",
+		},
+		{
+			name:     "branch with allowed punctuation",
+			branch:   "release/v1.2.3_rc+1@x",
+			expected: "release-v1.2.3_rc+1@x",
+		},
+		{
+			name:     "long branch is truncated with a hash",
+			branch:   "dependabot/npm_and_yarn/frontend/packages/some-very-long-package-name-4.17.21",
+			expected: "dependabot-npm_and_yarn-frontend-packages-some-very-lon" + "-" + shortHash("dependabot/npm_and_yarn/frontend/packages/some-very-long-package-name-4.17.21"),
+		},
 	}
 
 	for _, tt := range tests {
@@ -60,6 +90,31 @@ func TestBranchToSuffix(t *testing.T) {
 	}
 }
 
+func TestBranchToSuffix_Length(t *testing.T) {
+	a := BranchToSuffix("feature/" + strings.Repeat("a", 100))
+	b := BranchToSuffix("feature/" + strings.Repeat("a", 99) + "b")
+	if n := len([]rune(a)); n > MaxSuffixLength {
+		t.Errorf("BranchToSuffix() length = %d, want <= %d", n, MaxSuffixLength)
+	}
+	if a == b {
+		t.Errorf("BranchToSuffix() returned %q for two different long branches", a)
+	}
+	if a != BranchToSuffix("feature/"+strings.Repeat("a", 100)) {
+		t.Error("BranchToSuffix() is not stable")
+	}
+}
+
+func TestAlternativeWorktreePath(t *testing.T) {
+	a := AlternativeWorktreePath("/path/to/repo-feature-a-b", "feature/a-b")
+	b := AlternativeWorktreePath("/path/to/repo-feature-a-b", "feature-a/b")
+	if a == b {
+		t.Errorf("AlternativeWorktreePath() returned %q for two different branches", a)
+	}
+	if !strings.HasPrefix(a, "/path/to/repo-feature-a-b-") {
+		t.Errorf("AlternativeWorktreePath() = %q, want it next to the original path", a)
+	}
+}
+
 func TestWorktreeDirName(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -214,6 +269,286 @@ func TestManager_WorktreePath(t *testing.T) {
 	}
 }
 
+func TestManager_WorktreePath_Bare(t *testing.T) {
+	tests := []struct {
+		name      string
+		commonDir string
+		want      string
+	}{
+		{name: "next to repo.git", commonDir: "/home/user/repos/ex-repo.git", want: "/home/user/repos/ex-repo-feature-hoge"},
+		{name: "inside container with .bare", commonDir: "/home/user/repos/ex-repo/.bare", want: "/home/user/repos/ex-repo/feature-hoge"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := NewManager(&shell.MockExecutor{
+				ExecuteFunc: func(name string, args ...string) ([]byte, error) {
+					if args[0] == "config" && args[1] == "--bool" {
+						return []byte("true\n"), nil
+					}
+					if args[0] == "rev-parse" && args[1] == "--git-common-dir" {
+						return []byte(tt.commonDir + "\n"), nil
+					}
+					return nil, fmt.Errorf("unexpected command")
+				},
+			})
+			got, err := m.WorktreePath("ex-repo", "feature/hoge")
+			if err != nil {
+				t.Fatalf("Manager.WorktreePath() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Manager.WorktreePath() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestManager_WorktreePath_Template(t *testing.T) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		t.Skip("home directory is not available")
+	}
+
+	mock := &shell.MockExecutor{
+		ExecuteFunc: func(name string, args ...string) ([]byte, error) {
+			if name == "git" && args[0] == "rev-parse" && args[1] == "--git-common-dir" {
+				return []byte("/home/user/repos/ex-repo/.git\n"), nil
+			}
+			if name == "git" && args[0] == "remote" && args[1] == "get-url" {
+				return []byte("git@github.com:t98o84/ex-repo.git\n"), nil
+			}
+			return nil, fmt.Errorf("unexpected command")
+		},
+	}
+
+	tests := []struct {
+		name     string
+		template string
+		branch   string
+		want     string
+		wantErr  bool
+	}{
+		{
+			name:     "worktrees directory next to the repository",
+			template: "{{.RepoParent}}/{{.Repo}}-worktrees/{{.Suffix}}",
+			branch:   "feature/hoge",
+			want:     "/home/user/repos/ex-repo-worktrees/feature-hoge",
+		},
+		{
+			name:     "nested inside the repository by branch",
+			template: "{{.RepoRoot}}/.worktrees/{{.Branch}}",
+			branch:   "feature/hoge",
+			want:     "/home/user/repos/ex-repo/.worktrees/feature/hoge",
+		},
+		{
+			name:     "home directory with owner",
+			template: "~/wt/{{.Owner}}/{{.Repo}}/{{.Suffix}}",
+			branch:   "feature/hoge",
+			want:     filepath.Join(home, "wt/t98o84/ex-repo/feature-hoge"),
+		},
+		{
+			name:     "relative to the repository root",
+			template: "../wt/{{.Suffix}}",
+			branch:   "main",
+			want:     "/home/user/repos/wt/main",
+		},
+		{
+			name:     "syntax error",
+			template: "{{.Repo",
+			branch:   "main",
+			wantErr:  true,
+		},
+		{
+			name:     "unknown field",
+			template: "{{.Unknown}}/{{.Suffix}}",
+			branch:   "main",
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := NewManager(mock)
+			m.SetPathTemplate(tt.template)
+			got, err := m.WorktreePath("ex-repo", tt.branch)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Manager.WorktreePath() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("Manager.WorktreePath() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestManager_ExpectedWorktreePaths(t *testing.T) {
+	mock := &shell.MockExecutor{
+		ExecuteFunc: func(name string, args ...string) ([]byte, error) {
+			if name == "git" && args[0] == "rev-parse" && args[1] == "--git-common-dir" {
+				return []byte("/home/user/repos/ex-repo/.git\n"), nil
+			}
+			if name == "git" && args[0] == "rev-parse" && args[1] == "--show-toplevel" {
+				return []byte("/home/user/repos/ex-repo\n"), nil
+			}
+			return nil, fmt.Errorf("unexpected command")
+		},
+	}
+
+	tests := []struct {
+		name     string
+		template string
+		want     []string
+	}{
+		{
+			name: "default layout only",
+			want: []string{"/home/user/repos/ex-repo-feature-hoge"},
+		},
+		{
+			name:     "template first",
+			template: "{{.RepoParent}}/{{.Repo}}-worktrees/{{.Suffix}}",
+			want:     []string{"/home/user/repos/ex-repo-worktrees/feature-hoge", "/home/user/repos/ex-repo-feature-hoge"},
+		},
+		{
+			name:     "template matching the default layout",
+			template: "{{.RepoParent}}/{{.Repo}}-{{.Suffix}}",
+			want:     []string{"/home/user/repos/ex-repo-feature-hoge"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := NewManager(mock)
+			m.SetPathTemplate(tt.template)
+			got, err := m.ExpectedWorktreePaths("ex-repo", "feature/hoge")
+			if err != nil {
+				t.Fatalf("Manager.ExpectedWorktreePaths() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Manager.ExpectedWorktreePaths() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestManager_FindWorktree_Template(t *testing.T) {
+	mock := &shell.MockExecutor{
+		ExecuteFunc: func(name string, args ...string) ([]byte, error) {
+			if name == "git" && args[0] == "rev-parse" && args[1] == "--show-toplevel" {
+				return []byte("/path/to/myrepo\n"), nil
+			}
+			if name == "git" && args[0] == "rev-parse" && args[1] == "--git-common-dir" {
+				return []byte("/path/to/myrepo/.git\n"), nil
+			}
+			if name == "git" && args[0] == "worktree" {
+				return []byte("worktree /path/to/myrepo\nHEAD abc123\nbranch refs/heads/main\n\nworktree /path/to/myrepo/.worktrees/feature/test\nHEAD def456\nbranch refs/heads/feature/test\n\n"), nil
+			}
+			return nil, fmt.Errorf("unexpected command")
+		},
+	}
+	want := "/path/to/myrepo/.worktrees/feature/test"
+
+	for _, identifier := range []string{"feature/test", "feature-test", "myrepo-feature-test", want} {
+		t.Run(identifier, func(t *testing.T) {
+			m := NewManager(mock)
+			m.SetPathTemplate("{{.RepoRoot}}/.worktrees/{{.Branch}}")
+			got, err := m.FindWorktree(identifier)
+			if err != nil {
+				t.Fatalf("Manager.FindWorktree() error = %v", err)
+			}
+			if got == nil || got.Path != want {
+				t.Errorf("Manager.FindWorktree() = %v, want path %v", got, want)
+			}
+		})
+	}
+}
+
+func TestManager_FindWorktree_Ambiguous(t *testing.T) {
+	mock := &shell.MockExecutor{
+		ExecuteFunc: func(name string, args ...string) ([]byte, error) {
+			if name == "git" && args[0] == "rev-parse" && args[1] == "--show-toplevel" {
+				return []byte("/path/to/myrepo\n"), nil
+			}
+			if name == "git" && args[0] == "rev-parse" && args[1] == "--git-common-dir" {
+				return []byte("/path/to/myrepo/.git\n"), nil
+			}
+			if name == "git" && args[0] == "worktree" {
+				return []byte("worktree /path/to/myrepo\nHEAD abc123\nbranch refs/heads/main\n\n" +
+					"worktree /path/to/myrepo/.worktrees/feature/x\nHEAD def456\nbranch refs/heads/feature/x\n\n" +
+					"worktree /path/to/myrepo/.worktrees/bugfix/x\nHEAD 789abc\nbranch refs/heads/bugfix/x\n\n"), nil
+			}
+			return nil, fmt.Errorf("unexpected command")
+		},
+	}
+	m := NewManager(mock)
+	m.SetPathTemplate("{{.RepoRoot}}/.worktrees/{{.Branch}}")
+
+	got, err := m.FindWorktree("x")
+	if !errors.IsInvalidInputError(err) {
+		t.Errorf("Manager.FindWorktree() = %v, %v, want an InvalidInputError for an ambiguous name", got, err)
+	}
+
+	// The branch name still picks exactly one of them
+	got, err = m.FindWorktree("bugfix/x")
+	if err != nil {
+		t.Fatalf("Manager.FindWorktree() error = %v", err)
+	}
+	if got == nil || got.Path != "/path/to/myrepo/.worktrees/bugfix/x" {
+		t.Errorf("Manager.FindWorktree() = %v, want the bugfix/x worktree", got)
+	}
+}
+
+func TestManager_FindWorktree_Detached(t *testing.T) {
+	mock := &shell.MockExecutor{
+		ExecuteFunc: func(name string, args ...string) ([]byte, error) {
+			if name == "git" && args[0] == "rev-parse" && args[1] == "--show-toplevel" {
+				return []byte("/path/to/myrepo\n"), nil
+			}
+			if name == "git" && args[0] == "worktree" {
+				return []byte("worktree /path/to/myrepo\nHEAD abc123\nbranch refs/heads/main\n\nworktree /path/to/myrepo-v1.2.0\nHEAD 1a2b3c4d5e6f\ndetached\n\n"), nil
+			}
+			if name == "git" && args[0] == "tag" && args[1] == "--points-at" && args[2] == "1a2b3c4d5e6f" {
+				return []byte("v1.2.0\nrelease-2024\n"), nil
+			}
+			return nil, fmt.Errorf("unexpected command")
+		},
+	}
+	want := "/path/to/myrepo-v1.2.0"
+
+	tests := []struct {
+		identifier string
+		found      bool
+	}{
+		{identifier: "v1.2.0", found: true},
+		{identifier: "myrepo-v1.2.0", found: true},
+		{identifier: "release-2024", found: true},
+		{identifier: "1a2b3c4", found: true},
+		{identifier: "1A2B3C4D", found: true},
+		{identifier: "1a2", found: false},
+		{identifier: "fedcba9", found: false},
+		{identifier: "v2.0.0", found: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.identifier, func(t *testing.T) {
+			m := NewManager(mock)
+			got, err := m.FindWorktree(tt.identifier)
+			if err != nil {
+				t.Fatalf("Manager.FindWorktree() error = %v", err)
+			}
+			if !tt.found {
+				if got != nil {
+					t.Errorf("Manager.FindWorktree() = %v, want nil", got)
+				}
+				return
+			}
+			if got == nil || got.Path != want || !got.IsDetached {
+				t.Errorf("Manager.FindWorktree() = %v, want detached worktree at %v", got, want)
+			}
+		})
+	}
+}
+
 func TestManager_FindWorktree(t *testing.T) {
 	tests := []struct {
 		name         string
@@ -273,6 +608,23 @@ func TestManager_FindWorktree(t *testing.T) {
 			wantWorktree: &Worktree{Path: "/path/to/myrepo-feature-test", Branch: "feature/test", Commit: "def456", IsMain: false},
 			wantErr:      false,
 		},
+		{
+			name:       "exact branch match wins over colliding directory name",
+			identifier: "feature-a/b",
+			mock: &shell.MockExecutor{
+				ExecuteFunc: func(name string, args ...string) ([]byte, error) {
+					if name == "git" && args[0] == "rev-parse" && args[1] == "--show-toplevel" {
+						return []byte("/path/to/myrepo\n"), nil
+					}
+					if name == "git" && args[0] == "worktree" {
+						return []byte("worktree /path/to/myrepo\nHEAD abc123\nbranch refs/heads/main\n\nworktree /path/to/myrepo-feature-a-b\nHEAD def456\nbranch refs/heads/feature/a-b\n\nworktree /path/to/other\nHEAD 789abc\nbranch refs/heads/feature-a/b\n\n"), nil
+					}
+					return nil, fmt.Errorf("unexpected command")
+				},
+			},
+			wantWorktree: &Worktree{Path: "/path/to/other", Branch: "feature-a/b", Commit: "789abc", IsMain: false},
+			wantErr:      false,
+		},
 		{
 			name:       "worktree not found",
 			identifier: "nonexistent",
@@ -0,0 +1,106 @@
+package git
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/t98o84/gw/internal/errors"
+)
+
+// DiffPaths lists the files with uncommitted changes in the worktree at path:
+// the staged changes with cached, otherwise the unstaged changes.
+// Renamed files are listed with both their old and new path.
+func (m *Manager) DiffPaths(path string, cached bool) ([]string, error) {
+	args := []string{"-C", path, "diff", "--name-only", "--no-renames", "--no-relative", "-z"}
+	if cached {
+		args = append(args, "--cached")
+	}
+	out, err := m.executor.Execute("git", args...)
+	if err != nil {
+		return nil, errors.NewCommandExecutionError("git", args, out, err)
+	}
+	return splitNul(out), nil
+}
+
+// DiffPaths is a package-level wrapper for backward compatibility
+func DiffPaths(path string, cached bool) ([]string, error) {
+	return defaultManager.DiffPaths(path, cached)
+}
+
+// WritePatch writes a patch of the uncommitted changes in the worktree at path
+// to file: the staged changes with cached, otherwise the unstaged changes.
+// The patch includes binary files, renames and symlinks. If paths is not empty,
+// only the changes to these files are included.
+func (m *Manager) WritePatch(path, file string, cached bool, paths []string) error {
+	args := []string{
+		"--literal-pathspecs", "-C", path, "diff",
+		"--binary", "-M", "--no-color", "--no-ext-diff", "--no-textconv", "--no-relative",
+		// git apply expects the default prefixes, whatever diff.noprefix says
+		"--src-prefix=a/", "--dst-prefix=b/",
+		"--output=" + file,
+	}
+	if cached {
+		args = append(args, "--cached")
+	}
+	if len(paths) > 0 {
+		args = append(args, "--")
+		args = append(args, paths...)
+	}
+	out, err := m.executor.Execute("git", args...)
+	if err != nil {
+		return errors.NewCommandExecutionError("git", args, out, err)
+	}
+	return nil
+}
+
+// WritePatch is a package-level wrapper for backward compatibility
+func WritePatch(path, file string, cached bool, paths []string) error {
+	return defaultManager.WritePatch(path, file, cached, paths)
+}
+
+// ApplyPatch applies the patch in file to the worktree at path.
+// With index, the changes are also staged.
+// Nothing is changed if the patch doesn't apply cleanly.
+func (m *Manager) ApplyPatch(path, file string, index bool) error {
+	args := []string{"-C", path, "apply"}
+	if index {
+		args = append(args, "--index")
+	}
+	args = append(args, file)
+	out, err := m.executor.Execute("git", args...)
+	if err != nil {
+		return errors.NewCommandExecutionError("git", args, out, err)
+	}
+	return nil
+}
+
+// ApplyPatch is a package-level wrapper for backward compatibility
+func ApplyPatch(path, file string, index bool) error {
+	return defaultManager.ApplyPatch(path, file, index)
+}
+
+// GetUntrackedFiles returns the untracked files in the worktree at path that are not ignored
+func (m *Manager) GetUntrackedFiles(path string) ([]string, error) {
+	args := []string{"-C", path, "ls-files", "--others", "--exclude-standard", "-z"}
+	out, err := m.executor.Execute("git", args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list untracked files (-C %s): %w", path, err)
+	}
+	return splitNul(out), nil
+}
+
+// GetUntrackedFiles is a package-level wrapper for backward compatibility
+func GetUntrackedFiles(path string) ([]string, error) {
+	return defaultManager.GetUntrackedFiles(path)
+}
+
+// splitNul splits NUL-terminated output into its non-empty fields
+func splitNul(out []byte) []string {
+	var fields []string
+	for _, p := range bytes.Split(out, []byte{0}) {
+		if len(p) > 0 {
+			fields = append(fields, string(p))
+		}
+	}
+	return fields
+}
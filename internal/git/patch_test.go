@@ -0,0 +1,129 @@
+package git
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/t98o84/gw/internal/shell"
+)
+
+func TestManager_DiffPaths(t *testing.T) {
+	tests := []struct {
+		name     string
+		cached   bool
+		wantArgs string
+	}{
+		{name: "unstaged", cached: false, wantArgs: "-C /wt diff --name-only --no-renames --no-relative -z"},
+		{name: "staged", cached: true, wantArgs: "-C /wt diff --name-only --no-renames --no-relative -z --cached"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := NewManager(&shell.MockExecutor{
+				ExecuteFunc: func(name string, args ...string) ([]byte, error) {
+					if got := strings.Join(args, " "); got != tt.wantArgs {
+						return nil, fmt.Errorf("unexpected command: git %s", got)
+					}
+					return []byte("a.txt\x00dir/with space.txt\x00"), nil
+				},
+			})
+
+			got, err := m.DiffPaths("/wt", tt.cached)
+			if err != nil {
+				t.Fatalf("Manager.DiffPaths() error = %v", err)
+			}
+			if want := []string{"a.txt", "dir/with space.txt"}; !reflect.DeepEqual(got, want) {
+				t.Errorf("Manager.DiffPaths() = %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestManager_WritePatch(t *testing.T) {
+	tests := []struct {
+		name       string
+		cached     bool
+		paths      []string
+		wantSuffix string
+	}{
+		{name: "unstaged changes", wantSuffix: "--output=/tmp/p.patch"},
+		{name: "staged changes", cached: true, wantSuffix: "--output=/tmp/p.patch --cached"},
+		{name: "limited to paths", paths: []string{"a.txt", "*.go"}, wantSuffix: "--output=/tmp/p.patch -- a.txt *.go"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			m := NewManager(&shell.MockExecutor{
+				ExecuteFunc: func(name string, args ...string) ([]byte, error) {
+					got = args
+					return nil, nil
+				},
+			})
+
+			if err := m.WritePatch("/wt", "/tmp/p.patch", tt.cached, tt.paths); err != nil {
+				t.Fatalf("Manager.WritePatch() error = %v", err)
+			}
+			joined := strings.Join(got, " ")
+			if !strings.HasPrefix(joined, "--literal-pathspecs -C /wt diff --binary -M ") {
+				t.Errorf("Manager.WritePatch() ran git %s", joined)
+			}
+			if !strings.HasSuffix(joined, tt.wantSuffix) {
+				t.Errorf("Manager.WritePatch() ran git %s, want it to end with %q", joined, tt.wantSuffix)
+			}
+		})
+	}
+}
+
+func TestManager_ApplyPatch(t *testing.T) {
+	tests := []struct {
+		name     string
+		index    bool
+		err      error
+		wantArgs string
+		wantErr  bool
+	}{
+		{name: "working tree only", wantArgs: "-C /wt apply /tmp/p.patch"},
+		{name: "index", index: true, wantArgs: "-C /wt apply --index /tmp/p.patch"},
+		{name: "patch does not apply", err: fmt.Errorf("exit status 1"), wantArgs: "-C /wt apply /tmp/p.patch", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := NewManager(&shell.MockExecutor{
+				ExecuteFunc: func(name string, args ...string) ([]byte, error) {
+					if got := strings.Join(args, " "); got != tt.wantArgs {
+						return nil, fmt.Errorf("unexpected command: git %s", got)
+					}
+					return nil, tt.err
+				},
+			})
+
+			err := m.ApplyPatch("/wt", "/tmp/p.patch", tt.index)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Manager.ApplyPatch() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestManager_GetUntrackedFiles(t *testing.T) {
+	m := NewManager(&shell.MockExecutor{
+		ExecuteFunc: func(name string, args ...string) ([]byte, error) {
+			if strings.Join(args, " ") != "-C /wt ls-files --others --exclude-standard -z" {
+				return nil, fmt.Errorf("unexpected command")
+			}
+			return []byte("new.txt\x00dir/new.go\x00"), nil
+		},
+	})
+
+	got, err := m.GetUntrackedFiles("/wt")
+	if err != nil {
+		t.Fatalf("Manager.GetUntrackedFiles() error = %v", err)
+	}
+	if want := []string{"new.txt", "dir/new.go"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Manager.GetUntrackedFiles() = %v, want %v", got, want)
+	}
+}
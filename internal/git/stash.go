@@ -0,0 +1,102 @@
+package git
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/t98o84/gw/internal/errors"
+)
+
+// StashPush saves the uncommitted changes of the worktree at path, including
+// untracked files, in a new stash entry and cleans the worktree.
+// It returns the commit of the stash entry, or "" if git had nothing to save,
+// e.g. when the only changes are untracked files inside a submodule.
+// The stash is shared by all worktrees.
+func (m *Manager) StashPush(path, message string) (string, error) {
+	before, err := m.stashHead(path)
+	if err != nil {
+		return "", err
+	}
+
+	args := []string{"-C", path, "stash", "push", "--include-untracked", "-m", message}
+	out, err := m.executor.Execute("git", args...)
+	if err != nil {
+		return "", errors.NewCommandExecutionError("git", args, out, err)
+	}
+
+	// refs/stash only moves if a new entry was created; otherwise it is an
+	// older, unrelated entry of the user
+	after, err := m.stashHead(path)
+	if err != nil {
+		return "", err
+	}
+	if after == before {
+		return "", nil
+	}
+	return after, nil
+}
+
+// stashHead returns the commit of the latest stash entry, or "" if the stash is empty
+func (m *Manager) stashHead(path string) (string, error) {
+	args := []string{"-C", path, "rev-parse", "--verify", "--quiet", "refs/stash"}
+	out, err := m.executor.Execute("git", args...)
+	if err != nil {
+		// rev-parse --verify --quiet exits with 1 when the stash is empty
+		type exitCoder interface {
+			ExitCode() int
+		}
+		if exitErr, ok := err.(exitCoder); ok && exitErr.ExitCode() == 1 {
+			return "", nil
+		}
+		return "", errors.NewCommandExecutionError("git", args, out, err)
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// StashPush is a package-level wrapper for backward compatibility
+func StashPush(path, message string) (string, error) {
+	return defaultManager.StashPush(path, message)
+}
+
+// StashApply applies the stash entry with the given commit to the worktree at path.
+// Staged changes are restored to the index. The entry is kept in the stash.
+func (m *Manager) StashApply(path, commit string) error {
+	args := []string{"-C", path, "stash", "apply", "--index", commit}
+	out, err := m.executor.Execute("git", args...)
+	if err != nil {
+		return errors.NewCommandExecutionError("git", args, out, err)
+	}
+	return nil
+}
+
+// StashApply is a package-level wrapper for backward compatibility
+func StashApply(path, commit string) error {
+	return defaultManager.StashApply(path, commit)
+}
+
+// StashDrop removes the stash entry with the given commit
+func (m *Manager) StashDrop(path, commit string) error {
+	args := []string{"-C", path, "stash", "list", "--format=%H"}
+	out, err := m.executor.Execute("git", args...)
+	if err != nil {
+		return errors.NewCommandExecutionError("git", args, out, err)
+	}
+
+	for i, line := range strings.Split(strings.TrimSpace(string(out)), "\n") {
+		if strings.TrimSpace(line) != commit {
+			continue
+		}
+		args = []string{"-C", path, "stash", "drop", "--quiet", fmt.Sprintf("stash@{%d}", i)}
+		out, err = m.executor.Execute("git", args...)
+		if err != nil {
+			return errors.NewCommandExecutionError("git", args, out, err)
+		}
+		return nil
+	}
+	return fmt.Errorf("stash entry %s not found", commit)
+}
+
+// StashDrop is a package-level wrapper for backward compatibility
+func StashDrop(path, commit string) error {
+	return defaultManager.StashDrop(path, commit)
+}
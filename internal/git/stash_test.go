@@ -0,0 +1,114 @@
+package git
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/t98o84/gw/internal/shell"
+)
+
+func TestManager_StashPush(t *testing.T) {
+	tests := []struct {
+		name   string
+		before string // refs/stash before the push, "" for an empty stash
+		after  string // refs/stash after the push
+		want   string
+	}{
+		{name: "empty stash", before: "", after: "1a2b3c4d5e6f", want: "1a2b3c4d5e6f"},
+		{name: "existing entries", before: "0f0f0f0f0f0f", after: "1a2b3c4d5e6f", want: "1a2b3c4d5e6f"},
+		// git stash push succeeds without creating an entry when there is nothing to save
+		{name: "nothing to save", before: "0f0f0f0f0f0f", after: "0f0f0f0f0f0f", want: ""},
+		{name: "nothing to save on an empty stash", before: "", after: "", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pushed := false
+			var commands []string
+			m := NewManager(&shell.MockExecutor{
+				ExecuteFunc: func(name string, args ...string) ([]byte, error) {
+					cmd := strings.Join(args, " ")
+					commands = append(commands, cmd)
+					switch cmd {
+					case "-C /src stash push --include-untracked -m gw carry to /dst":
+						pushed = true
+						return []byte("Saved working directory and index state On main: gw carry to /dst\n"), nil
+					case "-C /src rev-parse --verify --quiet refs/stash":
+						ref := tt.before
+						if pushed {
+							ref = tt.after
+						}
+						if ref == "" {
+							return nil, &testExitError{exitCode: 1}
+						}
+						return []byte(ref + "\n"), nil
+					}
+					return nil, fmt.Errorf("unexpected command: git %s", cmd)
+				},
+			})
+
+			got, err := m.StashPush("/src", "gw carry to /dst")
+			if err != nil {
+				t.Fatalf("Manager.StashPush() error = %v (ran %v)", err, commands)
+			}
+			if got != tt.want {
+				t.Errorf("Manager.StashPush() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestManager_StashApply(t *testing.T) {
+	m := NewManager(&shell.MockExecutor{
+		ExecuteFunc: func(name string, args ...string) ([]byte, error) {
+			if strings.Join(args, " ") != "-C /dst stash apply --index 1a2b3c" {
+				return nil, fmt.Errorf("unexpected command")
+			}
+			return nil, nil
+		},
+	})
+
+	if err := m.StashApply("/dst", "1a2b3c"); err != nil {
+		t.Errorf("Manager.StashApply() error = %v", err)
+	}
+}
+
+func TestManager_StashDrop(t *testing.T) {
+	tests := []struct {
+		name     string
+		commit   string
+		wantDrop string
+		wantErr  bool
+	}{
+		{name: "latest entry", commit: "aaa", wantDrop: "stash@{0}"},
+		{name: "older entry", commit: "ccc", wantDrop: "stash@{2}"},
+		{name: "missing entry", commit: "ddd", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var dropped string
+			m := NewManager(&shell.MockExecutor{
+				ExecuteFunc: func(name string, args ...string) ([]byte, error) {
+					switch {
+					case strings.Join(args, " ") == "-C /src stash list --format=%H":
+						return []byte("aaa\nbbb\nccc\n"), nil
+					case len(args) == 6 && args[2] == "stash" && args[3] == "drop":
+						dropped = args[5]
+						return nil, nil
+					}
+					return nil, fmt.Errorf("unexpected command")
+				},
+			})
+
+			err := m.StashDrop("/src", tt.commit)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Manager.StashDrop() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if dropped != tt.wantDrop {
+				t.Errorf("Manager.StashDrop() dropped %q, want %q", dropped, tt.wantDrop)
+			}
+		})
+	}
+}
@@ -0,0 +1,139 @@
+package git
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/t98o84/gw/internal/errors"
+)
+
+// WorktreeStatus holds the working tree and upstream state of a worktree
+type WorktreeStatus struct {
+	// Staged is the number of files with changes in the index
+	Staged int
+	// Modified is the number of files with changes that are not staged
+	Modified int
+	// Untracked is the number of untracked files
+	Untracked int
+	// Conflicts is the number of files with unresolved merge conflicts
+	Conflicts int
+	// Upstream is the upstream branch (empty if the branch has none)
+	Upstream string
+	// UpstreamGone is true when the upstream branch no longer exists
+	UpstreamGone bool
+	// Ahead and Behind count the commits relative to the upstream branch
+	Ahead  int
+	Behind int
+}
+
+// IsClean reports whether the worktree has no changes
+func (s *WorktreeStatus) IsClean() bool {
+	return s.Staged == 0 && s.Modified == 0 && s.Untracked == 0 && s.Conflicts == 0
+}
+
+// Status returns the status of the worktree at the given path
+func (m *Manager) Status(path string) (*WorktreeStatus, error) {
+	args := []string{"-C", path, "status", "--porcelain=v2", "--branch"}
+	out, err := m.executor.Execute("git", args...)
+	if err != nil {
+		return nil, errors.NewCommandExecutionError("git", args, out, err)
+	}
+	return parseStatus(out), nil
+}
+
+// Status is a package-level wrapper for backward compatibility
+func Status(path string) (*WorktreeStatus, error) {
+	return defaultManager.Status(path)
+}
+
+// parseStatus parses the output of git status --porcelain=v2 --branch
+func parseStatus(out []byte) *WorktreeStatus {
+	status := &WorktreeStatus{}
+	hasAheadBehind := false
+	for _, line := range strings.Split(string(out), "\n") {
+		switch {
+		case strings.HasPrefix(line, "# branch.upstream "):
+			status.Upstream = strings.TrimPrefix(line, "# branch.upstream ")
+		case strings.HasPrefix(line, "# branch.ab "):
+			// # branch.ab +<ahead> -<behind>
+			fields := strings.Fields(strings.TrimPrefix(line, "# branch.ab "))
+			if len(fields) == 2 {
+				status.Ahead, _ = strconv.Atoi(strings.TrimPrefix(fields[0], "+"))
+				status.Behind, _ = strconv.Atoi(strings.TrimPrefix(fields[1], "-"))
+				hasAheadBehind = true
+			}
+		case strings.HasPrefix(line, "1 "), strings.HasPrefix(line, "2 "):
+			// <type> <XY> ...: X is the index state and Y the working tree state
+			if len(line) < 4 {
+				continue
+			}
+			if line[2] != '.' {
+				status.Staged++
+			}
+			if line[3] != '.' {
+				status.Modified++
+			}
+		case strings.HasPrefix(line, "u "):
+			status.Conflicts++
+		case strings.HasPrefix(line, "? "):
+			status.Untracked++
+		}
+	}
+	// git omits branch.ab when the upstream branch can't be resolved
+	status.UpstreamGone = status.Upstream != "" && !hasAheadBehind
+	return status
+}
+
+// AheadBehind returns the number of commits HEAD of the worktree at path is ahead of and behind base
+func (m *Manager) AheadBehind(path, base string) (ahead int, behind int, err error) {
+	args := []string{"-C", path, "rev-list", "--left-right", "--count", base + "...HEAD"}
+	out, err := m.executor.Execute("git", args...)
+	if err != nil {
+		return 0, 0, errors.NewCommandExecutionError("git", args, out, err)
+	}
+
+	// <behind>\t<ahead>: the left side is base, the right side HEAD
+	fields := strings.Fields(string(out))
+	if len(fields) != 2 {
+		return 0, 0, fmt.Errorf("unexpected rev-list output: %q", strings.TrimSpace(string(out)))
+	}
+	if behind, err = strconv.Atoi(fields[0]); err != nil {
+		return 0, 0, fmt.Errorf("unexpected rev-list output: %q", strings.TrimSpace(string(out)))
+	}
+	if ahead, err = strconv.Atoi(fields[1]); err != nil {
+		return 0, 0, fmt.Errorf("unexpected rev-list output: %q", strings.TrimSpace(string(out)))
+	}
+	return ahead, behind, nil
+}
+
+// AheadBehind is a package-level wrapper for backward compatibility
+func AheadBehind(path, base string) (int, int, error) {
+	return defaultManager.AheadBehind(path, base)
+}
+
+// LastCommit returns the committer date and subject of HEAD in the worktree at path
+func (m *Manager) LastCommit(path string) (time.Time, string, error) {
+	args := []string{"-C", path, "log", "-1", "--format=%ct%x00%s"}
+	out, err := m.executor.Execute("git", args...)
+	if err != nil {
+		return time.Time{}, "", errors.NewCommandExecutionError("git", args, out, err)
+	}
+
+	timestamp, subject, found := bytes.Cut(bytes.TrimRight(out, "\n"), []byte{0})
+	if !found {
+		return time.Time{}, "", fmt.Errorf("unexpected log output: %q", strings.TrimSpace(string(out)))
+	}
+	seconds, err := strconv.ParseInt(string(timestamp), 10, 64)
+	if err != nil {
+		return time.Time{}, "", fmt.Errorf("unexpected log output: %q", strings.TrimSpace(string(out)))
+	}
+	return time.Unix(seconds, 0), string(subject), nil
+}
+
+// LastCommit is a package-level wrapper for backward compatibility
+func LastCommit(path string) (time.Time, string, error) {
+	return defaultManager.LastCommit(path)
+}
@@ -0,0 +1,142 @@
+package git
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/t98o84/gw/internal/shell"
+)
+
+func TestManager_Status(t *testing.T) {
+	tests := []struct {
+		name    string
+		output  string
+		err     error
+		want    WorktreeStatus
+		wantErr bool
+	}{
+		{
+			name:   "clean without upstream",
+			output: "# branch.oid abc123\n# branch.head feature/a\n",
+			want:   WorktreeStatus{},
+		},
+		{
+			name:   "clean and in sync with upstream",
+			output: "# branch.oid abc123\n# branch.head main\n# branch.upstream origin/main\n# branch.ab +0 -0\n",
+			want:   WorktreeStatus{Upstream: "origin/main"},
+		},
+		{
+			name: "changes ahead and behind upstream",
+			output: "# branch.oid abc123\n# branch.head main\n# branch.upstream origin/main\n# branch.ab +2 -3\n" +
+				"1 M. N... 100644 100644 100644 aaa bbb staged.go\n" +
+				"1 .M N... 100644 100644 100644 aaa bbb modified.go\n" +
+				"1 MM N... 100644 100644 100644 aaa bbb both.go\n" +
+				"2 R. N... 100644 100644 100644 aaa bbb R100 new.go\told.go\n" +
+				"u UU N... 100644 100644 100644 100644 aaa bbb ccc conflict.go\n" +
+				"? new.txt\n" +
+				"? tmp/\n",
+			want: WorktreeStatus{Staged: 3, Modified: 2, Untracked: 2, Conflicts: 1, Upstream: "origin/main", Ahead: 2, Behind: 3},
+		},
+		{
+			name:   "upstream gone",
+			output: "# branch.oid abc123\n# branch.head feature/a\n# branch.upstream origin/feature/a\n",
+			want:   WorktreeStatus{Upstream: "origin/feature/a", UpstreamGone: true},
+		},
+		{
+			name:    "status fails",
+			err:     fmt.Errorf("git error"),
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := NewManager(&shell.MockExecutor{
+				ExecuteFunc: func(name string, args ...string) ([]byte, error) {
+					if args[0] == "-C" && args[1] == "/path/to/wt" && args[2] == "status" && args[3] == "--porcelain=v2" {
+						return []byte(tt.output), tt.err
+					}
+					return nil, fmt.Errorf("unexpected command")
+				},
+			})
+
+			got, err := m.Status("/path/to/wt")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Manager.Status() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if *got != tt.want {
+				t.Errorf("Manager.Status() = %+v, want %+v", *got, tt.want)
+			}
+			if got.IsClean() != (tt.want.Staged+tt.want.Modified+tt.want.Untracked+tt.want.Conflicts == 0) {
+				t.Errorf("WorktreeStatus.IsClean() = %v", got.IsClean())
+			}
+		})
+	}
+}
+
+func TestManager_AheadBehind(t *testing.T) {
+	tests := []struct {
+		name       string
+		output     string
+		err        error
+		wantAhead  int
+		wantBehind int
+		wantErr    bool
+	}{
+		{name: "ahead and behind", output: "4\t1\n", wantAhead: 1, wantBehind: 4},
+		{name: "in sync", output: "0\t0\n"},
+		{name: "unexpected output", output: "fatal: bad revision\n", wantErr: true},
+		{name: "rev-list fails", err: fmt.Errorf("git error"), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := NewManager(&shell.MockExecutor{
+				ExecuteFunc: func(name string, args ...string) ([]byte, error) {
+					if args[0] == "-C" && args[1] == "/path/to/wt" && args[2] == "rev-list" && args[5] == "origin/main...HEAD" {
+						return []byte(tt.output), tt.err
+					}
+					return nil, fmt.Errorf("unexpected command")
+				},
+			})
+
+			ahead, behind, err := m.AheadBehind("/path/to/wt", "origin/main")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Manager.AheadBehind() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if ahead != tt.wantAhead || behind != tt.wantBehind {
+				t.Errorf("Manager.AheadBehind() = (%d, %d), want (%d, %d)", ahead, behind, tt.wantAhead, tt.wantBehind)
+			}
+		})
+	}
+}
+
+func TestManager_LastCommit(t *testing.T) {
+	m := NewManager(&shell.MockExecutor{
+		ExecuteFunc: func(name string, args ...string) ([]byte, error) {
+			if args[0] == "-C" && args[1] == "/path/to/wt" && args[2] == "log" {
+				return []byte("1700000000\x00Fix the parser\n"), nil
+			}
+			return nil, fmt.Errorf("unexpected command")
+		},
+	})
+
+	when, subject, err := m.LastCommit("/path/to/wt")
+	if err != nil {
+		t.Fatalf("Manager.LastCommit() error = %v", err)
+	}
+	if !when.Equal(time.Unix(1700000000, 0)) {
+		t.Errorf("Manager.LastCommit() time = %v, want %v", when, time.Unix(1700000000, 0))
+	}
+	if subject != "Fix the parser" {
+		t.Errorf("Manager.LastCommit() subject = %q, want %q", subject, "Fix the parser")
+	}
+
+	if _, _, err := m.LastCommit("/path/to/other"); err == nil {
+		t.Error("Manager.LastCommit() expected error for failing git log")
+	}
+}
@@ -4,7 +4,10 @@ import (
 	"bufio"
 	"bytes"
 	"fmt"
+	"os"
 	"path/filepath"
+	"sort"
+	"strconv"
 	"strings"
 
 	"github.com/t98o84/gw/internal/errors"
@@ -16,14 +19,53 @@ type Worktree struct {
 	Path   string
 	Branch string
 	Commit string
+	// IsMain is true for the main worktree. In a bare repository layout,
+	// where there is no main working tree, it is set on the primary worktree instead.
 	IsMain bool
+	// IsBare is true for the bare repository entry of a bare repository layout
+	IsBare bool
+	// IsDetached is true when the worktree has a detached HEAD
+	IsDetached bool
+	// Locked is true when the worktree is locked with `git worktree lock`
+	Locked bool
+	// LockReason is the reason given when the worktree was locked (may be empty)
+	LockReason string
+	// Prunable is true when git considers the worktree stale (e.g. its directory is gone)
+	Prunable bool
+	// PrunableReason describes why the worktree is prunable (may be empty)
+	PrunableReason string
+}
+
+// Markers returns the state markers of the worktree, e.g. "(main)" or "(locked)"
+func (w *Worktree) Markers() []string {
+	var markers []string
+	if w.IsMain {
+		markers = append(markers, "(main)")
+	}
+	if w.IsBare {
+		markers = append(markers, "(bare)")
+	}
+	if w.Locked {
+		markers = append(markers, "(locked)")
+	}
+	if w.Prunable {
+		markers = append(markers, "(prunable)")
+	}
+	return markers
 }
 
 // Manager manages git operations with dependency injection
 type Manager struct {
 	executor shell.Executor
+	// pathTemplate is the template used by WorktreePath (empty means the default layout)
+	pathTemplate string
+	// remote is the remote used for remote branches (empty means DefaultRemoteName)
+	remote string
 }
 
+// DefaultRemoteName is the remote used when no default remote is configured
+const DefaultRemoteName = "origin"
+
 // NewManager creates a new Manager with the given executor
 func NewManager(executor shell.Executor) *Manager {
 	return &Manager{executor: executor}
@@ -32,6 +74,30 @@ func NewManager(executor shell.Executor) *Manager {
 // defaultManager is used for backward compatibility
 var defaultManager = NewManager(shell.NewRealExecutor())
 
+// SetDefaultRemote sets the remote used to look up and fetch remote branches,
+// the default branch and the remote URL. An empty name restores DefaultRemoteName.
+func (m *Manager) SetDefaultRemote(remote string) {
+	m.remote = remote
+}
+
+// SetDefaultRemote is a package-level wrapper for backward compatibility
+func SetDefaultRemote(remote string) {
+	defaultManager.SetDefaultRemote(remote)
+}
+
+// DefaultRemote returns the remote used for remote branches
+func (m *Manager) DefaultRemote() string {
+	if m.remote == "" {
+		return DefaultRemoteName
+	}
+	return m.remote
+}
+
+// DefaultRemote is a package-level wrapper for backward compatibility
+func DefaultRemote() string {
+	return defaultManager.DefaultRemote()
+}
+
 // GetRepoRoot returns the root directory of the git repository
 func (m *Manager) GetRepoRoot() (string, error) {
 	out, err := m.executor.Execute("git", "rev-parse", "--show-toplevel")
@@ -46,8 +112,16 @@ func GetRepoRoot() (string, error) {
 	return defaultManager.GetRepoRoot()
 }
 
-// GetRepoName returns the name of the repository (directory name)
+// GetRepoName returns the name of the repository.
+// The name is taken from the shared git directory so that it is the same in every worktree:
+// "/path/to/repo/.git" and "/path/to/repo/.bare" -> "repo", "/path/to/repo.git" -> "repo".
 func (m *Manager) GetRepoName() (string, error) {
+	if commonDir, err := m.GetCommonDir(); err == nil {
+		if name := repoNameFromCommonDir(commonDir); name != "" {
+			return name, nil
+		}
+	}
+
 	root, err := m.GetRepoRoot()
 	if err != nil {
 		return "", err
@@ -55,26 +129,78 @@ func (m *Manager) GetRepoName() (string, error) {
 	return filepath.Base(root), nil
 }
 
+// repoNameFromCommonDir derives the repository name from the shared git directory
+func repoNameFromCommonDir(commonDir string) string {
+	base := filepath.Base(commonDir)
+	switch {
+	case base == ".git" || base == ".bare":
+		base = filepath.Base(filepath.Dir(commonDir))
+	case strings.HasSuffix(base, ".git"):
+		base = strings.TrimSuffix(base, ".git")
+	}
+	if base == "." || base == string(filepath.Separator) {
+		return ""
+	}
+	return base
+}
+
 // GetRepoName is a package-level wrapper for backward compatibility
 func GetRepoName() (string, error) {
 	return defaultManager.GetRepoName()
 }
 
-// GetMainWorktreePath returns the path to the main (bare) worktree
-func (m *Manager) GetMainWorktreePath() (string, error) {
+// GetCommonDir returns the absolute path of the git directory shared by all worktrees
+func (m *Manager) GetCommonDir() (string, error) {
 	out, err := m.executor.Execute("git", "rev-parse", "--git-common-dir")
 	if err != nil {
 		return "", fmt.Errorf("failed to get git common dir: %w", err)
 	}
-	gitDir := strings.TrimSpace(string(out))
+	// The path is relative to the current directory when run from the main worktree
+	commonDir, err := filepath.Abs(strings.TrimSpace(string(out)))
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve git common dir: %w", err)
+	}
+	return commonDir, nil
+}
+
+// GetCommonDir is a package-level wrapper for backward compatibility
+func GetCommonDir() (string, error) {
+	return defaultManager.GetCommonDir()
+}
+
+// IsBareRepository reports whether the repository is a bare repository with linked worktrees
+func (m *Manager) IsBareRepository() bool {
+	out, err := m.executor.Execute("git", "config", "--bool", "core.bare")
+	return err == nil && strings.TrimSpace(string(out)) == "true"
+}
+
+// IsBareRepository is a package-level wrapper for backward compatibility
+func IsBareRepository() bool {
+	return defaultManager.IsBareRepository()
+}
+
+// GetMainWorktreePath returns the path to the main worktree.
+// In a bare repository layout this is the primary worktree (see List).
+func (m *Manager) GetMainWorktreePath() (string, error) {
+	gitDir, err := m.GetCommonDir()
+	if err != nil {
+		return "", err
+	}
 
-	// If it's a bare repo or main worktree, git-common-dir returns .git or the repo path
-	// We need to get the parent of .git
-	if filepath.Base(gitDir) == ".git" {
-		return filepath.Dir(gitDir), nil
+	if m.IsBareRepository() {
+		worktrees, err := m.List()
+		if err != nil {
+			return "", err
+		}
+		for _, wt := range worktrees {
+			if wt.IsMain {
+				return wt.Path, nil
+			}
+		}
+		return "", errors.NewInvalidInputError(gitDir, "bare repository has no primary worktree (add one with 'gw add' or set 'git config gw.primary <branch or path>')", nil)
 	}
 
-	// For worktrees, git-common-dir returns the path to the main repo's .git
+	// git-common-dir is the main worktree's .git directory, for the main worktree and linked worktrees alike
 	return filepath.Dir(gitDir), nil
 }
 
@@ -103,6 +229,16 @@ func (m *Manager) List() ([]Worktree, error) {
 			current.Commit = strings.TrimPrefix(line, "HEAD ")
 		case strings.HasPrefix(line, "branch "):
 			current.Branch = strings.TrimPrefix(line, "branch refs/heads/")
+		case line == "bare":
+			current.IsBare = true
+		case line == "detached":
+			current.IsDetached = true
+		case line == "locked" || strings.HasPrefix(line, "locked "):
+			current.Locked = true
+			current.LockReason = strings.TrimPrefix(strings.TrimPrefix(line, "locked"), " ")
+		case line == "prunable" || strings.HasPrefix(line, "prunable "):
+			current.Prunable = true
+			current.PrunableReason = strings.TrimPrefix(strings.TrimPrefix(line, "prunable"), " ")
 		case line == "":
 			if current.Path != "" {
 				worktrees = append(worktrees, current)
@@ -121,12 +257,48 @@ func (m *Manager) List() ([]Worktree, error) {
 
 	// Mark the main worktree
 	if len(worktrees) > 0 {
-		worktrees[0].IsMain = true
+		if worktrees[0].IsBare {
+			// A bare repository has no working tree, so a linked worktree stands in for it
+			if i := m.primaryWorktreeIndex(worktrees); i >= 0 {
+				worktrees[i].IsMain = true
+			}
+		} else {
+			worktrees[0].IsMain = true
+		}
 	}
 
 	return worktrees, nil
 }
 
+// primaryWorktreeIndex selects the primary worktree of a bare repository layout.
+// The worktree named by `git config gw.primary` (branch, directory name or path) wins,
+// followed by the worktree on the default branch. Returns -1 if neither exists:
+// any other worktree may be a feature worktree that must stay removable.
+func (m *Manager) primaryWorktreeIndex(worktrees []Worktree) int {
+	if out, err := m.executor.Execute("git", "config", "--get", "gw.primary"); err == nil {
+		if primary := strings.TrimSpace(string(out)); primary != "" {
+			for i, wt := range worktrees {
+				if wt.IsBare {
+					continue
+				}
+				if wt.Branch == primary || filepath.Base(wt.Path) == primary || filepath.Clean(wt.Path) == filepath.Clean(primary) {
+					return i
+				}
+			}
+		}
+	}
+
+	if defaultBranch, err := m.DefaultBranch(); err == nil {
+		defaultBranch = strings.TrimPrefix(defaultBranch, m.DefaultRemote()+"/")
+		for i, wt := range worktrees {
+			if !wt.IsBare && !wt.Prunable && wt.Branch == defaultBranch {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
 // List is a package-level wrapper for backward compatibility
 func List() ([]Worktree, error) {
 	return defaultManager.List()
@@ -156,6 +328,21 @@ func Add(path string, branch string, createBranch bool, from string) error {
 	return defaultManager.Add(path, branch, createBranch, from)
 }
 
+// AddDetached creates a new worktree with a detached HEAD at the given ref
+func (m *Manager) AddDetached(path string, ref string) error {
+	args := []string{"worktree", "add", "--detach", path, ref}
+	out, err := m.executor.Execute("git", args...)
+	if err != nil {
+		return errors.NewCommandExecutionError("git", args, out, err)
+	}
+	return nil
+}
+
+// AddDetached is a package-level wrapper for backward compatibility
+func AddDetached(path string, ref string) error {
+	return defaultManager.AddDetached(path, ref)
+}
+
 // Remove removes a worktree
 func (m *Manager) Remove(path string, force bool) error {
 	args := []string{"worktree", "remove"}
@@ -217,9 +404,9 @@ func BranchExists(branch string) (bool, error) {
 	return defaultManager.BranchExists(branch)
 }
 
-// RemoteBranchExists checks if a remote branch exists
-func (m *Manager) RemoteBranchExists(branch string) (bool, error) {
-	out, err := m.executor.Execute("git", "ls-remote", "--heads", "origin", branch)
+// RemoteBranchExists checks if a branch exists on the given remote
+func (m *Manager) RemoteBranchExists(remote string, branch string) (bool, error) {
+	out, err := m.executor.Execute("git", "ls-remote", "--heads", remote, branch)
 	if err != nil {
 		return false, err
 	}
@@ -227,67 +414,143 @@ func (m *Manager) RemoteBranchExists(branch string) (bool, error) {
 }
 
 // RemoteBranchExists is a package-level wrapper for backward compatibility
-func RemoteBranchExists(branch string) (bool, error) {
-	return defaultManager.RemoteBranchExists(branch)
+func RemoteBranchExists(remote string, branch string) (bool, error) {
+	return defaultManager.RemoteBranchExists(remote, branch)
 }
 
-// FetchBranch fetches a branch from origin
-func (m *Manager) FetchBranch(branch string) error {
-	args := []string{"fetch", "origin", branch + ":" + branch}
+// FetchBranch fetches a branch from the given remote and creates a local branch
+// of the same name that tracks it, so `git pull` and `git push` work right away
+func (m *Manager) FetchBranch(remote string, branch string) error {
+	// Fetch into the remote-tracking branch, which may not be covered by
+	// the remote's fetch refspec (e.g. in a bare clone)
+	args := []string{"fetch", remote, "+refs/heads/" + branch + ":refs/remotes/" + remote + "/" + branch}
 	out, err := m.executor.Execute("git", args...)
 	if err != nil {
 		return errors.NewCommandExecutionError("git", args, out, err)
 	}
+
+	args = []string{"branch", "--track", branch, remote + "/" + branch}
+	out, err = m.executor.Execute("git", args...)
+	if err != nil {
+		return errors.NewCommandExecutionError("git", args, out, err)
+	}
 	return nil
 }
 
 // FetchBranch is a package-level wrapper for backward compatibility
-func FetchBranch(branch string) error {
-	return defaultManager.FetchBranch(branch)
+func FetchBranch(remote string, branch string) error {
+	return defaultManager.FetchBranch(remote, branch)
 }
 
-// ListBranches returns all local and remote branches
+// ListRemotes returns the names of the configured remotes
+func (m *Manager) ListRemotes() ([]string, error) {
+	args := []string{"remote"}
+	out, err := m.executor.Execute("git", args...)
+	if err != nil {
+		return nil, errors.NewCommandExecutionError("git", args, out, err)
+	}
+
+	var remotes []string
+	for _, line := range strings.Split(string(out), "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			remotes = append(remotes, line)
+		}
+	}
+	return remotes, nil
+}
+
+// ListRemotes is a package-level wrapper for backward compatibility
+func ListRemotes() ([]string, error) {
+	return defaultManager.ListRemotes()
+}
+
+// SplitRemoteBranch splits a remote-qualified branch such as "upstream/feature/x"
+// into the remote and the branch name. ok is false when ref doesn't start with
+// one of the given remote names.
+func SplitRemoteBranch(ref string, remotes []string) (remote string, branch string, ok bool) {
+	for _, r := range remotes {
+		// Prefer the longest match in case remote names contain slashes
+		if strings.HasPrefix(ref, r+"/") && len(ref) > len(r)+1 && len(r) > len(remote) {
+			remote = r
+		}
+	}
+	if remote == "" {
+		return "", "", false
+	}
+	return remote, strings.TrimPrefix(ref, remote+"/"), true
+}
+
+// branchListFormat prints the committer date and the name of each branch
+const branchListFormat = "--format=%(committerdate:unix) %(refname:short)"
+
+// ListBranches returns the local branches and the remote branches that have no
+// local branch of the same name, most recently committed first. Remote branches
+// are qualified with their remote (e.g. "upstream/feature/x") so it is clear
+// where they come from.
 func (m *Manager) ListBranches() ([]string, error) {
 	// Get local branches
-	localOut, err := m.executor.Execute("git", "branch", "--format=%(refname:short)")
+	localOut, err := m.executor.Execute("git", "branch", branchListFormat)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list local branches: %w", err)
 	}
 
 	// Get remote branches
-	remoteOut, err := m.executor.Execute("git", "branch", "-r", "--format=%(refname:short)")
+	remoteOut, err := m.executor.Execute("git", "branch", "-r", branchListFormat)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list remote branches: %w", err)
 	}
 
+	type branchEntry struct {
+		name string
+		time int64
+	}
+	parse := func(line string) (branchEntry, bool) {
+		date, name, ok := strings.Cut(line, " ")
+		if !ok {
+			return branchEntry{}, false
+		}
+		// Symbolic refs such as origin/HEAD have no committer date of their own
+		t, _ := strconv.ParseInt(date, 10, 64)
+		return branchEntry{name: name, time: t}, true
+	}
+
 	branchSet := make(map[string]bool)
-	var branches []string
+	localBranches := make(map[string]bool)
+	var entries []branchEntry
 
 	// Add local branches
 	for _, line := range strings.Split(strings.TrimSpace(string(localOut)), "\n") {
-		if line != "" && !branchSet[line] {
-			branchSet[line] = true
-			branches = append(branches, line)
+		entry, ok := parse(line)
+		if ok && !branchSet[entry.name] {
+			branchSet[entry.name] = true
+			localBranches[entry.name] = true
+			entries = append(entries, entry)
 		}
 	}
 
-	// Add remote branches (strip origin/ prefix)
+	// Add remote branches that aren't checked out locally
 	for _, line := range strings.Split(strings.TrimSpace(string(remoteOut)), "\n") {
-		if line == "" {
+		entry, ok := parse(line)
+		// Skip HEAD pointers ("origin/HEAD -> origin/main", or just "origin" in newer git)
+		if !ok || strings.Contains(entry.name, "HEAD") || !strings.Contains(entry.name, "/") {
 			continue
 		}
-		// Skip HEAD pointer
-		if strings.Contains(line, "HEAD") {
+		_, name, _ := strings.Cut(entry.name, "/")
+		if localBranches[name] || branchSet[entry.name] {
 			continue
 		}
-		// Strip origin/ prefix
-		branch := strings.TrimPrefix(line, "origin/")
-		if !branchSet[branch] {
-			branchSet[branch] = true
-			branches = append(branches, branch)
-		}
+		branchSet[entry.name] = true
+		entries = append(entries, entry)
 	}
 
+	// Local branches stay ahead of remote branches with the same date
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].time > entries[j].time
+	})
+	branches := make([]string, len(entries))
+	for i, entry := range entries {
+		branches[i] = entry.name
+	}
 	return branches, nil
 }
 
@@ -422,3 +685,526 @@ func (m *Manager) GetIgnoredFiles(path string) ([]string, error) {
 func GetIgnoredFiles(path string) ([]string, error) {
 	return defaultManager.GetIgnoredFiles(path)
 }
+
+// DefaultBranch returns the default branch of the repository.
+// The default remote's HEAD (e.g. "origin/main") is preferred, falling back to a local main or master branch.
+func (m *Manager) DefaultBranch() (string, error) {
+	out, err := m.executor.Execute("git", "symbolic-ref", "--quiet", "--short", "refs/remotes/"+m.DefaultRemote()+"/HEAD")
+	if err == nil {
+		if ref := strings.TrimSpace(string(out)); ref != "" {
+			return ref, nil
+		}
+	}
+
+	for _, branch := range []string{"main", "master"} {
+		exists, err := m.BranchExists(branch)
+		if err != nil {
+			return "", err
+		}
+		if exists {
+			return branch, nil
+		}
+	}
+	return "", fmt.Errorf("failed to determine default branch")
+}
+
+// DefaultBranch is a package-level wrapper for backward compatibility
+func DefaultBranch() (string, error) {
+	return defaultManager.DefaultBranch()
+}
+
+// ResolveCommit returns the full commit hash the given ref points to
+func (m *Manager) ResolveCommit(ref string) (string, error) {
+	args := []string{"rev-parse", "--verify", "--quiet", ref + "^{commit}"}
+	out, err := m.executor.Execute("git", args...)
+	if err != nil {
+		return "", errors.NewCommandExecutionError("git", args, out, err)
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// ResolveCommit is a package-level wrapper for backward compatibility
+func ResolveCommit(ref string) (string, error) {
+	return defaultManager.ResolveCommit(ref)
+}
+
+// TagsAt returns the tags that point to the given commit
+func (m *Manager) TagsAt(commit string) ([]string, error) {
+	args := []string{"tag", "--points-at", commit}
+	out, err := m.executor.Execute("git", args...)
+	if err != nil {
+		return nil, errors.NewCommandExecutionError("git", args, out, err)
+	}
+
+	var tags []string
+	for _, line := range strings.Split(strings.TrimSpace(string(out)), "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			tags = append(tags, line)
+		}
+	}
+	return tags, nil
+}
+
+// TagsAt is a package-level wrapper for backward compatibility
+func TagsAt(commit string) ([]string, error) {
+	return defaultManager.TagsAt(commit)
+}
+
+// DetachedName returns the name used for a detached worktree at ref and the commit it points to.
+// Tags keep their name; any other ref is named by its short commit hash.
+func (m *Manager) DetachedName(ref string) (name string, commit string, err error) {
+	commit, err = m.ResolveCommit(ref)
+	if err != nil {
+		return "", "", err
+	}
+
+	if _, err := m.executor.Execute("git", "rev-parse", "--verify", "--quiet", "refs/tags/"+ref); err == nil {
+		return ref, commit, nil
+	}
+
+	args := []string{"rev-parse", "--short", commit}
+	out, err := m.executor.Execute("git", args...)
+	if err != nil {
+		return "", "", errors.NewCommandExecutionError("git", args, out, err)
+	}
+	return strings.TrimSpace(string(out)), commit, nil
+}
+
+// DetachedName is a package-level wrapper for backward compatibility
+func DetachedName(ref string) (string, string, error) {
+	return defaultManager.DetachedName(ref)
+}
+
+// MergedBranches returns the local branches that are merged into the given ref
+func (m *Manager) MergedBranches(target string) ([]string, error) {
+	args := []string{"branch", "--merged", target, "--format=%(refname:short)"}
+	out, err := m.executor.Execute("git", args...)
+	if err != nil {
+		return nil, errors.NewCommandExecutionError("git", args, out, err)
+	}
+
+	var branches []string
+	for _, line := range strings.Split(strings.TrimSpace(string(out)), "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			branches = append(branches, line)
+		}
+	}
+	return branches, nil
+}
+
+// MergedBranches is a package-level wrapper for backward compatibility
+func MergedBranches(target string) ([]string, error) {
+	return defaultManager.MergedBranches(target)
+}
+
+// GoneBranches returns the local branches whose upstream branch no longer exists on the remote
+func (m *Manager) GoneBranches() ([]string, error) {
+	args := []string{"for-each-ref", "--format=%(refname:short)%09%(upstream:track)", "refs/heads"}
+	out, err := m.executor.Execute("git", args...)
+	if err != nil {
+		return nil, errors.NewCommandExecutionError("git", args, out, err)
+	}
+
+	var branches []string
+	for _, line := range strings.Split(strings.TrimSpace(string(out)), "\n") {
+		branch, track, found := strings.Cut(line, "\t")
+		if found && track == "[gone]" {
+			branches = append(branches, branch)
+		}
+	}
+	return branches, nil
+}
+
+// GoneBranches is a package-level wrapper for backward compatibility
+func GoneBranches() ([]string, error) {
+	return defaultManager.GoneBranches()
+}
+
+// FetchAll fetches all remotes, optionally pruning deleted remote branches
+func (m *Manager) FetchAll(prune bool) error {
+	args := []string{"fetch", "--all"}
+	if prune {
+		args = append(args, "--prune")
+	}
+	out, err := m.executor.Execute("git", args...)
+	if err != nil {
+		return errors.NewCommandExecutionError("git", args, out, err)
+	}
+	return nil
+}
+
+// FetchAll is a package-level wrapper for backward compatibility
+func FetchAll(prune bool) error {
+	return defaultManager.FetchAll(prune)
+}
+
+// FastForward fast-forwards the branch checked out in the worktree at path to its upstream branch.
+// It fails if the branch has diverged from its upstream.
+func (m *Manager) FastForward(path string) error {
+	args := []string{"-C", path, "merge", "--ff-only", "--quiet", "@{upstream}"}
+	out, err := m.executor.Execute("git", args...)
+	if err != nil {
+		return errors.NewCommandExecutionError("git", args, out, err)
+	}
+	return nil
+}
+
+// FastForward is a package-level wrapper for backward compatibility
+func FastForward(path string) error {
+	return defaultManager.FastForward(path)
+}
+
+// Rebase rebases the branch checked out in the worktree at path onto the given ref.
+// When the rebase stops on conflicts, the worktree is left mid-rebase.
+func (m *Manager) Rebase(path string, onto string) error {
+	args := []string{"-C", path, "rebase", "--quiet", onto}
+	out, err := m.executor.Execute("git", args...)
+	if err != nil {
+		return errors.NewCommandExecutionError("git", args, out, err)
+	}
+	return nil
+}
+
+// Rebase is a package-level wrapper for backward compatibility
+func Rebase(path string, onto string) error {
+	return defaultManager.Rebase(path, onto)
+}
+
+// RebaseInProgress reports whether a rebase is in progress in the worktree at path
+func (m *Manager) RebaseInProgress(path string) (bool, error) {
+	args := []string{"-C", path, "rev-parse", "--git-path", "rebase-merge", "--git-path", "rebase-apply"}
+	out, err := m.executor.Execute("git", args...)
+	if err != nil {
+		return false, errors.NewCommandExecutionError("git", args, out, err)
+	}
+
+	for _, dir := range strings.Split(strings.TrimSpace(string(out)), "\n") {
+		if dir == "" {
+			continue
+		}
+		// --git-path is relative to the worktree unless the git directory is elsewhere
+		if !filepath.IsAbs(dir) {
+			dir = filepath.Join(path, dir)
+		}
+		if _, err := os.Stat(dir); err == nil {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// RebaseInProgress is a package-level wrapper for backward compatibility
+func RebaseInProgress(path string) (bool, error) {
+	return defaultManager.RebaseInProgress(path)
+}
+
+// IsDirty checks if the worktree at the given path has uncommitted or untracked changes
+func (m *Manager) IsDirty(path string) (bool, error) {
+	args := []string{"-C", path, "status", "--porcelain"}
+	out, err := m.executor.Execute("git", args...)
+	if err != nil {
+		return false, errors.NewCommandExecutionError("git", args, out, err)
+	}
+	return len(bytes.TrimSpace(out)) > 0, nil
+}
+
+// IsDirty is a package-level wrapper for backward compatibility
+func IsDirty(path string) (bool, error) {
+	return defaultManager.IsDirty(path)
+}
+
+// Lock locks a worktree so it can't be pruned, moved or removed
+func (m *Manager) Lock(path string, reason string) error {
+	args := []string{"worktree", "lock"}
+	if reason != "" {
+		args = append(args, "--reason", reason)
+	}
+	args = append(args, path)
+
+	out, err := m.executor.Execute("git", args...)
+	if err != nil {
+		return errors.NewCommandExecutionError("git", args, out, err)
+	}
+	return nil
+}
+
+// Lock is a package-level wrapper for backward compatibility
+func Lock(path string, reason string) error {
+	return defaultManager.Lock(path, reason)
+}
+
+// Unlock unlocks a locked worktree
+func (m *Manager) Unlock(path string) error {
+	args := []string{"worktree", "unlock", path}
+	out, err := m.executor.Execute("git", args...)
+	if err != nil {
+		return errors.NewCommandExecutionError("git", args, out, err)
+	}
+	return nil
+}
+
+// Unlock is a package-level wrapper for backward compatibility
+func Unlock(path string) error {
+	return defaultManager.Unlock(path)
+}
+
+// Move moves a worktree to a new location
+func (m *Manager) Move(path string, newPath string) error {
+	args := []string{"worktree", "move", path, newPath}
+	out, err := m.executor.Execute("git", args...)
+	if err != nil {
+		return errors.NewCommandExecutionError("git", args, out, err)
+	}
+	return nil
+}
+
+// Move is a package-level wrapper for backward compatibility
+func Move(path string, newPath string) error {
+	return defaultManager.Move(path, newPath)
+}
+
+// RenameBranch renames a local branch
+func (m *Manager) RenameBranch(oldName string, newName string) error {
+	args := []string{"branch", "-m", oldName, newName}
+	out, err := m.executor.Execute("git", args...)
+	if err != nil {
+		return errors.NewCommandExecutionError("git", args, out, err)
+	}
+	return nil
+}
+
+// RenameBranch is a package-level wrapper for backward compatibility
+func RenameBranch(oldName string, newName string) error {
+	return defaultManager.RenameBranch(oldName, newName)
+}
+
+// GetRemoteURL returns the URL of the given remote
+func (m *Manager) GetRemoteURL(remote string) (string, error) {
+	args := []string{"remote", "get-url", remote}
+	out, err := m.executor.Execute("git", args...)
+	if err != nil {
+		return "", errors.NewCommandExecutionError("git", args, out, err)
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// GetRemoteURL is a package-level wrapper for backward compatibility
+func GetRemoteURL(remote string) (string, error) {
+	return defaultManager.GetRemoteURL(remote)
+}
+
+// RemoteOwner extracts the owner (user or organization) from a remote URL
+// e.g., "git@github.com:owner/repo.git" or "https://example.com/owner/repo" -> "owner"
+// Returns an empty string if the URL has no owner segment.
+func RemoteOwner(url string) string {
+	url = strings.TrimSuffix(strings.TrimSuffix(url, "/"), ".git")
+	// Drop the scheme and host: "https://host/a/b", "ssh://git@host:22/a/b", "git@host:a/b"
+	if i := strings.Index(url, "://"); i >= 0 {
+		url = url[i+3:]
+		if j := strings.Index(url, "/"); j >= 0 {
+			url = url[j+1:]
+		} else {
+			return ""
+		}
+	} else if i := strings.Index(url, ":"); i >= 0 && !strings.HasPrefix(url, "/") {
+		url = url[i+1:]
+	}
+
+	segments := strings.Split(strings.Trim(url, "/"), "/")
+	if len(segments) < 2 {
+		return ""
+	}
+	return segments[len(segments)-2]
+}
+
+// RemoteRepoName extracts the repository name from a remote URL
+// e.g., "git@github.com:owner/repo.git" or "file:///srv/git/repo" -> "repo"
+func RemoteRepoName(url string) string {
+	url = strings.TrimSuffix(strings.TrimSuffix(url, "/"), ".git")
+	if i := strings.LastIndexAny(url, "/:\\"); i >= 0 {
+		url = url[i+1:]
+	}
+	return url
+}
+
+// CloneBare clones a repository as a bare repository into path
+func (m *Manager) CloneBare(url string, path string) error {
+	args := []string{"clone", "--bare", url, path}
+	out, err := m.executor.Execute("git", args...)
+	if err != nil {
+		return errors.NewCommandExecutionError("git", args, out, err)
+	}
+	return nil
+}
+
+// CloneBare is a package-level wrapper for backward compatibility
+func CloneBare(url string, path string) error {
+	return defaultManager.CloneBare(url, path)
+}
+
+// GetConfig returns a git config value, or an empty string if it is not set
+func (m *Manager) GetConfig(key string) (string, error) {
+	args := []string{"config", "--get", key}
+	out, err := m.executor.Execute("git", args...)
+	if err != nil {
+		// git config exits with 1 when the key is not set
+		type exitCoder interface {
+			ExitCode() int
+		}
+		if exitErr, ok := err.(exitCoder); ok && exitErr.ExitCode() == 1 {
+			return "", nil
+		}
+		return "", errors.NewCommandExecutionError("git", args, out, err)
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// GetConfig is a package-level wrapper for backward compatibility
+func GetConfig(key string) (string, error) {
+	return defaultManager.GetConfig(key)
+}
+
+// SetConfig sets a git config value in the repository
+func (m *Manager) SetConfig(key string, value string) error {
+	args := []string{"config", key, value}
+	out, err := m.executor.Execute("git", args...)
+	if err != nil {
+		return errors.NewCommandExecutionError("git", args, out, err)
+	}
+	return nil
+}
+
+// SetConfig is a package-level wrapper for backward compatibility
+func SetConfig(key string, value string) error {
+	return defaultManager.SetConfig(key, value)
+}
+
+// SetRemoteHead sets refs/remotes/<remote>/HEAD to the remote's default branch
+func (m *Manager) SetRemoteHead(remote string) error {
+	args := []string{"remote", "set-head", remote, "--auto"}
+	out, err := m.executor.Execute("git", args...)
+	if err != nil {
+		return errors.NewCommandExecutionError("git", args, out, err)
+	}
+	return nil
+}
+
+// SetRemoteHead is a package-level wrapper for backward compatibility
+func SetRemoteHead(remote string) error {
+	return defaultManager.SetRemoteHead(remote)
+}
+
+// SetUpstream sets the upstream branch of a local branch, e.g. "origin/main"
+func (m *Manager) SetUpstream(branch string, upstream string) error {
+	args := []string{"branch", "--set-upstream-to=" + upstream, branch}
+	out, err := m.executor.Execute("git", args...)
+	if err != nil {
+		return errors.NewCommandExecutionError("git", args, out, err)
+	}
+	return nil
+}
+
+// SetUpstream is a package-level wrapper for backward compatibility
+func SetUpstream(branch string, upstream string) error {
+	return defaultManager.SetUpstream(branch, upstream)
+}
+
+// SetPushUpstream configures branch to track the branch of the same name on remote.
+// If the remote-tracking branch exists, it becomes the upstream right away and
+// SetPushUpstream returns true. Otherwise no upstream is configured, since an
+// upstream that doesn't exist looks like a branch that was deleted on the remote;
+// only branch.<branch>.pushRemote is set, and the upstream is set when the branch
+// is first pushed with `git push -u`. No repository-wide push.* config is changed.
+func (m *Manager) SetPushUpstream(branch string, remote string) (bool, error) {
+	args := []string{"show-ref", "--verify", "--quiet", "refs/remotes/" + remote + "/" + branch}
+	out, err := m.executor.Execute("git", args...)
+	if err == nil {
+		return true, m.SetUpstream(branch, remote+"/"+branch)
+	}
+	type exitCoder interface {
+		ExitCode() int
+	}
+	if exitErr, ok := err.(exitCoder); !ok || exitErr.ExitCode() != 1 {
+		return false, errors.NewCommandExecutionError("git", args, out, err)
+	}
+
+	return false, m.SetConfig("branch."+branch+".pushRemote", remote)
+}
+
+// SetPushUpstream is a package-level wrapper for backward compatibility
+func SetPushUpstream(branch string, remote string) (bool, error) {
+	return defaultManager.SetPushUpstream(branch, remote)
+}
+
+// Repair repairs the links between the repository and the given worktrees
+// with `git worktree repair`, e.g. after the repository or worktrees were moved
+func (m *Manager) Repair(paths ...string) error {
+	args := append([]string{"worktree", "repair"}, paths...)
+	out, err := m.executor.Execute("git", args...)
+	if err != nil {
+		return errors.NewCommandExecutionError("git", args, out, err)
+	}
+	return nil
+}
+
+// Repair is a package-level wrapper for backward compatibility
+func Repair(paths ...string) error {
+	return defaultManager.Repair(paths...)
+}
+
+// AdminDir returns the administrative directory (<git-common-dir>/worktrees/<name>)
+// of the linked worktree registered at path
+func (m *Manager) AdminDir(path string) (string, error) {
+	commonDir, err := m.GetCommonDir()
+	if err != nil {
+		return "", err
+	}
+
+	worktreesDir := filepath.Join(commonDir, "worktrees")
+	entries, err := os.ReadDir(worktreesDir)
+	if err != nil {
+		return "", fmt.Errorf("failed to read %s: %w", worktreesDir, err)
+	}
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		adminDir := filepath.Join(worktreesDir, entry.Name())
+		data, err := os.ReadFile(filepath.Join(adminDir, "gitdir"))
+		if err != nil {
+			continue
+		}
+		// gitdir holds the path of the worktree's .git file
+		gitFile := strings.TrimSpace(string(data))
+		if !filepath.IsAbs(gitFile) {
+			gitFile = filepath.Join(adminDir, gitFile)
+		}
+		if filepath.Dir(filepath.Clean(gitFile)) == filepath.Clean(path) {
+			return adminDir, nil
+		}
+	}
+	return "", errors.NewWorktreeNotFoundError(path, nil)
+}
+
+// AdminDir is a package-level wrapper for backward compatibility
+func AdminDir(path string) (string, error) {
+	return defaultManager.AdminDir(path)
+}
+
+// LinkedGitDir returns the git directory that the .git file of the linked
+// worktree at path points to
+func LinkedGitDir(path string) (string, error) {
+	data, err := os.ReadFile(filepath.Join(path, ".git"))
+	if err != nil {
+		return "", fmt.Errorf("failed to read .git file: %w", err)
+	}
+	line := strings.TrimSpace(string(data))
+	if !strings.HasPrefix(line, "gitdir:") {
+		return "", errors.NewInvalidInputError(path, "not a linked worktree (.git is not a gitdir file)", nil)
+	}
+	gitDir := strings.TrimSpace(strings.TrimPrefix(line, "gitdir:"))
+	if !filepath.IsAbs(gitDir) {
+		gitDir = filepath.Join(path, gitDir)
+	}
+	return filepath.Clean(gitDir), nil
+}
@@ -2,7 +2,11 @@ package git
 
 import (
 	"fmt"
+	"os"
 	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/t98o84/gw/internal/shell"
@@ -51,6 +55,28 @@ func TestWorktree_Fields(t *testing.T) {
 	}
 }
 
+func TestWorktree_Markers(t *testing.T) {
+	tests := []struct {
+		name string
+		wt   Worktree
+		want []string
+	}{
+		{name: "plain worktree", wt: Worktree{Path: "/repo-feature"}, want: nil},
+		{name: "main worktree", wt: Worktree{Path: "/repo", IsMain: true}, want: []string{"(main)"}},
+		{name: "locked and prunable", wt: Worktree{Path: "/repo-old", Locked: true, Prunable: true}, want: []string{"(locked)", "(prunable)"}},
+		{name: "bare repository", wt: Worktree{Path: "/repo.git", IsBare: true}, want: []string{"(bare)"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.wt.Markers()
+			if strings.Join(got, " ") != strings.Join(tt.want, " ") {
+				t.Errorf("Worktree.Markers() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
 func TestManager_GetRepoRoot(t *testing.T) {
 	tests := []struct {
 		name    string
@@ -354,6 +380,27 @@ func TestManager_List(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "locked, prunable, detached and bare worktrees",
+			mock: &shell.MockExecutor{
+				ExecuteFunc: func(name string, args ...string) ([]byte, error) {
+					if name == "git" && args[0] == "worktree" {
+						return []byte("worktree /path/to/repo.git\nbare\n\n" +
+							"worktree /path/to/repo-locked\nHEAD abc123\nbranch refs/heads/hotfix\nlocked on removable disk\n\n" +
+							"worktree /path/to/repo-gone\nHEAD def456\nbranch refs/heads/old\nprunable gitdir file points to non-existent location\n\n" +
+							"worktree /path/to/repo-detached\nHEAD 789abc\ndetached\nlocked\n\n"), nil
+					}
+					return nil, fmt.Errorf("unexpected command")
+				},
+			},
+			want: []Worktree{
+				{Path: "/path/to/repo.git", IsBare: true},
+				{Path: "/path/to/repo-locked", Branch: "hotfix", Commit: "abc123", Locked: true, LockReason: "on removable disk"},
+				{Path: "/path/to/repo-gone", Branch: "old", Commit: "def456", Prunable: true, PrunableReason: "gitdir file points to non-existent location"},
+				{Path: "/path/to/repo-detached", Commit: "789abc", IsDetached: true, Locked: true},
+			},
+			wantErr: false,
+		},
 		{
 			name: "command fails",
 			mock: &shell.MockExecutor{
@@ -379,7 +426,7 @@ func TestManager_List(t *testing.T) {
 				return
 			}
 			for i := range got {
-				if got[i].Path != tt.want[i].Path || got[i].Branch != tt.want[i].Branch || got[i].Commit != tt.want[i].Commit || got[i].IsMain != tt.want[i].IsMain {
+				if got[i] != tt.want[i] {
 					t.Errorf("Manager.List()[%d] = %+v, want %+v", i, got[i], tt.want[i])
 				}
 			}
@@ -465,7 +512,7 @@ func TestManager_RemoteBranchExists(t *testing.T) {
 			branch: "main",
 			mock: &shell.MockExecutor{
 				ExecuteFunc: func(name string, args ...string) ([]byte, error) {
-					if name == "git" && args[0] == "ls-remote" {
+					if name == "git" && args[0] == "ls-remote" && args[2] == "origin" {
 						return []byte("abc123\trefs/heads/main\n"), nil
 					}
 					return nil, fmt.Errorf("unexpected command")
@@ -504,7 +551,7 @@ func TestManager_RemoteBranchExists(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			m := NewManager(tt.mock)
-			got, err := m.RemoteBranchExists(tt.branch)
+			got, err := m.RemoteBranchExists("origin", tt.branch)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("Manager.RemoteBranchExists() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -529,6 +576,146 @@ func (e *testExitError) ExitCode() int {
 	return e.exitCode
 }
 
+func TestManager_List_BarePrimary(t *testing.T) {
+	porcelain := "worktree /path/to/repo.git\nbare\n\n" +
+		"worktree /path/to/repo-feature\nHEAD abc123\nbranch refs/heads/feature/test\n\n" +
+		"worktree /path/to/repo-main\nHEAD def456\nbranch refs/heads/main\n\n" +
+		"worktree /path/to/repo-develop\nHEAD 789abc\nbranch refs/heads/develop\n\n"
+
+	tests := []struct {
+		name      string
+		primary   string
+		remoteRef string
+		hasMain   bool
+		want      string
+	}{
+		{name: "gw.primary by branch", primary: "develop", hasMain: true, want: "/path/to/repo-develop"},
+		{name: "gw.primary by directory name", primary: "repo-feature", hasMain: true, want: "/path/to/repo-feature"},
+		{name: "gw.primary by path", primary: "/path/to/repo-develop/", hasMain: true, want: "/path/to/repo-develop"},
+		{name: "default branch from origin/HEAD", remoteRef: "origin/develop", hasMain: true, want: "/path/to/repo-develop"},
+		{name: "local default branch", hasMain: true, want: "/path/to/repo-main"},
+		// Any other worktree may be a feature worktree, so none is the primary worktree
+		{name: "no gw.primary and no default branch", hasMain: false, want: ""},
+		{name: "unknown gw.primary falls back", primary: "nope", hasMain: true, want: "/path/to/repo-main"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := NewManager(&shell.MockExecutor{
+				ExecuteFunc: func(name string, args ...string) ([]byte, error) {
+					switch {
+					case args[0] == "worktree":
+						return []byte(porcelain), nil
+					case args[0] == "config" && args[2] == "gw.primary":
+						if tt.primary == "" {
+							return nil, &testExitError{exitCode: 1}
+						}
+						return []byte(tt.primary + "\n"), nil
+					case args[0] == "symbolic-ref":
+						if tt.remoteRef == "" {
+							return nil, &testExitError{exitCode: 1}
+						}
+						return []byte(tt.remoteRef + "\n"), nil
+					case args[0] == "show-ref" || args[0] == "rev-parse":
+						if tt.hasMain && strings.HasSuffix(args[len(args)-1], "/main") {
+							return []byte("def456\n"), nil
+						}
+						return nil, &testExitError{exitCode: 1}
+					}
+					return nil, fmt.Errorf("unexpected command: %v", args)
+				},
+			})
+
+			worktrees, err := m.List()
+			if err != nil {
+				t.Fatalf("Manager.List() error = %v", err)
+			}
+			var got []string
+			for _, wt := range worktrees {
+				if wt.IsMain {
+					got = append(got, wt.Path)
+				}
+			}
+			var want []string
+			if tt.want != "" {
+				want = []string{tt.want}
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("main worktrees = %v, want %v", got, want)
+			}
+			if worktrees[0].IsMain {
+				t.Error("the bare repository entry should not be the main worktree")
+			}
+		})
+	}
+}
+
+func TestManager_GetMainWorktreePath_Bare(t *testing.T) {
+	m := NewManager(&shell.MockExecutor{
+		ExecuteFunc: func(name string, args ...string) ([]byte, error) {
+			switch {
+			case args[0] == "rev-parse" && args[1] == "--git-common-dir":
+				return []byte("/path/to/repo.git\n"), nil
+			case args[0] == "config" && args[1] == "--bool":
+				return []byte("true\n"), nil
+			case args[0] == "config" && args[2] == "gw.primary":
+				return []byte("develop\n"), nil
+			case args[0] == "worktree":
+				return []byte("worktree /path/to/repo.git\nbare\n\nworktree /path/to/repo-develop\nHEAD 789abc\nbranch refs/heads/develop\n\n"), nil
+			}
+			return nil, fmt.Errorf("unexpected command: %v", args)
+		},
+	})
+
+	got, err := m.GetMainWorktreePath()
+	if err != nil {
+		t.Fatalf("Manager.GetMainWorktreePath() error = %v", err)
+	}
+	if got != "/path/to/repo-develop" {
+		t.Errorf("Manager.GetMainWorktreePath() = %v, want /path/to/repo-develop", got)
+	}
+}
+
+func TestManager_GetMainWorktreePath_BareWithoutWorktrees(t *testing.T) {
+	m := NewManager(&shell.MockExecutor{
+		ExecuteFunc: func(name string, args ...string) ([]byte, error) {
+			switch {
+			case args[0] == "rev-parse" && args[1] == "--git-common-dir":
+				return []byte("/path/to/repo.git\n"), nil
+			case args[0] == "config" && args[1] == "--bool":
+				return []byte("true\n"), nil
+			case args[0] == "worktree":
+				return []byte("worktree /path/to/repo.git\nbare\n\n"), nil
+			}
+			return nil, &testExitError{exitCode: 1}
+		},
+	})
+
+	if _, err := m.GetMainWorktreePath(); err == nil {
+		t.Error("Manager.GetMainWorktreePath() error = nil, want error for a bare repository without worktrees")
+	}
+}
+
+func TestRepoNameFromCommonDir(t *testing.T) {
+	tests := []struct {
+		commonDir string
+		want      string
+	}{
+		{"/path/to/repo/.git", "repo"},
+		{"/path/to/repo/.bare", "repo"},
+		{"/path/to/repo.git", "repo"},
+		{"/path/to/repo", "repo"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.commonDir, func(t *testing.T) {
+			if got := repoNameFromCommonDir(tt.commonDir); got != tt.want {
+				t.Errorf("repoNameFromCommonDir(%q) = %q, want %q", tt.commonDir, got, tt.want)
+			}
+		})
+	}
+}
+
 func TestManager_GetMainWorktreePath(t *testing.T) {
 	tests := []struct {
 		name    string
@@ -699,6 +886,24 @@ func TestManager_Add(t *testing.T) {
 	}
 }
 
+func TestManager_AddDetached(t *testing.T) {
+	var got []string
+	m := NewManager(&shell.MockExecutor{
+		ExecuteFunc: func(name string, args ...string) ([]byte, error) {
+			got = args
+			return []byte(""), nil
+		},
+	})
+
+	if err := m.AddDetached("/path/to/worktree", "v1.2.0"); err != nil {
+		t.Fatalf("Manager.AddDetached() error = %v", err)
+	}
+	want := "worktree add --detach /path/to/worktree v1.2.0"
+	if strings.Join(got, " ") != want {
+		t.Errorf("Manager.AddDetached() ran git %s, want git %s", strings.Join(got, " "), want)
+	}
+}
+
 func TestManager_Remove(t *testing.T) {
 	tests := []struct {
 		name    string
@@ -766,16 +971,38 @@ func TestManager_Remove(t *testing.T) {
 func TestManager_FetchBranch(t *testing.T) {
 	tests := []struct {
 		name    string
+		remote  string
 		branch  string
 		mock    *shell.MockExecutor
 		wantErr bool
 	}{
 		{
 			name:   "fetch success",
+			remote: "origin",
 			branch: "feature/test",
 			mock: &shell.MockExecutor{
 				ExecuteFunc: func(name string, args ...string) ([]byte, error) {
-					if name == "git" && args[0] == "fetch" && args[1] == "origin" && args[2] == "feature/test:feature/test" {
+					if name == "git" && args[0] == "fetch" && args[1] == "origin" && args[2] == "+refs/heads/feature/test:refs/remotes/origin/feature/test" {
+						return []byte(""), nil
+					}
+					if name == "git" && args[0] == "branch" && args[1] == "--track" && args[2] == "feature/test" && args[3] == "origin/feature/test" {
+						return []byte(""), nil
+					}
+					return nil, fmt.Errorf("unexpected command")
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name:   "fetch from another remote",
+			remote: "upstream",
+			branch: "feature/test",
+			mock: &shell.MockExecutor{
+				ExecuteFunc: func(name string, args ...string) ([]byte, error) {
+					if name == "git" && args[0] == "fetch" && args[1] == "upstream" && args[2] == "+refs/heads/feature/test:refs/remotes/upstream/feature/test" {
+						return []byte(""), nil
+					}
+					if name == "git" && args[0] == "branch" && args[1] == "--track" && args[2] == "feature/test" && args[3] == "upstream/feature/test" {
 						return []byte(""), nil
 					}
 					return nil, fmt.Errorf("unexpected command")
@@ -785,6 +1012,7 @@ func TestManager_FetchBranch(t *testing.T) {
 		},
 		{
 			name:   "fetch fails",
+			remote: "origin",
 			branch: "feature/test",
 			mock: &shell.MockExecutor{
 				ExecuteFunc: func(name string, args ...string) ([]byte, error) {
@@ -793,12 +1021,26 @@ func TestManager_FetchBranch(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name:   "branch creation fails",
+			remote: "origin",
+			branch: "feature/test",
+			mock: &shell.MockExecutor{
+				ExecuteFunc: func(name string, args ...string) ([]byte, error) {
+					if args[0] == "fetch" {
+						return []byte(""), nil
+					}
+					return nil, fmt.Errorf("branch error")
+				},
+			},
+			wantErr: true,
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			m := NewManager(tt.mock)
-			err := m.FetchBranch(tt.branch)
+			err := m.FetchBranch(tt.remote, tt.branch)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("Manager.FetchBranch() error = %v, wantErr %v", err, tt.wantErr)
 			}
@@ -818,19 +1060,19 @@ func TestManager_ListBranches(t *testing.T) {
 			mock: &shell.MockExecutor{
 				ExecuteFunc: func(name string, args ...string) ([]byte, error) {
 					if name == "git" && args[0] == "branch" {
-						if len(args) == 2 && args[1] == "--format=%(refname:short)" {
+						if len(args) == 2 && args[1] == branchListFormat {
 							// Local branches
-							return []byte("main\nfeature/test\n"), nil
+							return []byte("1700000300 main\n1700000100 feature/test\n"), nil
 						}
 						if len(args) == 3 && args[1] == "-r" {
 							// Remote branches
-							return []byte("origin/main\norigin/feature/remote\norigin/HEAD -> origin/main\n"), nil
+							return []byte("1700000300 origin/main\n1700000200 origin/feature/remote\n1700000300 origin/HEAD -> origin/main\n1700000300 origin\n1700000000 upstream/main\n1700000400 upstream/feature/remote\n1700000000 upstream/feature/up\n"), nil
 						}
 					}
 					return nil, fmt.Errorf("unexpected command")
 				},
 			},
-			want:    []string{"main", "feature/test", "feature/remote"},
+			want:    []string{"upstream/feature/remote", "main", "origin/feature/remote", "feature/test", "upstream/feature/up"},
 			wantErr: false,
 		},
 		{
@@ -852,7 +1094,7 @@ func TestManager_ListBranches(t *testing.T) {
 				ExecuteFunc: func(name string, args ...string) ([]byte, error) {
 					if name == "git" && args[0] == "branch" {
 						if len(args) == 2 {
-							return []byte("main\n"), nil
+							return []byte("1700000000 main\n"), nil
 						}
 						if len(args) == 3 && args[1] == "-r" {
 							return nil, fmt.Errorf("git error")
@@ -889,6 +1131,117 @@ func TestManager_ListBranches(t *testing.T) {
 	}
 }
 
+func TestManager_SetPushUpstream(t *testing.T) {
+	tests := []struct {
+		name         string
+		remoteExists bool
+		wantSet      bool
+		want         [][]string
+	}{
+		{
+			name:         "remote-tracking branch exists",
+			remoteExists: true,
+			wantSet:      true,
+			want: [][]string{
+				{"show-ref", "--verify", "--quiet", "refs/remotes/upstream/feature/new"},
+				{"branch", "--set-upstream-to=upstream/feature/new", "feature/new"},
+			},
+		},
+		{
+			// An upstream that doesn't exist yet would look like a deleted one
+			name: "branch not pushed yet",
+			want: [][]string{
+				{"show-ref", "--verify", "--quiet", "refs/remotes/upstream/feature/new"},
+				{"config", "branch.feature/new.pushRemote", "upstream"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got [][]string
+			m := NewManager(&shell.MockExecutor{
+				ExecuteFunc: func(name string, args ...string) ([]byte, error) {
+					got = append(got, args)
+					if args[0] == "show-ref" && !tt.remoteExists {
+						return nil, &testExitError{exitCode: 1}
+					}
+					return nil, nil
+				},
+			})
+
+			set, err := m.SetPushUpstream("feature/new", "upstream")
+			if err != nil {
+				t.Fatalf("Manager.SetPushUpstream() error = %v", err)
+			}
+			if set != tt.wantSet {
+				t.Errorf("Manager.SetPushUpstream() = %v, want %v", set, tt.wantSet)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Manager.SetPushUpstream() ran %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestManager_ListRemotes(t *testing.T) {
+	m := NewManager(&shell.MockExecutor{
+		ExecuteFunc: func(name string, args ...string) ([]byte, error) {
+			if len(args) == 1 && args[0] == "remote" {
+				return []byte("origin\nupstream\n"), nil
+			}
+			return nil, fmt.Errorf("unexpected command")
+		},
+	})
+
+	got, err := m.ListRemotes()
+	if err != nil {
+		t.Fatalf("Manager.ListRemotes() error = %v", err)
+	}
+	if len(got) != 2 || got[0] != "origin" || got[1] != "upstream" {
+		t.Errorf("Manager.ListRemotes() = %v, want [origin upstream]", got)
+	}
+}
+
+func TestManager_DefaultRemote(t *testing.T) {
+	m := NewManager(&shell.MockExecutor{})
+	if got := m.DefaultRemote(); got != "origin" {
+		t.Errorf("Manager.DefaultRemote() = %q, want %q", got, "origin")
+	}
+	m.SetDefaultRemote("upstream")
+	if got := m.DefaultRemote(); got != "upstream" {
+		t.Errorf("Manager.DefaultRemote() = %q, want %q", got, "upstream")
+	}
+}
+
+func TestSplitRemoteBranch(t *testing.T) {
+	remotes := []string{"origin", "upstream", "team/fork"}
+
+	tests := []struct {
+		ref        string
+		wantRemote string
+		wantBranch string
+		wantOK     bool
+	}{
+		{ref: "upstream/feature/x", wantRemote: "upstream", wantBranch: "feature/x", wantOK: true},
+		{ref: "origin/main", wantRemote: "origin", wantBranch: "main", wantOK: true},
+		{ref: "team/fork/feature", wantRemote: "team/fork", wantBranch: "feature", wantOK: true},
+		{ref: "feature/x"},
+		{ref: "upstream/"},
+		{ref: "upstream"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.ref, func(t *testing.T) {
+			remote, branch, ok := SplitRemoteBranch(tt.ref, remotes)
+			if ok != tt.wantOK || remote != tt.wantRemote || branch != tt.wantBranch {
+				t.Errorf("SplitRemoteBranch(%q) = (%q, %q, %v), want (%q, %q, %v)",
+					tt.ref, remote, branch, ok, tt.wantRemote, tt.wantBranch, tt.wantOK)
+			}
+		})
+	}
+}
+
 func TestManager_Exists(t *testing.T) {
 	tests := []struct {
 		name         string
@@ -991,3 +1344,543 @@ func TestManager_Exists(t *testing.T) {
 		})
 	}
 }
+
+func TestManager_DefaultBranch(t *testing.T) {
+	tests := []struct {
+		name    string
+		remote  string
+		mock    *shell.MockExecutor
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "remote HEAD is set",
+			mock: &shell.MockExecutor{
+				ExecuteFunc: func(name string, args ...string) ([]byte, error) {
+					if args[0] == "symbolic-ref" {
+						return []byte("origin/main\n"), nil
+					}
+					return nil, fmt.Errorf("unexpected command")
+				},
+			},
+			want: "origin/main",
+		},
+		{
+			name:   "remote HEAD of the default remote",
+			remote: "upstream",
+			mock: &shell.MockExecutor{
+				ExecuteFunc: func(name string, args ...string) ([]byte, error) {
+					if args[0] == "symbolic-ref" && args[len(args)-1] == "refs/remotes/upstream/HEAD" {
+						return []byte("upstream/develop\n"), nil
+					}
+					return nil, fmt.Errorf("unexpected command")
+				},
+			},
+			want: "upstream/develop",
+		},
+		{
+			name: "falls back to local master",
+			mock: &shell.MockExecutor{
+				ExecuteFunc: func(name string, args ...string) ([]byte, error) {
+					if args[0] == "symbolic-ref" {
+						return nil, &testExitError{exitCode: 1}
+					}
+					if args[0] == "show-ref" && args[len(args)-1] == "refs/heads/master" {
+						return nil, nil
+					}
+					if args[0] == "show-ref" {
+						return nil, &testExitError{exitCode: 1}
+					}
+					return nil, fmt.Errorf("unexpected command")
+				},
+			},
+			want: "master",
+		},
+		{
+			name: "no default branch",
+			mock: &shell.MockExecutor{
+				ExecuteFunc: func(name string, args ...string) ([]byte, error) {
+					return nil, &testExitError{exitCode: 1}
+				},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := NewManager(tt.mock)
+			m.SetDefaultRemote(tt.remote)
+			got, err := m.DefaultBranch()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Manager.DefaultBranch() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("Manager.DefaultBranch() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestManager_FastForward(t *testing.T) {
+	var got []string
+	m := NewManager(&shell.MockExecutor{
+		ExecuteFunc: func(name string, args ...string) ([]byte, error) {
+			got = args
+			if args[1] == "/path/to/diverged" {
+				return []byte("fatal: Not possible to fast-forward, aborting."), fmt.Errorf("exit status 128")
+			}
+			return []byte(""), nil
+		},
+	})
+
+	if err := m.FastForward("/path/to/wt"); err != nil {
+		t.Fatalf("Manager.FastForward() error = %v", err)
+	}
+	want := "-C /path/to/wt merge --ff-only --quiet @{upstream}"
+	if strings.Join(got, " ") != want {
+		t.Errorf("Manager.FastForward() ran git %s, want git %s", strings.Join(got, " "), want)
+	}
+
+	if err := m.FastForward("/path/to/diverged"); err == nil {
+		t.Error("Manager.FastForward() expected error for a diverged branch")
+	}
+}
+
+func TestManager_Rebase(t *testing.T) {
+	var got []string
+	m := NewManager(&shell.MockExecutor{
+		ExecuteFunc: func(name string, args ...string) ([]byte, error) {
+			got = args
+			return []byte(""), nil
+		},
+	})
+
+	if err := m.Rebase("/path/to/wt", "origin/main"); err != nil {
+		t.Fatalf("Manager.Rebase() error = %v", err)
+	}
+	want := "-C /path/to/wt rebase --quiet origin/main"
+	if strings.Join(got, " ") != want {
+		t.Errorf("Manager.Rebase() ran git %s, want git %s", strings.Join(got, " "), want)
+	}
+}
+
+func TestManager_RebaseInProgress(t *testing.T) {
+	wt := t.TempDir()
+	gitDir := t.TempDir()
+	m := NewManager(&shell.MockExecutor{
+		ExecuteFunc: func(name string, args ...string) ([]byte, error) {
+			if args[0] == "-C" && args[2] == "rev-parse" && args[3] == "--git-path" {
+				if args[1] == wt {
+					// Main worktree: paths are relative to the worktree
+					return []byte(".git/rebase-merge\n.git/rebase-apply\n"), nil
+				}
+				return []byte(filepath.Join(gitDir, "rebase-merge") + "\n" + filepath.Join(gitDir, "rebase-apply") + "\n"), nil
+			}
+			return nil, fmt.Errorf("unexpected command")
+		},
+	})
+
+	for _, path := range []string{wt, "/path/to/linked"} {
+		got, err := m.RebaseInProgress(path)
+		if err != nil {
+			t.Fatalf("Manager.RebaseInProgress(%s) error = %v", path, err)
+		}
+		if got {
+			t.Errorf("Manager.RebaseInProgress(%s) = true, want false", path)
+		}
+	}
+
+	if err := os.MkdirAll(filepath.Join(wt, ".git", "rebase-merge"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Join(gitDir, "rebase-apply"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	for _, path := range []string{wt, "/path/to/linked"} {
+		got, err := m.RebaseInProgress(path)
+		if err != nil {
+			t.Fatalf("Manager.RebaseInProgress(%s) error = %v", path, err)
+		}
+		if !got {
+			t.Errorf("Manager.RebaseInProgress(%s) = false, want true", path)
+		}
+	}
+}
+
+func TestManager_TagsAt(t *testing.T) {
+	m := NewManager(&shell.MockExecutor{
+		ExecuteFunc: func(name string, args ...string) ([]byte, error) {
+			if args[0] == "tag" && args[1] == "--points-at" && args[2] == "abc123" {
+				return []byte("v1.2.0\nlatest\n"), nil
+			}
+			return []byte(""), nil
+		},
+	})
+
+	got, err := m.TagsAt("abc123")
+	if err != nil {
+		t.Fatalf("Manager.TagsAt() error = %v", err)
+	}
+	if strings.Join(got, ",") != "v1.2.0,latest" {
+		t.Errorf("Manager.TagsAt() = %v, want [v1.2.0 latest]", got)
+	}
+
+	got, err = m.TagsAt("def456")
+	if err != nil {
+		t.Fatalf("Manager.TagsAt() error = %v", err)
+	}
+	if len(got) != 0 {
+		t.Errorf("Manager.TagsAt() = %v, want none", got)
+	}
+}
+
+func TestManager_DetachedName(t *testing.T) {
+	const commit = "1a2b3c4d5e6f7a8b9c0d"
+	tests := []struct {
+		name     string
+		ref      string
+		wantName string
+		wantErr  bool
+	}{
+		{name: "tag", ref: "v1.2.0", wantName: "v1.2.0"},
+		{name: "commit", ref: "1a2b3c4d", wantName: "1a2b3c4"},
+		{name: "branch", ref: "origin/main", wantName: "1a2b3c4"},
+		{name: "unknown ref", ref: "nope", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := NewManager(&shell.MockExecutor{
+				ExecuteFunc: func(name string, args ...string) ([]byte, error) {
+					switch strings.Join(args, " ") {
+					case "rev-parse --verify --quiet v1.2.0^{commit}",
+						"rev-parse --verify --quiet 1a2b3c4d^{commit}",
+						"rev-parse --verify --quiet origin/main^{commit}":
+						return []byte(commit + "\n"), nil
+					case "rev-parse --verify --quiet refs/tags/v1.2.0":
+						return []byte("0f0f0f\n"), nil
+					case "rev-parse --short " + commit:
+						return []byte("1a2b3c4\n"), nil
+					}
+					return nil, &testExitError{exitCode: 1}
+				},
+			})
+
+			gotName, gotCommit, err := m.DetachedName(tt.ref)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Manager.DetachedName() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if gotName != tt.wantName || gotCommit != commit {
+				t.Errorf("Manager.DetachedName() = (%q, %q), want (%q, %q)", gotName, gotCommit, tt.wantName, commit)
+			}
+		})
+	}
+}
+
+func TestManager_MergedBranches(t *testing.T) {
+	m := NewManager(&shell.MockExecutor{
+		ExecuteFunc: func(name string, args ...string) ([]byte, error) {
+			if args[0] == "branch" && args[1] == "--merged" && args[2] == "origin/main" {
+				return []byte("main\nfeature/done\n"), nil
+			}
+			return nil, fmt.Errorf("unexpected command")
+		},
+	})
+
+	got, err := m.MergedBranches("origin/main")
+	if err != nil {
+		t.Fatalf("Manager.MergedBranches() error = %v", err)
+	}
+	if strings.Join(got, ",") != "main,feature/done" {
+		t.Errorf("Manager.MergedBranches() = %v, want [main feature/done]", got)
+	}
+}
+
+func TestManager_GoneBranches(t *testing.T) {
+	m := NewManager(&shell.MockExecutor{
+		ExecuteFunc: func(name string, args ...string) ([]byte, error) {
+			if args[0] == "for-each-ref" {
+				return []byte("main\t\nfeature/a\t[gone]\nfeature/b\t[ahead 1]\nlocal-only\t\n"), nil
+			}
+			return nil, fmt.Errorf("unexpected command")
+		},
+	})
+
+	got, err := m.GoneBranches()
+	if err != nil {
+		t.Fatalf("Manager.GoneBranches() error = %v", err)
+	}
+	if strings.Join(got, ",") != "feature/a" {
+		t.Errorf("Manager.GoneBranches() = %v, want [feature/a]", got)
+	}
+}
+
+func TestManager_IsDirty(t *testing.T) {
+	tests := []struct {
+		name    string
+		output  string
+		err     error
+		want    bool
+		wantErr bool
+	}{
+		{name: "clean", output: "", want: false},
+		{name: "modified file", output: " M main.go\n", want: true},
+		{name: "untracked file", output: "?? new.txt\n", want: true},
+		{name: "status fails", err: fmt.Errorf("git error"), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := NewManager(&shell.MockExecutor{
+				ExecuteFunc: func(name string, args ...string) ([]byte, error) {
+					if args[0] == "-C" && args[1] == "/path/to/wt" && args[2] == "status" {
+						return []byte(tt.output), tt.err
+					}
+					return nil, fmt.Errorf("unexpected command")
+				},
+			})
+			got, err := m.IsDirty("/path/to/wt")
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Manager.IsDirty() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("Manager.IsDirty() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestManager_GetConfig(t *testing.T) {
+	tests := []struct {
+		name    string
+		output  string
+		err     error
+		want    string
+		wantErr bool
+	}{
+		{name: "value set", output: "develop\n", want: "develop"},
+		{name: "key not set", err: &testExitError{exitCode: 1}, want: ""},
+		{name: "config fails", err: &testExitError{exitCode: 128}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := NewManager(&shell.MockExecutor{
+				ExecuteFunc: func(name string, args ...string) ([]byte, error) {
+					if len(args) == 3 && args[0] == "config" && args[1] == "--get" && args[2] == "gw.primary" {
+						return []byte(tt.output), tt.err
+					}
+					return nil, fmt.Errorf("unexpected command")
+				},
+			})
+			got, err := m.GetConfig("gw.primary")
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Manager.GetConfig() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("Manager.GetConfig() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestManager_LockUnlock(t *testing.T) {
+	var calls [][]string
+	m := NewManager(&shell.MockExecutor{
+		ExecuteFunc: func(name string, args ...string) ([]byte, error) {
+			calls = append(calls, args)
+			return nil, nil
+		},
+	})
+
+	if err := m.Lock("/path/to/wt", "on external disk"); err != nil {
+		t.Fatalf("Manager.Lock() error = %v", err)
+	}
+	if err := m.Lock("/path/to/wt", ""); err != nil {
+		t.Fatalf("Manager.Lock() error = %v", err)
+	}
+	if err := m.Unlock("/path/to/wt"); err != nil {
+		t.Fatalf("Manager.Unlock() error = %v", err)
+	}
+
+	want := []string{
+		"worktree lock --reason on external disk /path/to/wt",
+		"worktree lock /path/to/wt",
+		"worktree unlock /path/to/wt",
+	}
+	if len(calls) != len(want) {
+		t.Fatalf("got %d git calls, want %d", len(calls), len(want))
+	}
+	for i := range want {
+		if got := strings.Join(calls[i], " "); got != want[i] {
+			t.Errorf("call %d = %q, want %q", i, got, want[i])
+		}
+	}
+}
+
+func TestManager_Lock_Error(t *testing.T) {
+	m := NewManager(&shell.MockExecutor{
+		ExecuteFunc: func(name string, args ...string) ([]byte, error) {
+			return []byte("fatal: 'wt' is already locked"), fmt.Errorf("exit status 128")
+		},
+	})
+
+	err := m.Lock("/path/to/wt", "")
+	if err == nil {
+		t.Fatal("Manager.Lock() expected error")
+	}
+	if !strings.Contains(err.Error(), "already locked") {
+		t.Errorf("Manager.Lock() error = %v, want git output included", err)
+	}
+}
+
+func TestManager_MoveAndRenameBranch(t *testing.T) {
+	var calls []string
+	m := NewManager(&shell.MockExecutor{
+		ExecuteFunc: func(name string, args ...string) ([]byte, error) {
+			calls = append(calls, strings.Join(args, " "))
+			return nil, nil
+		},
+	})
+
+	if err := m.RenameBranch("feature/old", "feature/new"); err != nil {
+		t.Fatalf("Manager.RenameBranch() error = %v", err)
+	}
+	if err := m.Move("/repo-feature-old", "/repo-feature-new"); err != nil {
+		t.Fatalf("Manager.Move() error = %v", err)
+	}
+
+	want := []string{
+		"branch -m feature/old feature/new",
+		"worktree move /repo-feature-old /repo-feature-new",
+	}
+	if strings.Join(calls, "\n") != strings.Join(want, "\n") {
+		t.Errorf("git calls = %q, want %q", calls, want)
+	}
+}
+
+func TestManager_AdminDir(t *testing.T) {
+	commonDir := t.TempDir()
+	adminDir := filepath.Join(commonDir, "worktrees", "ex-repo-feature")
+	if err := os.MkdirAll(adminDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(adminDir, "gitdir"), []byte("/path/to/ex-repo-feature/.git\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	m := NewManager(&shell.MockExecutor{
+		ExecuteFunc: func(name string, args ...string) ([]byte, error) {
+			if args[0] == "rev-parse" && args[1] == "--git-common-dir" {
+				return []byte(commonDir + "\n"), nil
+			}
+			return nil, fmt.Errorf("unexpected command")
+		},
+	})
+
+	got, err := m.AdminDir("/path/to/ex-repo-feature")
+	if err != nil {
+		t.Fatalf("Manager.AdminDir() error = %v", err)
+	}
+	if got != adminDir {
+		t.Errorf("Manager.AdminDir() = %q, want %q", got, adminDir)
+	}
+
+	if _, err := m.AdminDir("/path/to/unknown"); err == nil {
+		t.Error("Manager.AdminDir() error = nil, want error for an unregistered worktree")
+	}
+}
+
+func TestLinkedGitDir(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    string
+		wantErr bool
+	}{
+		{name: "absolute path", content: "gitdir: /repo/.git/worktrees/feature\n", want: "/repo/.git/worktrees/feature"},
+		{name: "relative path", content: "gitdir: ../repo/.git/worktrees/feature\n", want: "repo/.git/worktrees/feature"},
+		{name: "not a gitdir file", content: "something else\n", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			base := t.TempDir()
+			path := filepath.Join(base, "wt")
+			if err := os.MkdirAll(path, 0755); err != nil {
+				t.Fatal(err)
+			}
+			if err := os.WriteFile(filepath.Join(path, ".git"), []byte(tt.content), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := LinkedGitDir(path)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("LinkedGitDir() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if !filepath.IsAbs(tt.want) {
+				tt.want = filepath.Join(base, tt.want)
+			}
+			if got != tt.want {
+				t.Errorf("LinkedGitDir() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	if _, err := LinkedGitDir(t.TempDir()); err == nil {
+		t.Error("LinkedGitDir() error = nil, want error without a .git file")
+	}
+}
+
+func TestRemoteOwner(t *testing.T) {
+	tests := []struct {
+		url  string
+		want string
+	}{
+		{"git@github.com:t98o84/gw.git", "t98o84"},
+		{"https://github.com/t98o84/gw.git", "t98o84"},
+		{"https://github.com/t98o84/gw", "t98o84"},
+		{"ssh://git@gitlab.example.com:2222/group/sub/gw.git", "sub"},
+		{"/srv/git/team/gw.git", "team"},
+		{"https://example.com/gw.git", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.url, func(t *testing.T) {
+			if got := RemoteOwner(tt.url); got != tt.want {
+				t.Errorf("RemoteOwner(%q) = %q, want %q", tt.url, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRemoteRepoName(t *testing.T) {
+	tests := []struct {
+		url  string
+		want string
+	}{
+		{"git@github.com:t98o84/gw.git", "gw"},
+		{"https://github.com/t98o84/gw", "gw"},
+		{"https://github.com/t98o84/gw.git/", "gw"},
+		{"file:///srv/git/gw", "gw"},
+		{"../gw.git", "gw"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.url, func(t *testing.T) {
+			if got := RemoteRepoName(tt.url); got != tt.want {
+				t.Errorf("RemoteRepoName(%q) = %q, want %q", tt.url, got, tt.want)
+			}
+		})
+	}
+}
@@ -14,9 +14,10 @@ import (
 	"golang.org/x/oauth2"
 )
 
-// GetPRBranch extracts the branch name from a PR number or URL
-func GetPRBranch(prIdentifier string, repoName string) (string, error) {
-	prNumber, owner, repo, err := parsePRIdentifier(prIdentifier, repoName)
+// GetPRBranch extracts the branch name from a PR number or URL.
+// PR numbers are looked up in the repository of the given remote.
+func GetPRBranch(prIdentifier string, repoName string, remote string) (string, error) {
+	prNumber, owner, repo, err := parsePRIdentifier(prIdentifier, repoName, remote)
 	if err != nil {
 		return "", err
 	}
@@ -40,7 +41,7 @@ func GetPRBranch(prIdentifier string, repoName string) (string, error) {
 }
 
 // parsePRIdentifier parses a PR number or URL and returns PR number, owner, and repo
-func parsePRIdentifier(identifier string, defaultRepo string) (int, string, string, error) {
+func parsePRIdentifier(identifier string, defaultRepo string, remote string) (int, string, string, error) {
 	// Try to parse as URL
 	// Formats:
 	//   https://github.com/owner/repo/pull/123
@@ -55,7 +56,7 @@ func parsePRIdentifier(identifier string, defaultRepo string) (int, string, stri
 	prNum, err := strconv.Atoi(identifier)
 	if err == nil {
 		// Need to get owner/repo from git remote
-		owner, repo, err := getRemoteOwnerRepo()
+		owner, repo, err := getRemoteOwnerRepo(remote)
 		if err != nil {
 			return 0, "", "", err
 		}
@@ -65,16 +66,16 @@ func parsePRIdentifier(identifier string, defaultRepo string) (int, string, stri
 	return 0, "", "", errors.NewInvalidInputError(identifier, "invalid PR identifier (use PR number or URL)", nil)
 }
 
-// getRemoteOwnerRepo extracts owner and repo from git remote origin
-func getRemoteOwnerRepo() (string, string, error) {
-	return getRemoteOwnerRepoWithExecutor(shell.NewRealExecutor())
+// getRemoteOwnerRepo extracts owner and repo from the URL of the given git remote
+func getRemoteOwnerRepo(remote string) (string, string, error) {
+	return getRemoteOwnerRepoWithExecutor(shell.NewRealExecutor(), remote)
 }
 
-// getRemoteOwnerRepoWithExecutor extracts owner and repo from git remote origin using provided executor
-func getRemoteOwnerRepoWithExecutor(executor shell.Executor) (string, string, error) {
-	out, err := executor.Execute("git", "remote", "get-url", "origin")
+// getRemoteOwnerRepoWithExecutor extracts owner and repo from the URL of the given git remote using provided executor
+func getRemoteOwnerRepoWithExecutor(executor shell.Executor, remote string) (string, string, error) {
+	out, err := executor.Execute("git", "remote", "get-url", remote)
 	if err != nil {
-		return "", "", errors.NewCommandExecutionError("git", []string{"remote", "get-url", "origin"}, out, err)
+		return "", "", errors.NewCommandExecutionError("git", []string{"remote", "get-url", remote}, out, err)
 	}
 
 	url := strings.TrimSpace(string(out))
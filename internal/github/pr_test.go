@@ -2,6 +2,7 @@ package github
 
 import (
 	"context"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"os"
@@ -11,6 +12,7 @@ import (
 	"testing"
 
 	"github.com/google/go-github/v66/github"
+	"github.com/t98o84/gw/internal/shell"
 )
 
 func TestParsePRIdentifier_URL(t *testing.T) {
@@ -54,7 +56,7 @@ func TestParsePRIdentifier_URL(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			prNum, owner, repo, err := parsePRIdentifier(tt.identifier, tt.defaultRepo)
+			prNum, owner, repo, err := parsePRIdentifier(tt.identifier, tt.defaultRepo, "origin")
 			if (err != nil) != tt.wantErr {
 				t.Errorf("parsePRIdentifier() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -95,7 +97,7 @@ func TestParsePRIdentifier_InvalidInput(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			_, _, _, err := parsePRIdentifier(tt.identifier, "")
+			_, _, _, err := parsePRIdentifier(tt.identifier, "", "origin")
 			if err == nil {
 				t.Errorf("parsePRIdentifier(%q) expected error, got nil", tt.identifier)
 			}
@@ -148,6 +150,47 @@ func TestGetRemoteOwnerRepo_ParseFormats(t *testing.T) {
 	}
 }
 
+func TestGetRemoteOwnerRepoWithExecutor_Remote(t *testing.T) {
+	mock := &shell.MockExecutor{
+		ExecuteFunc: func(name string, args ...string) ([]byte, error) {
+			if name == "git" && len(args) == 3 && args[0] == "remote" && args[1] == "get-url" {
+				switch args[2] {
+				case "origin":
+					return []byte("git@github.com:me/gw.git\n"), nil
+				case "upstream":
+					return []byte("https://github.com/t98o84/gw.git\n"), nil
+				}
+			}
+			return nil, fmt.Errorf("unexpected command")
+		},
+	}
+
+	tests := []struct {
+		remote    string
+		wantOwner string
+		wantErr   bool
+	}{
+		{remote: "origin", wantOwner: "me"},
+		{remote: "upstream", wantOwner: "t98o84"},
+		{remote: "fork", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.remote, func(t *testing.T) {
+			owner, repo, err := getRemoteOwnerRepoWithExecutor(mock, tt.remote)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("getRemoteOwnerRepoWithExecutor() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if owner != tt.wantOwner || repo != "gw" {
+				t.Errorf("getRemoteOwnerRepoWithExecutor() = (%q, %q), want (%q, %q)", owner, repo, tt.wantOwner, "gw")
+			}
+		})
+	}
+}
+
 // TestGetRemoteOwnerRepo_Integration tests getRemoteOwnerRepo with actual git command
 // This test runs in the actual repository and validates the parsing logic
 func TestGetRemoteOwnerRepo_Integration(t *testing.T) {
@@ -156,7 +199,7 @@ func TestGetRemoteOwnerRepo_Integration(t *testing.T) {
 		t.Skip("Not in a git repository, skipping integration test")
 	}
 
-	owner, repo, err := getRemoteOwnerRepo()
+	owner, repo, err := getRemoteOwnerRepo("origin")
 	if err != nil {
 		t.Fatalf("getRemoteOwnerRepo() error = %v", err)
 	}
@@ -333,7 +376,7 @@ func TestParsePRIdentifier_WithPRNumber(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			prNum, owner, repo, err := parsePRIdentifier(tt.identifier, "")
+			prNum, owner, repo, err := parsePRIdentifier(tt.identifier, "", "origin")
 			if (err != nil) != tt.wantErr {
 				t.Errorf("parsePRIdentifier() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -379,7 +422,7 @@ func TestGetPRBranch_ErrorCases(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			// This will fail at parsePRIdentifier stage
-			_, err := GetPRBranch(tt.prIdentifier, tt.repoName)
+			_, err := GetPRBranch(tt.prIdentifier, tt.repoName, "origin")
 			if (err != nil) != tt.wantErr {
 				t.Errorf("GetPRBranch() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -492,7 +535,7 @@ func TestGetRemoteOwnerRepo_ErrorHandling(t *testing.T) {
 		}
 
 		// Try to get remote from non-git directory
-		_, _, err = getRemoteOwnerRepo()
+		_, _, err = getRemoteOwnerRepo("origin")
 		if err == nil {
 			t.Error("getRemoteOwnerRepo() should return error in non-git directory")
 		}
@@ -503,7 +546,7 @@ func TestGetRemoteOwnerRepo_ErrorHandling(t *testing.T) {
 func BenchmarkParsePRIdentifier_URL(b *testing.B) {
 	identifier := "https://github.com/owner/repo/pull/123"
 	for i := 0; i < b.N; i++ {
-		_, _, _, _ = parsePRIdentifier(identifier, "")
+		_, _, _, _ = parsePRIdentifier(identifier, "", "origin")
 	}
 }
 
@@ -515,7 +558,7 @@ func BenchmarkParsePRIdentifier_Number(b *testing.B) {
 
 	identifier := "123"
 	for i := 0; i < b.N; i++ {
-		_, _, _, _ = parsePRIdentifier(identifier, "")
+		_, _, _, _ = parsePRIdentifier(identifier, "", "origin")
 	}
 }
 
@@ -562,7 +605,7 @@ func TestParsePRIdentifier_EdgeCases(t *testing.T) {
 				}
 			}
 
-			_, _, _, err := parsePRIdentifier(tt.identifier, "")
+			_, _, _, err := parsePRIdentifier(tt.identifier, "", "origin")
 			_ = err // Explicitly ignore for edge case testing
 			if (err != nil) != tt.wantErr {
 				t.Errorf("parsePRIdentifier() error = %v, wantErr %v", err, tt.wantErr)
@@ -0,0 +1,260 @@
+// Package metadata records information about worktrees that git doesn't keep,
+// such as the branch a worktree was created from and when it was last used.
+package metadata
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// fileVersion is the version of the metadata file format
+const fileVersion = 1
+
+const (
+	// lockTimeout is how long to wait for another process to release the store
+	lockTimeout = 5 * time.Second
+	// lockRetryInterval is how often the lock is retried while waiting
+	lockRetryInterval = 10 * time.Millisecond
+	// lockStaleAge is the age after which a lock file is considered abandoned
+	lockStaleAge = 30 * time.Second
+)
+
+// Worktree is the metadata recorded for a worktree
+type Worktree struct {
+	// Branch is the branch the worktree was created for (empty when detached)
+	Branch string `json:"branch,omitempty"`
+	// Base is the ref the branch was created from, if gw created the branch
+	Base string `json:"base,omitempty"`
+	// PR is the pull request number or URL the worktree was created from
+	PR string `json:"pr,omitempty"`
+	// CreatedAt is nil for worktrees that were not created by gw
+	CreatedAt  *time.Time `json:"created_at,omitempty"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+}
+
+// file is the content of the metadata file
+type file struct {
+	Version int `json:"version"`
+	// Worktrees is keyed by the absolute path of the worktree
+	Worktrees map[string]Worktree `json:"worktrees"`
+}
+
+// Store is the metadata file of a repository, shared by all of its worktrees
+type Store struct {
+	path string
+}
+
+// Open returns the store kept in the git common directory commonDir.
+// The file is created on the first update.
+func Open(commonDir string) *Store {
+	return &Store{path: filepath.Join(commonDir, "gw", "worktrees.json")}
+}
+
+// Path returns the path of the metadata file
+func (s *Store) Path() string {
+	return s.path
+}
+
+// Load returns the metadata of all worktrees keyed by worktree path
+func (s *Store) Load() (map[string]Worktree, error) {
+	f, err := s.read()
+	if err != nil {
+		return nil, err
+	}
+	return f.Worktrees, nil
+}
+
+// Get returns the metadata recorded for the worktree at path, or nil if there is none
+func (s *Store) Get(path string) (*Worktree, error) {
+	f, err := s.read()
+	if err != nil {
+		return nil, err
+	}
+	wt, ok := f.Worktrees[Key(path)]
+	if !ok {
+		return nil, nil
+	}
+	return &wt, nil
+}
+
+// Update applies update to the metadata of the worktree at path and saves the store.
+// A new entry is created if there is none yet.
+func (s *Store) Update(path string, update func(wt *Worktree)) error {
+	return s.modify(func(f *file) bool {
+		key := Key(path)
+		wt := f.Worktrees[key]
+		update(&wt)
+		f.Worktrees[key] = wt
+		return true
+	})
+}
+
+// Delete removes the metadata of the worktree at path
+func (s *Store) Delete(path string) error {
+	return s.modify(func(f *file) bool {
+		key := Key(path)
+		if _, ok := f.Worktrees[key]; !ok {
+			return false
+		}
+		delete(f.Worktrees, key)
+		return true
+	})
+}
+
+// Move moves the metadata of the worktree at oldPath to newPath
+func (s *Store) Move(oldPath, newPath string) error {
+	return s.modify(func(f *file) bool {
+		oldKey := Key(oldPath)
+		wt, ok := f.Worktrees[oldKey]
+		if !ok {
+			return false
+		}
+		delete(f.Worktrees, oldKey)
+		f.Worktrees[Key(newPath)] = wt
+		return true
+	})
+}
+
+// Retain removes the metadata of all worktrees whose path is not in paths
+func (s *Store) Retain(paths []string) error {
+	return s.modify(func(f *file) bool {
+		keep := make(map[string]bool, len(paths))
+		for _, path := range paths {
+			keep[Key(path)] = true
+		}
+		removed := false
+		for key := range f.Worktrees {
+			if !keep[key] {
+				delete(f.Worktrees, key)
+				removed = true
+			}
+		}
+		return removed
+	})
+}
+
+// Key returns the key the metadata of the worktree at path is stored under.
+// Symlinks are resolved so the same worktree always maps to the same key, even
+// when its directory no longer exists.
+func Key(path string) string {
+	path = filepath.Clean(path)
+	if resolved, err := filepath.EvalSymlinks(path); err == nil {
+		return resolved
+	}
+	if dir, err := filepath.EvalSymlinks(filepath.Dir(path)); err == nil {
+		return filepath.Join(dir, filepath.Base(path))
+	}
+	return path
+}
+
+// modify reads the metadata file, applies change and saves the file if change
+// reports that it changed anything. The store is locked meanwhile so that
+// concurrent gw processes don't overwrite each other's changes.
+func (s *Store) modify(change func(f *file) bool) error {
+	unlock, err := s.lock()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	f, err := s.read()
+	if err != nil {
+		return err
+	}
+	if !change(f) {
+		return nil
+	}
+	return s.write(f)
+}
+
+// lock creates the lock file of the store, waiting up to lockTimeout for
+// another process to release it. A lock file older than lockStaleAge was left
+// behind by a process that crashed and is removed.
+func (s *Store) lock() (func(), error) {
+	if err := os.MkdirAll(filepath.Dir(s.path), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create metadata directory: %w", err)
+	}
+
+	lockPath := s.path + ".lock"
+	deadline := time.Now().Add(lockTimeout)
+	for {
+		lockFile, err := os.OpenFile(lockPath, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0644)
+		if err == nil {
+			lockFile.Close()
+			return func() { os.Remove(lockPath) }, nil
+		}
+		if !os.IsExist(err) {
+			return nil, fmt.Errorf("failed to lock worktree metadata: %w", err)
+		}
+
+		if info, err := os.Stat(lockPath); err == nil && time.Since(info.ModTime()) > lockStaleAge {
+			os.Remove(lockPath)
+			continue
+		}
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("failed to lock worktree metadata: %s is held by another process", lockPath)
+		}
+		time.Sleep(lockRetryInterval)
+	}
+}
+
+// read reads the metadata file. A missing file is an empty store.
+func (s *Store) read() (*file, error) {
+	f := &file{Version: fileVersion, Worktrees: make(map[string]Worktree)}
+
+	data, err := os.ReadFile(s.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return f, nil
+		}
+		return nil, fmt.Errorf("failed to read worktree metadata: %w", err)
+	}
+	if err := json.Unmarshal(data, f); err != nil {
+		return nil, fmt.Errorf("failed to parse worktree metadata %s: %w", s.path, err)
+	}
+	if f.Version > fileVersion {
+		return nil, fmt.Errorf("worktree metadata %s has version %d, this gw supports up to %d", s.path, f.Version, fileVersion)
+	}
+	if f.Worktrees == nil {
+		f.Worktrees = make(map[string]Worktree)
+	}
+	return f, nil
+}
+
+// write saves the metadata file. It is written to a temporary file first so
+// that concurrent readers never see a partially written file.
+func (s *Store) write(f *file) error {
+	f.Version = fileVersion
+	data, err := json.MarshalIndent(f, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal worktree metadata: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(s.path), 0755); err != nil {
+		return fmt.Errorf("failed to create metadata directory: %w", err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), "worktrees-*.json")
+	if err != nil {
+		return fmt.Errorf("failed to write worktree metadata: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if err := tmp.Chmod(0644); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write worktree metadata: %w", err)
+	}
+	if _, err := tmp.Write(append(data, '\n')); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write worktree metadata: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write worktree metadata: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), s.path); err != nil {
+		return fmt.Errorf("failed to write worktree metadata: %w", err)
+	}
+	return nil
+}
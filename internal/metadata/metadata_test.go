@@ -0,0 +1,226 @@
+package metadata
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestStore_Update(t *testing.T) {
+	store := Open(t.TempDir())
+	path := filepath.Join(t.TempDir(), "repo-feature")
+	created := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	if err := store.Update(path, func(wt *Worktree) {
+		wt.Branch = "feature"
+		wt.Base = "origin/main"
+		wt.CreatedAt = &created
+	}); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+
+	used := created.Add(time.Hour)
+	if err := store.Update(path, func(wt *Worktree) {
+		wt.LastUsedAt = &used
+	}); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+
+	got, err := store.Get(path)
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if got == nil {
+		t.Fatal("Get() = nil, want the recorded metadata")
+	}
+	if got.Branch != "feature" || got.Base != "origin/main" {
+		t.Errorf("Get() = %+v, want branch feature and base origin/main", got)
+	}
+	if got.CreatedAt == nil || !got.CreatedAt.Equal(created) {
+		t.Errorf("Get().CreatedAt = %v, want %v", got.CreatedAt, created)
+	}
+	if got.LastUsedAt == nil || !got.LastUsedAt.Equal(used) {
+		t.Errorf("Get().LastUsedAt = %v, want %v", got.LastUsedAt, used)
+	}
+}
+
+func TestStore_Get_Missing(t *testing.T) {
+	store := Open(t.TempDir())
+
+	got, err := store.Get("/path/to/repo-feature")
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if got != nil {
+		t.Errorf("Get() = %+v, want nil without a metadata file", got)
+	}
+}
+
+func TestStore_DeleteAndMove(t *testing.T) {
+	store := Open(t.TempDir())
+	for _, branch := range []string{"a", "b"} {
+		if err := store.Update("/path/to/repo-"+branch, func(wt *Worktree) { wt.Branch = branch }); err != nil {
+			t.Fatalf("Update() error = %v", err)
+		}
+	}
+
+	if err := store.Delete("/path/to/repo-a"); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if err := store.Move("/path/to/repo-b", "/path/to/repo-c"); err != nil {
+		t.Fatalf("Move() error = %v", err)
+	}
+	// Unknown worktrees are ignored
+	if err := store.Delete("/path/to/repo-x"); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+
+	worktrees, err := store.Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if len(worktrees) != 1 || worktrees["/path/to/repo-c"].Branch != "b" {
+		t.Errorf("Load() = %+v, want only repo-c with branch b", worktrees)
+	}
+}
+
+func TestStore_Retain(t *testing.T) {
+	store := Open(t.TempDir())
+	for _, branch := range []string{"a", "b", "c"} {
+		if err := store.Update("/path/to/repo-"+branch, func(wt *Worktree) { wt.Branch = branch }); err != nil {
+			t.Fatalf("Update() error = %v", err)
+		}
+	}
+
+	if err := store.Retain([]string{"/path/to/repo-b", "/path/to/repo-x"}); err != nil {
+		t.Fatalf("Retain() error = %v", err)
+	}
+
+	worktrees, err := store.Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if len(worktrees) != 1 || worktrees["/path/to/repo-b"].Branch != "b" {
+		t.Errorf("Load() = %+v, want only repo-b with branch b", worktrees)
+	}
+}
+
+func TestStore_Update_Concurrent(t *testing.T) {
+	commonDir := t.TempDir()
+
+	// Each goroutine uses its own store like separate gw processes do
+	const n = 20
+	var wg sync.WaitGroup
+	errs := make(chan error, n)
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			path := fmt.Sprintf("/path/to/repo-%d", i)
+			errs <- Open(commonDir).Update(path, func(wt *Worktree) { wt.Branch = "feature" })
+		}(i)
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Fatalf("Update() error = %v", err)
+		}
+	}
+
+	worktrees, err := Open(commonDir).Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if len(worktrees) != n {
+		t.Errorf("Load() returned %d worktrees, want %d", len(worktrees), n)
+	}
+}
+
+func TestStore_Lock(t *testing.T) {
+	store := Open(t.TempDir())
+	lockPath := store.Path() + ".lock"
+	if err := os.MkdirAll(filepath.Dir(lockPath), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	// A lock left behind by a crashed process is taken over
+	if err := os.WriteFile(lockPath, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	stale := time.Now().Add(-2 * lockStaleAge)
+	if err := os.Chtimes(lockPath, stale, stale); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Update("/path/to/repo", func(wt *Worktree) {}); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	if _, err := os.Stat(lockPath); !os.IsNotExist(err) {
+		t.Errorf("lock file still exists after Update(): %v", err)
+	}
+}
+
+func TestStore_Read_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+	}{
+		{name: "invalid JSON", content: "{"},
+		{name: "newer version", content: `{"version": 99, "worktrees": {}}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := Open(t.TempDir())
+			if err := os.MkdirAll(filepath.Dir(store.Path()), 0755); err != nil {
+				t.Fatal(err)
+			}
+			if err := os.WriteFile(store.Path(), []byte(tt.content), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			if _, err := store.Load(); err == nil {
+				t.Error("Load() expected an error")
+			}
+			// A broken file is not overwritten
+			if err := store.Update("/path/to/repo", func(wt *Worktree) {}); err == nil {
+				t.Error("Update() expected an error")
+			}
+		})
+	}
+}
+
+func TestKey(t *testing.T) {
+	dir, err := filepath.EvalSymlinks(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Mkdir(filepath.Join(dir, "real"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(filepath.Join(dir, "real"), filepath.Join(dir, "link")); err != nil {
+		t.Skipf("symlinks not supported: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		path string
+		want string
+	}{
+		{name: "existing directory", path: filepath.Join(dir, "real"), want: filepath.Join(dir, "real")},
+		{name: "symlink", path: filepath.Join(dir, "link") + "/", want: filepath.Join(dir, "real")},
+		{name: "removed directory under a symlink", path: filepath.Join(dir, "link", "gone"), want: filepath.Join(dir, "real", "gone")},
+		{name: "missing parent", path: "/nonexistent/parent/../repo", want: "/nonexistent/repo"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Key(tt.path); got != tt.want {
+				t.Errorf("Key(%q) = %q, want %q", tt.path, got, tt.want)
+			}
+		})
+	}
+}